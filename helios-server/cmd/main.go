@@ -4,7 +4,6 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"log"
 	"net/http"
 	"os"
@@ -12,17 +11,14 @@ import (
 	"syscall"
 	"time"
 
-	"nfcunha/helios/core/models"
 	"nfcunha/helios/core/repository"
 	"nfcunha/helios/core/service"
 	"nfcunha/helios/database"
 	"nfcunha/helios/handler"
 	"nfcunha/helios/utils/config"
 	"nfcunha/helios/utils/docker"
-	"nfcunha/helios/utils/statsutil"
+	"nfcunha/helios/utils/metrics"
 
-	"github.com/docker/docker/api/types"
-	"github.com/docker/docker/api/types/container"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
@@ -48,7 +44,7 @@ func main() {
 	log.Println("Database initialized successfully")
 
 	// Initialize Docker client
-	dockerClient, err := docker.NewClient()
+	dockerClient, err := docker.NewClient(cfg.Docker.MaxConcurrentInspects)
 	if err != nil {
 		log.Fatalf("Failed to initialize Docker client: %v", err)
 	}
@@ -65,17 +61,58 @@ func main() {
 	// Create repository instances
 	healthCheckRepo := repository.NewHealthCheckLogRepository(database.GetDB())
 	actionLogRepo := repository.NewActionLogRepository(database.GetDB())
+	eventLogRepo := repository.NewEventLogRepository(database.GetDB())
+	drainRepo := repository.NewDrainRepository(database.GetDB())
+	volumeUsageRepo := repository.NewVolumeUsageRepository(database.GetDB())
+	templateRepo := repository.NewTemplateRepository(database.GetDB())
+
+	// Shared Docker event subscription for internal consumers
+	eventBus := service.NewEventBus(dockerClient, eventLogRepo)
+	defer eventBus.Stop()
 
 	// Create service instances
-	containerService := service.NewContainerService(dockerClient, actionLogRepo)
-	logService := service.NewLogService(dockerClient)
-	imageService := service.NewImageService(dockerClient, actionLogRepo)
-	volumeService := service.NewVolumeService(dockerClient, actionLogRepo)
-	networkService := service.NewNetworkService(dockerClient, actionLogRepo)
-
-	// Start health checker if enabled
-	if cfg.HealthCheck.Enabled {
-		go startHealthChecker(dockerClient, healthCheckRepo, &cfg.HealthCheck)
+	pruneProtection := service.NewPruneProtection(cfg.PruneProtect)
+	webhookNotifier := service.NewWebhookNotifier(cfg.Webhook)
+	containerScope := service.NewContainerScope(cfg.Docker.ContainerNamePrefix)
+	resourceLabels := service.NewResourceLabels(cfg.Docker)
+	registryCredentials, err := service.NewRegistryCredentials(cfg.Registry)
+	if err != nil {
+		log.Fatalf("Failed to load registry credentials: %v", err)
+	}
+	containerService := service.NewContainerService(dockerClient, actionLogRepo, containerScope, registryCredentials, resourceLabels, cfg.Docker, cfg.Stats, cfg.Exec, cfg.Bulk)
+	logService := service.NewLogService(dockerClient, actionLogRepo, eventBus, containerScope, cfg.Logs)
+	imageService := service.NewImageService(dockerClient, actionLogRepo, pruneProtection, containerScope, registryCredentials, cfg.Build)
+	volumeService := service.NewVolumeService(dockerClient, actionLogRepo, volumeUsageRepo, pruneProtection, resourceLabels)
+	networkService := service.NewNetworkService(dockerClient, actionLogRepo, pruneProtection, containerScope, resourceLabels, cfg.Network)
+	diskService := service.NewDiskService(dockerClient, actionLogRepo)
+
+	// Start health checker; periodic passes only run when enabled
+	containerEvents, unsubscribe := eventBus.Subscribe(64)
+	defer unsubscribe()
+	healthChecker := service.NewHealthChecker(dockerClient, healthCheckRepo, eventLogRepo, webhookNotifier, containerScope, cfg.HealthCheck, containerEvents)
+	defer healthChecker.Stop()
+
+	// Record significant Docker events, including changes made outside Helios
+	eventRecorder := service.NewDockerEventRecorder(eventBus, eventLogRepo, containerScope)
+	defer eventRecorder.Stop()
+
+	// Start log retention cleanup
+	retentionCleaner := service.NewRetentionCleaner(healthCheckRepo, actionLogRepo, eventLogRepo, database.Vacuum, cfg.LogRetention)
+	defer retentionCleaner.Stop()
+
+	// Start automatic prune scheduler if enabled
+	if cfg.AutoPrune.Enabled {
+		pruneScheduler := service.NewPruneScheduler(dockerClient, diskService, eventLogRepo, cfg.AutoPrune)
+		defer pruneScheduler.Stop()
+	}
+
+	// Start config file watcher if enabled
+	if cfg.ConfigWatch.Enabled {
+		configWatcher, err := service.NewConfigWatcher(dockerClient, containerService, eventLogRepo, containerScope, cfg.ConfigWatch)
+		if err != nil {
+			log.Fatalf("Failed to start config watcher: %v", err)
+		}
+		defer configWatcher.Stop()
 	}
 
 	// Set Gin mode
@@ -87,6 +124,10 @@ func main() {
 		log.Println("Running in DEBUG mode")
 	}
 
+	// Apply configured handler timeouts
+	handler.ConfigureTimeouts(cfg.Timeouts)
+	handler.ConfigureWebSocket(cfg.WebSocket)
+
 	// Create Gin engine
 	engine := gin.New()
 	engine.Use(gin.Recovery())
@@ -97,37 +138,178 @@ func main() {
 	// Add CORS middleware
 	engine.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
 
+	// Reject mutating requests when running read-only
+	engine.Use(handler.ReadOnly(cfg.Server.ReadOnly))
+
 	// Basic health endpoint for Phase 1
 	helios := engine.Group("/helios")
 	{
 		helios.GET("/health", func(c *gin.Context) {
 			c.JSON(http.StatusOK, gin.H{
-				"status": "healthy",
+				"status":         "healthy",
+				"time":           time.Now(),
+				"active_streams": metrics.ActiveStreams(),
+				"read_only":      cfg.Server.ReadOnly,
+			})
+		})
+
+		// Readiness endpoint reflects Docker daemon reachability
+		helios.GET("/ready", func(c *gin.Context) {
+			ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+			defer cancel()
+
+			if err := dockerClient.Ping(ctx); err != nil {
+				dockerStatus := "error"
+				if docker.IsUnavailable(err) {
+					dockerStatus = "unavailable"
+				}
+				c.JSON(http.StatusServiceUnavailable, gin.H{
+					"status": "not_ready",
+					"docker": dockerStatus,
+					"detail": err.Error(),
+					"time":   time.Now(),
+				})
+				return
+			}
+
+			c.JSON(http.StatusOK, gin.H{
+				"status": "ready",
+				"docker": "available",
 				"time":   time.Now(),
 			})
 		})
 
+		// Helios internals (requires HELIOS_ADMIN_TOKEN)
+		debugHandler := handler.NewDebugHandler(database.GetDB())
+		debug := helios.Group("/debug", handler.RequireAdminToken(cfg.Server.AdminToken))
+		{
+			debug.GET("/stats", debugHandler.GetStats)
+			debug.GET("/metrics", debugHandler.Metrics)
+		}
+
+		// Multiplexed dashboard stream
+		streamHandler := handler.NewStreamHandler(containerService, logService, eventBus, containerScope)
+		helios.GET("/stream", streamHandler.Stream)
+
+		// Docker lifecycle events
+		eventsHandler := handler.NewEventsHandler(eventBus, containerScope)
+		helios.GET("/events/ws", eventsHandler.StreamEvents)
+
+		// Chargeback estimates
+		costHandler := handler.NewCostHandler(service.NewCostEstimator(healthCheckRepo, healthChecker, cfg.Cost))
+		helios.GET("/costs", costHandler.GetCosts)
+
+		// Maintenance drain/restore, disk and log reports and effective configuration
+		// (drain, restore, config and build cache prune require HELIOS_ADMIN_TOKEN)
+		systemHandler := handler.NewSystemHandler(service.NewDrainService(containerService, drainRepo), containerService, diskService, cfg)
+		system := helios.Group("/system")
+		{
+			system.POST("/drain", handler.RequireAdminToken(cfg.Server.AdminToken), systemHandler.Drain)
+			system.POST("/restore", handler.RequireAdminToken(cfg.Server.AdminToken), systemHandler.Restore)
+			system.GET("/config", handler.RequireAdminToken(cfg.Server.AdminToken), systemHandler.GetConfig)
+			system.GET("/log-rotation", systemHandler.LogRotation)
+			system.GET("/disk-usage", systemHandler.GetDiskUsage)
+			system.GET("/build-cache", systemHandler.GetBuildCache)
+			system.POST("/build-cache/prune", handler.RequireAdminToken(cfg.Server.AdminToken), systemHandler.PruneBuildCache)
+		}
+
+		// Health checker control
+		healthHandler := handler.NewHealthHandler(healthChecker)
+		helios.POST("/health/run", healthHandler.RunHealthCheck)
+
+		// Long-running operations: pulls, updates and deploys
+		operationsHandler := handler.NewOperationsHandler()
+		helios.GET("/operations", operationsHandler.ListOperations)
+		helios.DELETE("/operations/:id", operationsHandler.CancelOperation)
+
+		// Settings export/import (admin only)
+		settingsHandler := handler.NewSettingsHandler(service.NewSettingsService(healthChecker, pruneProtection))
+		settings := helios.Group("/settings", handler.RequireAdminToken(cfg.Server.AdminToken))
+		{
+			settings.GET("/export", settingsHandler.ExportSettings)
+			settings.POST("/import", settingsHandler.ImportSettings)
+		}
+
+		// Multi-container log streaming
+		logHandler := handler.NewLogHandler(logService)
+		helios.GET("/logs/stream", logHandler.StreamMatchingLogs)
+
+		// Action log endpoints
+		actionLogHandler := handler.NewActionLogHandler(actionLogRepo)
+		helios.GET("/logs/actions", actionLogHandler.ListActionLogs)
+		helios.GET("/logs/actions/summary", actionLogHandler.SummarizeActionLogs)
+		helios.GET("/logs/actions/:resourceType/:resourceId", actionLogHandler.ListResourceActionLogs)
+
+		// Event log endpoints
+		eventLogHandler := handler.NewEventLogHandler(eventLogRepo)
+		helios.GET("/logs/events", eventLogHandler.ListEventLogs)
+
 		// Container management endpoints (Phase 2)
 		containerHandler := handler.NewContainerHandler(containerService)
+		deployHandler := handler.NewDeployHandler(containerService, logService)
+		execHandler := handler.NewExecHandler(containerService)
+		statsHandler := handler.NewStatsHandler(containerService)
+		lifecycleHandler := handler.NewLifecycleHandler(service.NewLifecycleTimeline(actionLogRepo, eventLogRepo))
+		templateHandler := handler.NewTemplateHandler(service.NewTemplateService(templateRepo, containerService, actionLogRepo))
 
 		// Dashboard summary endpoint
 		helios.GET("/dashboard/summary", containerHandler.GetDashboardSummary)
 
+		// Container template endpoints
+		templates := helios.Group("/templates")
+		{
+			templates.GET("", templateHandler.ListTemplates)
+			templates.POST("", templateHandler.CreateTemplate)
+			templates.GET("/:name", templateHandler.GetTemplate)
+			templates.PUT("/:name", templateHandler.UpdateTemplate)
+			templates.DELETE("/:name", templateHandler.DeleteTemplate)
+		}
+
 		containers := helios.Group("/containers")
 		{
 			containers.GET("", containerHandler.ListContainers)
-			containers.GET("/:id", containerHandler.GetContainer)
-			containers.POST("/:id/start", containerHandler.StartContainer)
-			containers.POST("/:id/stop", containerHandler.StopContainer)
-			containers.POST("/:id/restart", containerHandler.RestartContainer)
-			containers.DELETE("/:id", containerHandler.RemoveContainer)
+			containers.POST("", containerHandler.CreateContainer)
+			containers.GET("/search", containerHandler.SearchContainers)
+			containers.GET("/top", containerHandler.TopContainers)
+			containers.GET("/deploy", deployHandler.Deploy)
+			containers.POST("/from-template/:name", templateHandler.CreateFromTemplate)
+
+			// Single-container routes accept a name, full ID or unique partial ID
+			byID := containers.Group("/:id", containerHandler.ResolveContainer)
+			{
+				byID.GET("", containerHandler.GetContainer)
+				byID.GET("/ready", containerHandler.ContainerReady)
+				byID.GET("/wait-healthy", containerHandler.WaitHealthy)
+				byID.GET("/stats", containerHandler.GetContainerStats)
+				byID.GET("/stats/ws", statsHandler.StreamStats)
+				byID.GET("/stats/history", healthHandler.StatsHistory)
+				byID.GET("/lifecycle", lifecycleHandler.ContainerLifecycle)
+				byID.GET("/env/diff", containerHandler.DiffContainerEnv)
+				byID.GET("/ports/history", healthHandler.PortHistory)
+				byID.GET("/health/history", healthHandler.HealthHistory)
+				byID.POST("/start", containerHandler.StartContainer)
+				byID.POST("/stop", containerHandler.StopContainer)
+				byID.POST("/break-loop", containerHandler.BreakRestartLoop)
+				byID.POST("/restart", containerHandler.RestartContainer)
+				byID.POST("/update", containerHandler.UpdateContainer)
+				byID.POST("/rename", containerHandler.RenameContainer)
+				byID.PATCH("/resources", containerHandler.UpdateContainerResources)
+				byID.POST("/exec", containerHandler.ExecContainer)
+				byID.GET("/exec/ws", execHandler.StreamExec)
+				byID.DELETE("", containerHandler.RemoveContainer)
+
+				// Log streaming endpoints (Phase 3)
+				byID.GET("/logs", logHandler.StreamLogs)
+				byID.GET("/logs/download", logHandler.DownloadLogs)
+				byID.POST("/logs/clear", logHandler.ClearLogs)
+			}
 
 			// Bulk operations
 			bulk := containers.Group("/bulk")
@@ -136,11 +318,6 @@ func main() {
 				bulk.POST("/stop", containerHandler.BulkStopContainers)
 				bulk.POST("/remove", containerHandler.BulkRemoveContainers)
 			}
-
-			// Log streaming endpoints (Phase 3)
-			logHandler := handler.NewLogHandler(logService)
-			containers.GET("/:id/logs", logHandler.StreamLogs)
-			containers.GET("/:id/logs/download", logHandler.DownloadLogs)
 		}
 
 		// Image management endpoints (Phase 4)
@@ -150,8 +327,12 @@ func main() {
 			images.GET("", imageHandler.ListImages)
 			images.GET("/search", imageHandler.SearchImages)
 			images.GET("/tags", imageHandler.GetImageTags)
+			images.GET("/layers", imageHandler.AnalyzeImageLayers)
+			images.GET("/diff", imageHandler.DiffImages)
 			images.GET("/:id", imageHandler.InspectImage)
 			images.POST("/pull", imageHandler.PullImage)
+			images.POST("/build", imageHandler.BuildImage)
+			images.POST("/:id/tag", imageHandler.TagImage)
 			images.POST("/prune", imageHandler.PruneImages)
 			images.DELETE("/:id", imageHandler.RemoveImage)
 
@@ -159,6 +340,8 @@ func main() {
 			bulk := images.Group("/bulk")
 			{
 				bulk.POST("/remove", imageHandler.BulkRemoveImages)
+				bulk.POST("/tag", imageHandler.BulkTagImages)
+				bulk.POST("/untag", imageHandler.BulkUntagImages)
 			}
 		}
 
@@ -170,7 +353,14 @@ func main() {
 			volumes.GET("/:name", volumeHandler.InspectVolume)
 			volumes.POST("", volumeHandler.CreateVolume)
 			volumes.POST("/prune", volumeHandler.PruneVolumes)
+			volumes.GET("/:name/usage", volumeHandler.GetVolumeUsage)
+			volumes.POST("/:name/refresh-usage", volumeHandler.RefreshVolumeUsage)
 			volumes.DELETE("/:name", volumeHandler.RemoveVolume)
+
+			bulk := volumes.Group("/bulk")
+			{
+				bulk.POST("/remove", volumeHandler.BulkRemoveVolumes)
+			}
 		}
 
 		// Network management endpoints (Phase 5)
@@ -178,10 +368,16 @@ func main() {
 		networks := helios.Group("/networks")
 		{
 			networks.GET("", networkHandler.ListNetworks)
+			networks.GET("/topology", networkHandler.GetTopology)
 			networks.GET("/:id", networkHandler.InspectNetwork)
 			networks.POST("", networkHandler.CreateNetwork)
 			networks.POST("/prune", networkHandler.PruneNetworks)
 			networks.DELETE("/:id", networkHandler.RemoveNetwork)
+
+			bulk := networks.Group("/bulk")
+			{
+				bulk.POST("/remove", networkHandler.BulkRemoveNetworks)
+			}
 		}
 	}
 
@@ -204,12 +400,28 @@ func main() {
 		}
 	}()
 
+	eventLogRepo.EmitEvent("system", "info", "Helios started", map[string]interface{}{
+		"address":     addr,
+		"docker_host": cfg.Docker.Host,
+	})
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	sig := <-quit
 
 	log.Println("Shutting down server...")
+	eventLogRepo.EmitEvent("system", "info", "Helios shutting down", map[string]interface{}{
+		"signal": sig.String(),
+	})
+
+	// Let streaming clients know before the listener closes; server.Shutdown does not
+	// wait for WebSocket connections
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if remaining := metrics.DrainStreams(drainCtx); remaining > 0 {
+		log.Printf("%d streams still open after the grace period, closing them", remaining)
+	}
+	drainCancel()
 
 	// Graceful shutdown
 	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
@@ -221,94 +433,3 @@ func main() {
 
 	log.Println("Server stopped gracefully")
 }
-
-// startHealthChecker runs the health check loop at the configured interval.
-func startHealthChecker(dockerClient *docker.Client, repo *repository.HealthCheckLogRepository, cfg *config.HealthCheckConfig) {
-	ticker := time.NewTicker(cfg.Interval)
-	defer ticker.Stop()
-
-	log.Printf("Health checker started (interval: %v, CPU threshold: %.1f%%, Memory threshold: %.1f%%)",
-		cfg.Interval, cfg.CPUThreshold, cfg.MemoryThreshold)
-
-	for {
-		<-ticker.C
-		log.Println("Running health check...")
-
-		containers, err := dockerClient.ContainerList(context.Background(), container.ListOptions{})
-		if err != nil {
-			log.Printf("Failed to list containers: %v", err)
-			continue
-		}
-
-		for _, c := range containers {
-			checkContainer(dockerClient, repo, c, cfg)
-		}
-	}
-}
-
-// checkContainer performs health check on a single container.
-func checkContainer(dockerClient *docker.Client, repo *repository.HealthCheckLogRepository, c types.Container, cfg *config.HealthCheckConfig) {
-	ctx := context.Background()
-
-	// Get container name (remove leading slash)
-	containerName := c.Names[0]
-	if len(containerName) > 0 && containerName[0] == '/' {
-		containerName = containerName[1:]
-	}
-
-	// Get container stats
-	stats, err := dockerClient.ContainerStats(ctx, c.ID, false)
-	if err != nil {
-		log.Printf("Failed to get stats for container %s: %v", containerName, err)
-		// Log error to database
-		healthLog := &models.HealthCheckLog{
-			ContainerID:   c.ID,
-			ContainerName: containerName,
-			Status:        "error",
-			ErrorMessage:  err.Error(),
-			CheckedAt:     time.Now(),
-		}
-		if err := repo.Create(healthLog); err != nil {
-			log.Printf("Failed to store health check log: %v", err)
-		}
-		return
-	}
-	defer stats.Body.Close()
-
-	// Parse stats
-	var statsData container.StatsResponse
-	if err := json.NewDecoder(stats.Body).Decode(&statsData); err != nil {
-		log.Printf("Failed to decode stats for container %s: %v", containerName, err)
-		return
-	}
-
-	// Calculate CPU percentage
-	cpuPercent := statsutil.CalculateCPUPercent(&statsData)
-
-	// Calculate memory percentage
-	memoryPercent := float64(statsData.MemoryStats.Usage) / float64(statsData.MemoryStats.Limit) * 100.0
-
-	// Determine status
-	status := "healthy"
-	if cpuPercent > cfg.CPUThreshold || memoryPercent > cfg.MemoryThreshold {
-		status = "resource_critical"
-		log.Printf("Container %s is resource critical (CPU: %.2f%%, Memory: %.2f%%)", containerName, cpuPercent, memoryPercent)
-	}
-
-	// Store health check log
-	healthLog := &models.HealthCheckLog{
-		ContainerID:         c.ID,
-		ContainerName:       containerName,
-		Status:              status,
-		ResourceCPU:         cpuPercent,
-		ResourceMemory:      statsData.MemoryStats.Usage,
-		ResourceMemoryLimit: statsData.MemoryStats.Limit,
-		ResourceNetworkRx:   statsutil.GetNetworkRx(&statsData),
-		ResourceNetworkTx:   statsutil.GetNetworkTx(&statsData),
-		CheckedAt:           time.Now(),
-	}
-
-	if err := repo.Create(healthLog); err != nil {
-		log.Printf("Failed to store health check log: %v", err)
-	}
-}
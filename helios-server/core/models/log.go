@@ -8,7 +8,7 @@ type HealthCheckLog struct {
 	ID                  int64     `json:"id"`
 	ContainerID         string    `json:"container_id"`
 	ContainerName       string    `json:"container_name"`
-	Status              string    `json:"status"` // healthy, unhealthy, resource_critical
+	Status              string    `json:"status"` // healthy, unhealthy, resource_critical, oom_killed, error
 	ResourceCPU         float64   `json:"resource_cpu"`
 	ResourceMemory      uint64    `json:"resource_memory"`
 	ResourceMemoryLimit uint64    `json:"resource_memory_limit"`
@@ -16,6 +16,44 @@ type HealthCheckLog struct {
 	ResourceNetworkTx   uint64    `json:"resource_network_tx"`
 	ErrorMessage        string    `json:"error_message,omitempty"`
 	CheckedAt           time.Time `json:"checked_at"`
+	PublishedPorts      *string   `json:"published_ports,omitempty"`  // Compact sorted port set; nil if not recorded
+	Trigger             string    `json:"trigger,omitempty"`          // Thresholds this reading breached: cpu, memory or cpu,memory
+	CPUThreshold        *float64  `json:"cpu_threshold,omitempty"`    // CPU threshold in effect when cpu breached
+	MemoryThreshold     *float64  `json:"memory_threshold,omitempty"` // Memory threshold in effect when memory breached
+}
+
+// DrainedContainer is a container stopped by a maintenance drain, to be started again on restore.
+type DrainedContainer struct {
+	ID            int64      `json:"id"`
+	DrainID       string     `json:"drain_id"`
+	ContainerID   string     `json:"container_id"`
+	ContainerName string     `json:"container_name"`
+	Position      int        `json:"position"` // Order in which the container was stopped
+	DrainedAt     time.Time  `json:"drained_at"`
+	RestoredAt    *time.Time `json:"restored_at,omitempty"`
+}
+
+// VolumeUsage is the last computed disk usage of a volume.
+type VolumeUsage struct {
+	VolumeName string    `json:"volume_name"`
+	Size       int64     `json:"size"`      // Bytes; -1 if the driver cannot report it
+	RefCount   int64     `json:"ref_count"` // Containers referencing the volume; -1 if unknown
+	ComputedAt time.Time `json:"computed_at"`
+}
+
+// PortSetRecord is a published port set of a container and when it was first recorded.
+type PortSetRecord struct {
+	Ports     string    `json:"ports"` // Comma-separated "ip:public->private/proto" entries; empty if none
+	FirstSeen time.Time `json:"first_seen"`
+}
+
+// ActionLogCount is the number of actions of one type on one resource type.
+type ActionLogCount struct {
+	ResourceType string `json:"resource_type"`
+	ActionType   string `json:"action_type"`
+	Count        int    `json:"count"`
+	Succeeded    int    `json:"succeeded"`
+	Failed       int    `json:"failed"`
 }
 
 // ActionLog represents an action performed on a Docker resource.
@@ -39,3 +77,12 @@ type EventLog struct {
 	Metadata  string    `json:"metadata,omitempty"` // JSON-encoded additional data
 	CreatedAt time.Time `json:"created_at"`
 }
+
+// ContainerTemplate is a saved container creation spec, instantiated by name.
+type ContainerTemplate struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Spec        string    `json:"spec"` // JSON-encoded container creation request
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
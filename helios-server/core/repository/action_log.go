@@ -3,8 +3,11 @@ package repository
 
 import (
 	"database/sql"
+	"strings"
+	"time"
 
 	"nfcunha/helios/core/models"
+	"nfcunha/helios/utils/metrics"
 )
 
 // ActionLogRepository handles persistence of action logs.
@@ -19,6 +22,8 @@ func NewActionLogRepository(db *sql.DB) *ActionLogRepository {
 
 // Create stores an action log in the database.
 func (r *ActionLogRepository) Create(log *models.ActionLog) error {
+	defer metrics.ObserveDBQuery(time.Now())
+
 	query := `
 		INSERT INTO action_logs (
 			action_type, resource_type, resource_id, resource_name,
@@ -61,6 +66,8 @@ func (r *ActionLogRepository) Create(log *models.ActionLog) error {
 
 // GetByResource retrieves action logs for a specific resource.
 func (r *ActionLogRepository) GetByResource(resourceType, resourceID string, limit int) ([]*models.ActionLog, error) {
+	defer metrics.ObserveDBQuery(time.Now())
+
 	query := `
 		SELECT id, action_type, resource_type, resource_id, resource_name,
 		       success, error_message, executed_at
@@ -76,54 +83,190 @@ func (r *ActionLogRepository) GetByResource(resourceType, resourceID string, lim
 	}
 	defer rows.Close()
 
-	var logs []*models.ActionLog
-	for rows.Next() {
-		log := &models.ActionLog{}
-		var errorMsg, resourceName sql.NullString
+	return scanActionLogs(rows)
+}
 
-		err := rows.Scan(
-			&log.ID,
-			&log.ActionType,
-			&log.ResourceType,
-			&log.ResourceID,
-			&resourceName,
-			&log.Success,
-			&errorMsg,
-			&log.ExecutedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
+// GetRecent retrieves recent action logs across all resources.
+func (r *ActionLogRepository) GetRecent(limit int) ([]*models.ActionLog, error) {
+	defer metrics.ObserveDBQuery(time.Now())
 
-		if errorMsg.Valid {
-			log.ErrorMessage = errorMsg.String
-		}
-		if resourceName.Valid {
-			log.ResourceName = resourceName.String
-		}
+	query := `
+		SELECT id, action_type, resource_type, resource_id, resource_name,
+		       success, error_message, executed_at
+		FROM action_logs
+		ORDER BY executed_at DESC
+		LIMIT ?
+	`
 
-		logs = append(logs, log)
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	return logs, rows.Err()
+	return scanActionLogs(rows)
 }
 
-// GetRecent retrieves recent action logs across all resources.
-func (r *ActionLogRepository) GetRecent(limit int) ([]*models.ActionLog, error) {
+// GetInRange retrieves action logs executed between from and to (inclusive).
+func (r *ActionLogRepository) GetInRange(from, to time.Time, limit, offset int) ([]*models.ActionLog, error) {
+	defer metrics.ObserveDBQuery(time.Now())
+
 	query := `
 		SELECT id, action_type, resource_type, resource_id, resource_name,
 		       success, error_message, executed_at
 		FROM action_logs
+		WHERE executed_at BETWEEN ? AND ?
 		ORDER BY executed_at DESC
-		LIMIT ?
+		LIMIT ? OFFSET ?
 	`
 
-	rows, err := r.db.Query(query, limit)
+	rows, err := r.db.Query(query, from.Local(), to.Local(), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanActionLogs(rows)
+}
+
+// ActionLogFilter selects action logs. Zero-valued fields are not filtered on.
+type ActionLogFilter struct {
+	ActionType   string
+	ResourceType string
+	ResourceID   string
+	Success      *bool
+	From         time.Time
+	To           time.Time
+	Limit        int
+	Offset       int
+}
+
+// Query retrieves a page of action logs matching the filter, newest first,
+// together with the total number of matching entries.
+func (r *ActionLogRepository) Query(filter ActionLogFilter) ([]*models.ActionLog, int, error) {
+	defer metrics.ObserveDBQuery(time.Now())
+
+	var conditions []string
+	var args []interface{}
+
+	if filter.ActionType != "" {
+		conditions = append(conditions, "action_type = ?")
+		args = append(args, filter.ActionType)
+	}
+	if filter.ResourceType != "" {
+		conditions = append(conditions, "resource_type = ?")
+		args = append(args, filter.ResourceType)
+	}
+	if filter.ResourceID != "" {
+		conditions = append(conditions, "resource_id = ?")
+		args = append(args, filter.ResourceID)
+	}
+	if filter.Success != nil {
+		conditions = append(conditions, "success = ?")
+		args = append(args, *filter.Success)
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "executed_at >= ?")
+		args = append(args, filter.From.Local())
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "executed_at <= ?")
+		args = append(args, filter.To.Local())
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM action_logs "+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT id, action_type, resource_type, resource_id, resource_name,
+		       success, error_message, executed_at
+		FROM action_logs
+		` + where + `
+		ORDER BY executed_at DESC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.db.Query(query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	logs, err := scanActionLogs(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
+
+// CountByResource counts the actions executed in a time range, grouped by resource
+// type and action type, most frequent first. A zero from or to leaves that end open.
+func (r *ActionLogRepository) CountByResource(from, to time.Time) ([]*models.ActionLogCount, error) {
+	defer metrics.ObserveDBQuery(time.Now())
+
+	var conditions []string
+	var args []interface{}
+	if !from.IsZero() {
+		conditions = append(conditions, "executed_at >= ?")
+		args = append(args, from.Local())
+	}
+	if !to.IsZero() {
+		conditions = append(conditions, "executed_at <= ?")
+		args = append(args, to.Local())
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := `
+		SELECT resource_type, action_type, COUNT(*), COALESCE(SUM(success), 0)
+		FROM action_logs
+		` + where + `
+		GROUP BY resource_type, action_type
+		ORDER BY COUNT(*) DESC, resource_type, action_type
+	`
+
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	counts := []*models.ActionLogCount{}
+	for rows.Next() {
+		count := &models.ActionLogCount{}
+		if err := rows.Scan(&count.ResourceType, &count.ActionType, &count.Count, &count.Succeeded); err != nil {
+			return nil, err
+		}
+		count.Failed = count.Count - count.Succeeded
+		counts = append(counts, count)
+	}
+	return counts, rows.Err()
+}
+
+// DeleteOlderThan removes action logs older than the specified duration.
+func (r *ActionLogRepository) DeleteOlderThan(days int) (int64, error) {
+	defer metrics.ObserveDBQuery(time.Now())
+
+	query := `DELETE FROM action_logs WHERE executed_at < datetime('now', '-' || ? || ' days')`
+	result, err := r.db.Exec(query, days)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// scanActionLogs reads all action log rows from a query result.
+func scanActionLogs(rows *sql.Rows) ([]*models.ActionLog, error) {
 	var logs []*models.ActionLog
 	for rows.Next() {
 		log := &models.ActionLog{}
@@ -155,13 +298,3 @@ func (r *ActionLogRepository) GetRecent(limit int) ([]*models.ActionLog, error)
 
 	return logs, rows.Err()
 }
-
-// DeleteOlderThan removes action logs older than the specified duration.
-func (r *ActionLogRepository) DeleteOlderThan(days int) (int64, error) {
-	query := `DELETE FROM action_logs WHERE executed_at < datetime('now', '-' || ? || ' days')`
-	result, err := r.db.Exec(query, days)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected()
-}
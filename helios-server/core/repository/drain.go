@@ -0,0 +1,99 @@
+// Package repository provides data access layer for logs.
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"nfcunha/helios/core/models"
+	"nfcunha/helios/utils/metrics"
+)
+
+// DrainRepository handles persistence of containers stopped by maintenance drains.
+type DrainRepository struct {
+	db *sql.DB
+}
+
+// NewDrainRepository creates a new drain repository.
+func NewDrainRepository(db *sql.DB) *DrainRepository {
+	return &DrainRepository{db: db}
+}
+
+// Create stores the containers stopped by one drain in a single transaction.
+func (r *DrainRepository) Create(records []*models.DrainedContainer) error {
+	defer metrics.ObserveDBQuery(time.Now())
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO drained_containers (
+			drain_id, container_id, container_name, position, drained_at
+		) VALUES (?, ?, ?, ?, ?)
+	`
+
+	for _, record := range records {
+		result, err := tx.Exec(query, record.DrainID, record.ContainerID, record.ContainerName, record.Position, record.DrainedAt)
+		if err != nil {
+			return err
+		}
+		if record.ID, err = result.LastInsertId(); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetPending returns the containers of drains that have not been restored yet,
+// in the order they were stopped.
+func (r *DrainRepository) GetPending() ([]*models.DrainedContainer, error) {
+	defer metrics.ObserveDBQuery(time.Now())
+
+	query := `
+		SELECT id, drain_id, container_id, container_name, position, drained_at, restored_at
+		FROM drained_containers
+		WHERE restored_at IS NULL
+		ORDER BY drained_at, position
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []*models.DrainedContainer
+	for rows.Next() {
+		record := &models.DrainedContainer{}
+		var restoredAt sql.NullTime
+		if err := rows.Scan(
+			&record.ID,
+			&record.DrainID,
+			&record.ContainerID,
+			&record.ContainerName,
+			&record.Position,
+			&record.DrainedAt,
+			&restoredAt,
+		); err != nil {
+			return nil, err
+		}
+		if restoredAt.Valid {
+			record.RestoredAt = &restoredAt.Time
+		}
+		records = append(records, record)
+	}
+
+	return records, rows.Err()
+}
+
+// MarkRestored records that a drained container has been handled by a restore.
+func (r *DrainRepository) MarkRestored(id int64, restoredAt time.Time) error {
+	defer metrics.ObserveDBQuery(time.Now())
+
+	_, err := r.db.Exec(`UPDATE drained_containers SET restored_at = ? WHERE id = ?`, restoredAt, id)
+	return err
+}
@@ -3,8 +3,12 @@ package repository
 
 import (
 	"database/sql"
+	"encoding/json"
+	"log"
+	"time"
 
 	"nfcunha/helios/core/models"
+	"nfcunha/helios/utils/metrics"
 )
 
 // EventLogRepository handles persistence of event logs.
@@ -19,6 +23,8 @@ func NewEventLogRepository(db *sql.DB) *EventLogRepository {
 
 // Create stores an event log in the database.
 func (r *EventLogRepository) Create(log *models.EventLog) error {
+	defer metrics.ObserveDBQuery(time.Now())
+
 	query := `
 		INSERT INTO event_logs (event_type, level, message, metadata, created_at)
 		VALUES (?, ?, ?, ?, ?)
@@ -50,8 +56,38 @@ func (r *EventLogRepository) Create(log *models.EventLog) error {
 	return nil
 }
 
+// EmitEvent stores an event log, JSON-encoding metadata when it is not nil.
+// Failures are logged rather than returned, so callers can emit events on their
+// way past without affecting the operation they describe. A nil repository does nothing.
+func (r *EventLogRepository) EmitEvent(eventType, level, message string, metadata any) {
+	if r == nil {
+		return
+	}
+
+	eventLog := &models.EventLog{
+		EventType: eventType,
+		Level:     level,
+		Message:   message,
+		CreatedAt: time.Now(),
+	}
+	if metadata != nil {
+		encoded, err := json.Marshal(metadata)
+		if err != nil {
+			log.Printf("Failed to encode %s event metadata: %v", eventType, err)
+		} else {
+			eventLog.Metadata = string(encoded)
+		}
+	}
+
+	if err := r.Create(eventLog); err != nil {
+		log.Printf("Failed to store %s event: %v", eventType, err)
+	}
+}
+
 // GetRecent retrieves recent event logs.
 func (r *EventLogRepository) GetRecent(limit int) ([]*models.EventLog, error) {
+	defer metrics.ObserveDBQuery(time.Now())
+
 	query := `
 		SELECT id, event_type, level, message, metadata, created_at
 		FROM event_logs
@@ -65,49 +101,88 @@ func (r *EventLogRepository) GetRecent(limit int) ([]*models.EventLog, error) {
 	}
 	defer rows.Close()
 
-	var logs []*models.EventLog
-	for rows.Next() {
-		log := &models.EventLog{}
-		var metadata sql.NullString
+	return scanEventLogs(rows)
+}
 
-		err := rows.Scan(
-			&log.ID,
-			&log.EventType,
-			&log.Level,
-			&log.Message,
-			&metadata,
-			&log.CreatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
+// GetByType retrieves event logs filtered by type.
+func (r *EventLogRepository) GetByType(eventType string, limit int) ([]*models.EventLog, error) {
+	defer metrics.ObserveDBQuery(time.Now())
 
-		if metadata.Valid {
-			log.Metadata = metadata.String
-		}
+	query := `
+		SELECT id, event_type, level, message, metadata, created_at
+		FROM event_logs
+		WHERE event_type = ?
+		ORDER BY created_at DESC
+		LIMIT ?
+	`
 
-		logs = append(logs, log)
+	rows, err := r.db.Query(query, eventType, limit)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	return logs, rows.Err()
+	return scanEventLogs(rows)
 }
 
-// GetByType retrieves event logs filtered by type.
-func (r *EventLogRepository) GetByType(eventType string, limit int) ([]*models.EventLog, error) {
+// GetByContainer retrieves the recorded Docker events of a container, newest first.
+func (r *EventLogRepository) GetByContainer(containerID string, limit int) ([]*models.EventLog, error) {
+	defer metrics.ObserveDBQuery(time.Now())
+
 	query := `
 		SELECT id, event_type, level, message, metadata, created_at
 		FROM event_logs
-		WHERE event_type = ?
+		WHERE event_type = 'docker'
+		  AND json_extract(metadata, '$.type') = 'container'
+		  AND json_extract(metadata, '$.id') = ?
 		ORDER BY created_at DESC
 		LIMIT ?
 	`
 
-	rows, err := r.db.Query(query, eventType, limit)
+	rows, err := r.db.Query(query, containerID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanEventLogs(rows)
+}
+
+// GetInRange retrieves event logs created between from and to (inclusive).
+func (r *EventLogRepository) GetInRange(from, to time.Time, limit, offset int) ([]*models.EventLog, error) {
+	defer metrics.ObserveDBQuery(time.Now())
+
+	query := `
+		SELECT id, event_type, level, message, metadata, created_at
+		FROM event_logs
+		WHERE created_at BETWEEN ? AND ?
+		ORDER BY created_at DESC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.db.Query(query, from.Local(), to.Local(), limit, offset)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	return scanEventLogs(rows)
+}
+
+// DeleteOlderThan removes event logs older than the specified duration.
+func (r *EventLogRepository) DeleteOlderThan(days int) (int64, error) {
+	defer metrics.ObserveDBQuery(time.Now())
+
+	query := `DELETE FROM event_logs WHERE created_at < datetime('now', '-' || ? || ' days')`
+	result, err := r.db.Exec(query, days)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// scanEventLogs reads all event log rows from a query result.
+func scanEventLogs(rows *sql.Rows) ([]*models.EventLog, error) {
 	var logs []*models.EventLog
 	for rows.Next() {
 		log := &models.EventLog{}
@@ -134,13 +209,3 @@ func (r *EventLogRepository) GetByType(eventType string, limit int) ([]*models.E
 
 	return logs, rows.Err()
 }
-
-// DeleteOlderThan removes event logs older than the specified duration.
-func (r *EventLogRepository) DeleteOlderThan(days int) (int64, error) {
-	query := `DELETE FROM event_logs WHERE created_at < datetime('now', '-' || ? || ' days')`
-	result, err := r.db.Exec(query, days)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected()
-}
@@ -3,8 +3,10 @@ package repository
 
 import (
 	"database/sql"
+	"time"
 
 	"nfcunha/helios/core/models"
+	"nfcunha/helios/utils/metrics"
 )
 
 // HealthCheckLogRepository handles persistence of health check logs.
@@ -19,19 +21,26 @@ func NewHealthCheckLogRepository(db *sql.DB) *HealthCheckLogRepository {
 
 // Create stores a health check result in the database.
 func (r *HealthCheckLogRepository) Create(log *models.HealthCheckLog) error {
+	defer metrics.ObserveDBQuery(time.Now())
+
 	query := `
 		INSERT INTO health_check_logs (
 			container_id, container_name, status,
 			resource_cpu, resource_memory, resource_memory_limit,
 			resource_network_rx, resource_network_tx,
-			error_message, checked_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			error_message, checked_at, published_ports,
+			trigger, cpu_threshold, memory_threshold
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	var errorMsg *string
 	if log.ErrorMessage != "" {
 		errorMsg = &log.ErrorMessage
 	}
+	var trigger *string
+	if log.Trigger != "" {
+		trigger = &log.Trigger
+	}
 
 	result, err := r.db.Exec(
 		query,
@@ -45,6 +54,10 @@ func (r *HealthCheckLogRepository) Create(log *models.HealthCheckLog) error {
 		log.ResourceNetworkTx,
 		errorMsg,
 		log.CheckedAt,
+		log.PublishedPorts,
+		trigger,
+		log.CPUThreshold,
+		log.MemoryThreshold,
 	)
 	if err != nil {
 		return err
@@ -61,11 +74,14 @@ func (r *HealthCheckLogRepository) Create(log *models.HealthCheckLog) error {
 
 // GetByContainerID retrieves health check logs for a specific container.
 func (r *HealthCheckLogRepository) GetByContainerID(containerID string, limit int) ([]*models.HealthCheckLog, error) {
+	defer metrics.ObserveDBQuery(time.Now())
+
 	query := `
 		SELECT id, container_id, container_name, status,
 		       resource_cpu, resource_memory, resource_memory_limit,
 		       resource_network_rx, resource_network_tx,
-		       error_message, checked_at
+		       error_message, checked_at, published_ports,
+		       trigger, cpu_threshold, memory_threshold
 		FROM health_check_logs
 		WHERE container_id = ?
 		ORDER BY checked_at DESC
@@ -78,10 +94,167 @@ func (r *HealthCheckLogRepository) GetByContainerID(containerID string, limit in
 	}
 	defer rows.Close()
 
+	return scanHealthCheckLogs(rows)
+}
+
+// GetContainerStatsHistory retrieves the resource readings recorded for a container
+// since the given time, newest first. Entries without readings (errors, OOM kills)
+// are excluded.
+func (r *HealthCheckLogRepository) GetContainerStatsHistory(containerID string, since time.Time, limit int) ([]*models.HealthCheckLog, error) {
+	defer metrics.ObserveDBQuery(time.Now())
+
+	query := `
+		SELECT id, container_id, container_name, status,
+		       resource_cpu, resource_memory, resource_memory_limit,
+		       resource_network_rx, resource_network_tx,
+		       error_message, checked_at, published_ports,
+		       trigger, cpu_threshold, memory_threshold
+		FROM health_check_logs
+		WHERE container_id = ? AND checked_at >= ?
+		  AND status IN ('healthy', 'resource_critical')
+		ORDER BY checked_at DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, containerID, since.Local(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanHealthCheckLogs(rows)
+}
+
+// GetInRange retrieves health check logs recorded between from and to (inclusive).
+func (r *HealthCheckLogRepository) GetInRange(from, to time.Time, limit, offset int) ([]*models.HealthCheckLog, error) {
+	defer metrics.ObserveDBQuery(time.Now())
+
+	query := `
+		SELECT id, container_id, container_name, status,
+		       resource_cpu, resource_memory, resource_memory_limit,
+		       resource_network_rx, resource_network_tx,
+		       error_message, checked_at, published_ports,
+		       trigger, cpu_threshold, memory_threshold
+		FROM health_check_logs
+		WHERE checked_at BETWEEN ? AND ?
+		ORDER BY checked_at DESC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.db.Query(query, from.Local(), to.Local(), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanHealthCheckLogs(rows)
+}
+
+// GetUsageSamples retrieves resource readings recorded between from and to (inclusive),
+// ordered by container and then by time. Entries without readings (errors, OOM kills)
+// are excluded.
+func (r *HealthCheckLogRepository) GetUsageSamples(from, to time.Time) ([]*models.HealthCheckLog, error) {
+	defer metrics.ObserveDBQuery(time.Now())
+
+	query := `
+		SELECT id, container_id, container_name, status,
+		       resource_cpu, resource_memory, resource_memory_limit,
+		       resource_network_rx, resource_network_tx,
+		       error_message, checked_at, published_ports,
+		       trigger, cpu_threshold, memory_threshold
+		FROM health_check_logs
+		WHERE checked_at BETWEEN ? AND ?
+		  AND status IN ('healthy', 'resource_critical')
+		ORDER BY container_id, checked_at ASC
+	`
+
+	rows, err := r.db.Query(query, from.Local(), to.Local())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanHealthCheckLogs(rows)
+}
+
+// GetLatestPorts returns the most recently recorded published port set of a container.
+// The boolean is false if no port set has been recorded yet.
+func (r *HealthCheckLogRepository) GetLatestPorts(containerID string) (string, bool, error) {
+	defer metrics.ObserveDBQuery(time.Now())
+
+	query := `
+		SELECT published_ports
+		FROM health_check_logs
+		WHERE container_id = ? AND published_ports IS NOT NULL
+		ORDER BY checked_at DESC
+		LIMIT 1
+	`
+
+	var ports string
+	err := r.db.QueryRow(query, containerID).Scan(&ports)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return ports, true, nil
+}
+
+// GetPortHistory returns the distinct published port sets a container has had,
+// each with the time it was first recorded, most recent first.
+func (r *HealthCheckLogRepository) GetPortHistory(containerID string, limit int) ([]*models.PortSetRecord, error) {
+	defer metrics.ObserveDBQuery(time.Now())
+
+	query := `
+		SELECT published_ports, checked_at
+		FROM (
+			SELECT published_ports, checked_at,
+			       LAG(published_ports) OVER (ORDER BY checked_at) AS previous
+			FROM health_check_logs
+			WHERE container_id = ? AND published_ports IS NOT NULL
+		)
+		WHERE previous IS NULL OR previous != published_ports
+		ORDER BY checked_at DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, containerID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []*models.PortSetRecord
+	for rows.Next() {
+		record := &models.PortSetRecord{}
+		if err := rows.Scan(&record.Ports, &record.FirstSeen); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// DeleteOlderThan removes health check logs older than the specified duration.
+func (r *HealthCheckLogRepository) DeleteOlderThan(days int) (int64, error) {
+	defer metrics.ObserveDBQuery(time.Now())
+
+	query := `DELETE FROM health_check_logs WHERE checked_at < datetime('now', '-' || ? || ' days')`
+	result, err := r.db.Exec(query, days)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// scanHealthCheckLogs reads all health check log rows from a query result.
+func scanHealthCheckLogs(rows *sql.Rows) ([]*models.HealthCheckLog, error) {
 	var logs []*models.HealthCheckLog
 	for rows.Next() {
 		log := &models.HealthCheckLog{}
-		var errorMsg sql.NullString
+		var errorMsg, publishedPorts, trigger sql.NullString
+		var cpuThreshold, memoryThreshold sql.NullFloat64
 
 		err := rows.Scan(
 			&log.ID,
@@ -95,6 +268,10 @@ func (r *HealthCheckLogRepository) GetByContainerID(containerID string, limit in
 			&log.ResourceNetworkTx,
 			&errorMsg,
 			&log.CheckedAt,
+			&publishedPorts,
+			&trigger,
+			&cpuThreshold,
+			&memoryThreshold,
 		)
 		if err != nil {
 			return nil, err
@@ -103,19 +280,21 @@ func (r *HealthCheckLogRepository) GetByContainerID(containerID string, limit in
 		if errorMsg.Valid {
 			log.ErrorMessage = errorMsg.String
 		}
+		if publishedPorts.Valid {
+			log.PublishedPorts = &publishedPorts.String
+		}
+		if trigger.Valid {
+			log.Trigger = trigger.String
+		}
+		if cpuThreshold.Valid {
+			log.CPUThreshold = &cpuThreshold.Float64
+		}
+		if memoryThreshold.Valid {
+			log.MemoryThreshold = &memoryThreshold.Float64
+		}
 
 		logs = append(logs, log)
 	}
 
 	return logs, rows.Err()
 }
-
-// DeleteOlderThan removes health check logs older than the specified duration.
-func (r *HealthCheckLogRepository) DeleteOlderThan(days int) (int64, error) {
-	query := `DELETE FROM health_check_logs WHERE checked_at < datetime('now', '-' || ? || ' days')`
-	result, err := r.db.Exec(query, days)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected()
-}
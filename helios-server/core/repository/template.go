@@ -0,0 +1,129 @@
+// Package repository provides data access layer for logs.
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"nfcunha/helios/core/models"
+	"nfcunha/helios/utils/metrics"
+)
+
+// TemplateRepository handles persistence of container templates.
+type TemplateRepository struct {
+	db *sql.DB
+}
+
+// NewTemplateRepository creates a new template repository.
+func NewTemplateRepository(db *sql.DB) *TemplateRepository {
+	return &TemplateRepository{db: db}
+}
+
+// Create stores a new template. The boolean is false if a template with the same name already exists.
+func (r *TemplateRepository) Create(template *models.ContainerTemplate) (bool, error) {
+	defer metrics.ObserveDBQuery(time.Now())
+
+	query := `
+		INSERT INTO templates (name, description, spec, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(name) DO NOTHING
+	`
+
+	result, err := r.db.Exec(query, template.Name, template.Description, template.Spec, template.CreatedAt, template.UpdatedAt)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// Update replaces the description and spec of a template.
+// The boolean is false if no template has that name.
+func (r *TemplateRepository) Update(template *models.ContainerTemplate) (bool, error) {
+	defer metrics.ObserveDBQuery(time.Now())
+
+	query := `
+		UPDATE templates
+		SET description = ?, spec = ?, updated_at = ?
+		WHERE name = ?
+	`
+
+	result, err := r.db.Exec(query, template.Description, template.Spec, template.UpdatedAt, template.Name)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// Get returns a template by name.
+// The boolean is false if no template has that name.
+func (r *TemplateRepository) Get(name string) (*models.ContainerTemplate, bool, error) {
+	defer metrics.ObserveDBQuery(time.Now())
+
+	query := `
+		SELECT name, description, spec, created_at, updated_at
+		FROM templates
+		WHERE name = ?
+	`
+
+	template, err := scanTemplate(r.db.QueryRow(query, name))
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return template, true, nil
+}
+
+// List returns all templates ordered by name.
+func (r *TemplateRepository) List() ([]*models.ContainerTemplate, error) {
+	defer metrics.ObserveDBQuery(time.Now())
+
+	query := `
+		SELECT name, description, spec, created_at, updated_at
+		FROM templates
+		ORDER BY name
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []*models.ContainerTemplate
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+	return templates, rows.Err()
+}
+
+// Delete removes a template. The boolean is false if no template has that name.
+func (r *TemplateRepository) Delete(name string) (bool, error) {
+	defer metrics.ObserveDBQuery(time.Now())
+
+	result, err := r.db.Exec(`DELETE FROM templates WHERE name = ?`, name)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// scanTemplate reads a template from a row of a query selecting all template columns.
+func scanTemplate(row interface{ Scan(...any) error }) (*models.ContainerTemplate, error) {
+	template := &models.ContainerTemplate{}
+	var description sql.NullString
+
+	if err := row.Scan(&template.Name, &description, &template.Spec, &template.CreatedAt, &template.UpdatedAt); err != nil {
+		return nil, err
+	}
+	template.Description = description.String
+	return template, nil
+}
@@ -0,0 +1,67 @@
+// Package repository provides data access layer for logs.
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"nfcunha/helios/core/models"
+	"nfcunha/helios/utils/metrics"
+)
+
+// VolumeUsageRepository handles persistence of computed volume sizes.
+type VolumeUsageRepository struct {
+	db *sql.DB
+}
+
+// NewVolumeUsageRepository creates a new volume usage repository.
+func NewVolumeUsageRepository(db *sql.DB) *VolumeUsageRepository {
+	return &VolumeUsageRepository{db: db}
+}
+
+// Save stores the usage of a volume, replacing any previously computed value.
+func (r *VolumeUsageRepository) Save(usage *models.VolumeUsage) error {
+	defer metrics.ObserveDBQuery(time.Now())
+
+	query := `
+		INSERT INTO volume_usage (volume_name, size, ref_count, computed_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(volume_name) DO UPDATE SET
+			size = excluded.size,
+			ref_count = excluded.ref_count,
+			computed_at = excluded.computed_at
+	`
+
+	_, err := r.db.Exec(query, usage.VolumeName, usage.Size, usage.RefCount, usage.ComputedAt)
+	return err
+}
+
+// Get returns the last computed usage of a volume.
+// The boolean is false if its usage has not been computed yet.
+func (r *VolumeUsageRepository) Get(volumeName string) (*models.VolumeUsage, bool, error) {
+	defer metrics.ObserveDBQuery(time.Now())
+
+	query := `
+		SELECT volume_name, size, ref_count, computed_at
+		FROM volume_usage
+		WHERE volume_name = ?
+	`
+
+	usage := &models.VolumeUsage{}
+	err := r.db.QueryRow(query, volumeName).Scan(&usage.VolumeName, &usage.Size, &usage.RefCount, &usage.ComputedAt)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return usage, true, nil
+}
+
+// Delete removes the cached usage of a volume.
+func (r *VolumeUsageRepository) Delete(volumeName string) error {
+	defer metrics.ObserveDBQuery(time.Now())
+
+	_, err := r.db.Exec(`DELETE FROM volume_usage WHERE volume_name = ?`, volumeName)
+	return err
+}
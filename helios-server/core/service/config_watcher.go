@@ -0,0 +1,340 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"nfcunha/helios/core/repository"
+	"nfcunha/helios/utils/config"
+	"nfcunha/helios/utils/docker"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/fsnotify/fsnotify"
+)
+
+// WatchLabel lists the config files (comma-separated paths) whose changes restart a container.
+// Paths are resolved on the Helios filesystem, so they must be mounted into Helios.
+const WatchLabel = "helios.watch"
+
+// ConfigWatcher restarts containers when one of their watched config files changes.
+// The parent directories of watched files are watched with fsnotify, so files replaced
+// by a rename are still noticed. A restart happens once a changed file has stayed
+// unchanged for the debounce period, so editors writing in several steps trigger a
+// single restart. Labelled containers are re-listed at the configured interval.
+type ConfigWatcher struct {
+	dockerClient     *docker.Client
+	containerService *ContainerService
+	eventLogRepo     *repository.EventLogRepository
+	scope            *ContainerScope
+	cfg              config.ConfigWatchConfig
+	fsWatcher        *fsnotify.Watcher
+	state            *watchState     // Only touched by the loop goroutine
+	dirs             map[string]bool // Watched directories; false once adding the watch failed
+	ctx              context.Context
+	cancel           context.CancelFunc
+}
+
+// NewConfigWatcher creates a new config watcher and starts the background loop.
+func NewConfigWatcher(dockerClient *docker.Client, containerService *ContainerService, eventLogRepo *repository.EventLogRepository, scope *ContainerScope, cfg config.ConfigWatchConfig) (*ConfigWatcher, error) {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file watcher: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	watcher := &ConfigWatcher{
+		dockerClient:     dockerClient,
+		containerService: containerService,
+		eventLogRepo:     eventLogRepo,
+		scope:            scope,
+		cfg:              cfg,
+		fsWatcher:        fsWatcher,
+		state:            newWatchState(cfg.Debounce),
+		dirs:             make(map[string]bool),
+		ctx:              ctx,
+		cancel:           cancel,
+	}
+
+	go watcher.loop()
+
+	return watcher, nil
+}
+
+// loop handles file events and re-lists labelled containers until stopped.
+func (w *ConfigWatcher) loop() {
+	defer w.fsWatcher.Close()
+
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+
+	// Fires when the earliest pending change has settled; nil while nothing is pending
+	var settled <-chan time.Time
+
+	log.Printf("Config watcher started (interval: %v, debounce: %v)", w.cfg.Interval, w.cfg.Debounce)
+
+	w.sync()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+			w.sync()
+		case event, ok := <-w.fsWatcher.Events:
+			if !ok {
+				return
+			}
+			if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) && !event.Has(fsnotify.Rename) {
+				continue
+			}
+			if w.state.changed(filepath.Clean(event.Name), time.Now()) {
+				settled = w.nextSettled()
+			}
+		case err, ok := <-w.fsWatcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("Config watcher error: %v", err)
+		case <-settled:
+			for _, r := range w.state.due(time.Now()) {
+				w.restart(r.containerID, r.name, r.paths)
+			}
+			settled = w.nextSettled()
+		}
+	}
+}
+
+// nextSettled returns a channel that fires when the earliest pending change settles,
+// or nil when no change is pending.
+func (w *ConfigWatcher) nextSettled() <-chan time.Time {
+	next, ok := w.state.nextDue()
+	if !ok {
+		return nil
+	}
+	return time.After(time.Until(next))
+}
+
+// sync re-lists running labelled containers and updates the watched files and directories.
+func (w *ConfigWatcher) sync() {
+	ctx, cancel := context.WithTimeout(w.ctx, 30*time.Second)
+	defer cancel()
+
+	containers, err := w.dockerClient.ContainerList(ctx, container.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", WatchLabel)),
+	})
+	if err != nil {
+		if w.ctx.Err() == nil {
+			log.Printf("Config watcher failed to list containers: %v", err)
+		}
+		return
+	}
+
+	watches := make(map[string]map[string]string)
+	for _, c := range w.scope.Filter(containers) {
+		name := ""
+		if len(c.Names) > 0 {
+			name = containerDisplayName(c.Names[0])
+		}
+		for _, path := range watchPaths(c.Labels[WatchLabel]) {
+			if watches[path] == nil {
+				watches[path] = make(map[string]string)
+			}
+			watches[path][c.ID] = name
+		}
+	}
+
+	for _, path := range w.state.update(watches) {
+		if _, err := os.Stat(path); err != nil {
+			log.Printf("Config watcher: %s does not exist or is not mounted into Helios", path)
+		}
+	}
+
+	w.syncDirs()
+}
+
+// syncDirs adds watches for the parent directories of watched files and removes
+// watches that are no longer needed. Failed directories are retried on every sync
+// but only logged once.
+func (w *ConfigWatcher) syncDirs() {
+	needed := make(map[string]bool)
+	for path := range w.state.files {
+		needed[filepath.Dir(path)] = true
+	}
+
+	for dir := range needed {
+		if w.dirs[dir] {
+			continue
+		}
+		if err := w.fsWatcher.Add(dir); err != nil {
+			if _, logged := w.dirs[dir]; !logged {
+				log.Printf("Config watcher failed to watch %s: %v", dir, err)
+			}
+			w.dirs[dir] = false
+			continue
+		}
+		w.dirs[dir] = true
+	}
+
+	for dir, watching := range w.dirs {
+		if needed[dir] {
+			continue
+		}
+		if watching {
+			_ = w.fsWatcher.Remove(dir)
+		}
+		delete(w.dirs, dir)
+	}
+}
+
+// restart restarts a container after its config changed and records the restart.
+func (w *ConfigWatcher) restart(containerID, name string, paths []string) {
+	log.Printf("Config watcher: restarting container %s after changes to %s", name, strings.Join(paths, ", "))
+
+	ctx, cancel := context.WithTimeout(w.ctx, 60*time.Second)
+	defer cancel()
+
+	err := w.containerService.RestartContainer(ctx, containerID)
+	if err != nil {
+		log.Printf("Config watcher failed to restart container %s: %v", name, err)
+	}
+
+	level := "info"
+	message := fmt.Sprintf("Container %s restarted after config change", name)
+	if err != nil {
+		level = "warning"
+		message = fmt.Sprintf("Container %s could not be restarted after config change: %v", name, err)
+	}
+
+	w.eventLogRepo.EmitEvent("config_watch", level, message, map[string]interface{}{
+		"container_id":   containerID,
+		"container_name": name,
+		"paths":          paths,
+	})
+}
+
+// Stop stops the background watch loop.
+func (w *ConfigWatcher) Stop() {
+	w.cancel()
+}
+
+// watchPaths splits a helios.watch label value into its cleaned paths.
+func watchPaths(label string) []string {
+	var paths []string
+	for _, path := range strings.Split(label, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, filepath.Clean(path))
+		}
+	}
+	return paths
+}
+
+// watchState holds the debounce state of watched files. It is not safe for concurrent use.
+type watchState struct {
+	debounce time.Duration
+	files    map[string]*watchedFile // By cleaned path
+}
+
+// watchedFile is a watched path and the containers that watch it.
+type watchedFile struct {
+	containers map[string]string // Container ID to name
+	changedAt  time.Time         // Zero unless a change is waiting for the debounce period
+}
+
+// configRestart is a container due for a restart and the changed paths that caused it.
+type configRestart struct {
+	containerID string
+	name        string
+	paths       []string
+}
+
+// newWatchState creates an empty watch state.
+func newWatchState(debounce time.Duration) *watchState {
+	return &watchState{debounce: debounce, files: make(map[string]*watchedFile)}
+}
+
+// update replaces the watched paths and their containers (path to container ID to name)
+// and returns the paths seen for the first time. First sightings never arm a restart;
+// pending changes of paths that are still watched are kept.
+func (s *watchState) update(watches map[string]map[string]string) []string {
+	var added []string
+	for path, containers := range watches {
+		file, ok := s.files[path]
+		if !ok {
+			file = &watchedFile{}
+			s.files[path] = file
+			added = append(added, path)
+		}
+		file.containers = containers
+	}
+
+	// Forget paths of containers that stopped or lost the label
+	for path := range s.files {
+		if _, ok := watches[path]; !ok {
+			delete(s.files, path)
+		}
+	}
+
+	sort.Strings(added)
+	return added
+}
+
+// changed records a change to path and restarts its debounce period. It reports
+// whether the path is watched.
+func (s *watchState) changed(path string, now time.Time) bool {
+	file, ok := s.files[path]
+	if !ok {
+		return false
+	}
+	file.changedAt = now
+	return true
+}
+
+// nextDue returns when the earliest pending change settles.
+func (s *watchState) nextDue() (time.Time, bool) {
+	var next time.Time
+	for _, file := range s.files {
+		if file.changedAt.IsZero() {
+			continue
+		}
+		if due := file.changedAt.Add(s.debounce); next.IsZero() || due.Before(next) {
+			next = due
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// due disarms files that stayed unchanged for the debounce period and returns the
+// containers to restart, one entry per container.
+func (s *watchState) due(now time.Time) []configRestart {
+	byContainer := make(map[string]*configRestart)
+	for path, file := range s.files {
+		if file.changedAt.IsZero() || now.Sub(file.changedAt) < s.debounce {
+			continue
+		}
+		file.changedAt = time.Time{}
+		for id, name := range file.containers {
+			r, ok := byContainer[id]
+			if !ok {
+				r = &configRestart{containerID: id, name: name}
+				byContainer[id] = r
+			}
+			r.paths = append(r.paths, path)
+		}
+	}
+
+	restarts := make([]configRestart, 0, len(byContainer))
+	for _, r := range byContainer {
+		sort.Strings(r.paths)
+		restarts = append(restarts, *r)
+	}
+	sort.Slice(restarts, func(i, j int) bool { return restarts[i].containerID < restarts[j].containerID })
+	return restarts
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWatchStateDebounce(t *testing.T) {
+	const debounce = 5 * time.Second
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	type step struct {
+		at      time.Duration // Offset from start
+		change  string        // Path changed at this step, if any
+		wantDue []string      // Container IDs restarted by due at this step
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "first sighting does not restart",
+			steps: []step{
+				{at: 0},
+				{at: time.Hour},
+			},
+		},
+		{
+			name: "change arms the timer",
+			steps: []step{
+				{at: time.Second, change: "/etc/app/a.conf"},
+				{at: 5 * time.Second},
+				{at: 6 * time.Second, wantDue: []string{"c1"}},
+				{at: time.Hour},
+			},
+		},
+		{
+			name: "further changes restart the debounce period",
+			steps: []step{
+				{at: time.Second, change: "/etc/app/a.conf"},
+				{at: 4 * time.Second, change: "/etc/app/a.conf"},
+				{at: 8 * time.Second},
+				{at: 9 * time.Second, wantDue: []string{"c1"}},
+			},
+		},
+		{
+			name: "shared file restarts every watching container",
+			steps: []step{
+				{at: 0, change: "/etc/app/b.conf"},
+				{at: debounce, wantDue: []string{"c1", "c2"}},
+			},
+		},
+		{
+			name: "unwatched path is ignored",
+			steps: []step{
+				{at: 0, change: "/etc/app/other.conf"},
+				{at: time.Hour},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := newWatchState(debounce)
+			state.update(map[string]map[string]string{
+				"/etc/app/a.conf": {"c1": "web"},
+				"/etc/app/b.conf": {"c1": "web", "c2": "worker"},
+			})
+
+			for i, s := range tt.steps {
+				now := start.Add(s.at)
+				if s.change != "" {
+					state.changed(s.change, now)
+				}
+
+				var got []string
+				for _, r := range state.due(now) {
+					got = append(got, r.containerID)
+				}
+				if !reflect.DeepEqual(got, s.wantDue) {
+					t.Errorf("step %d (%v): due = %v, want %v", i, s.at, got, s.wantDue)
+				}
+			}
+		})
+	}
+}
+
+func TestWatchStateUpdate(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	state := newWatchState(time.Second)
+
+	added := state.update(map[string]map[string]string{"/a": {"c1": "web"}, "/b": {"c1": "web"}})
+	if want := []string{"/a", "/b"}; !reflect.DeepEqual(added, want) {
+		t.Errorf("first update added = %v, want %v", added, want)
+	}
+	if _, ok := state.nextDue(); ok {
+		t.Error("first sighting armed a restart")
+	}
+
+	state.changed("/a", now)
+	state.changed("/b", now)
+
+	// A pending change survives a re-list; a path that is no longer watched is forgotten
+	added = state.update(map[string]map[string]string{"/a": {"c1": "web"}})
+	if len(added) != 0 {
+		t.Errorf("second update added = %v, want none", added)
+	}
+	if state.changed("/b", now) {
+		t.Error("changed reported a forgotten path as watched")
+	}
+	if next, ok := state.nextDue(); !ok || !next.Equal(now.Add(time.Second)) {
+		t.Errorf("nextDue = %v, %v, want %v, true", next, ok, now.Add(time.Second))
+	}
+
+	restarts := state.due(now.Add(time.Second))
+	want := []configRestart{{containerID: "c1", name: "web", paths: []string{"/a"}}}
+	if !reflect.DeepEqual(restarts, want) {
+		t.Errorf("due = %+v, want %+v", restarts, want)
+	}
+}
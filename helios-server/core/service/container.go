@@ -7,35 +7,62 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"nfcunha/helios/core/models"
 	"nfcunha/helios/core/repository"
+	"nfcunha/helios/utils/config"
 	"nfcunha/helios/utils/docker"
 	"nfcunha/helios/utils/statsutil"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
 )
 
 // ContainerService handles container-related operations.
 type ContainerService struct {
 	dockerClient  *docker.Client
 	actionLogRepo *repository.ActionLogRepository
+	scope         *ContainerScope
+	credentials   *RegistryCredentials
 	statsCache    *StatsCache
+	execCfg       config.ExecConfig
+	bulkCfg       config.BulkConfig
+	labels        *ResourceLabels
+
+	validateBindMounts bool
 }
 
 // NewContainerService creates a new container service.
-func NewContainerService(dockerClient *docker.Client, actionLogRepo *repository.ActionLogRepository) *ContainerService {
+// Listing, stats and resolution only consider containers within the given scope.
+// Image pulls during updates use the registry credentials when available.
+// Running containers are sampled for the stats cache every statsCfg.SampleEvery refresh
+// cycles unless their helios.stats.every label says otherwise.
+// Output of commands run with ExecContainer is capped at execCfg.MaxOutput.
+// Containers created with CreateContainer are stamped with the given resource labels.
+// Bulk operations handle at most bulkCfg.Concurrency containers at a time.
+// Bind mount sources are checked before creation unless dockerCfg.ValidateBindMounts is off.
+func NewContainerService(dockerClient *docker.Client, actionLogRepo *repository.ActionLogRepository, scope *ContainerScope, credentials *RegistryCredentials, labels *ResourceLabels, dockerCfg config.DockerConfig, statsCfg config.StatsConfig, execCfg config.ExecConfig, bulkCfg config.BulkConfig) *ContainerService {
 	service := &ContainerService{
 		dockerClient:  dockerClient,
 		actionLogRepo: actionLogRepo,
+		scope:         scope,
+		credentials:   credentials,
+		execCfg:       execCfg,
+		bulkCfg:       bulkCfg,
+		labels:        labels,
+
+		validateBindMounts: dockerCfg.ValidateBindMounts,
 	}
 
 	// Initialize stats cache with background refresh
-	service.statsCache = NewStatsCache(service)
+	service.statsCache = NewStatsCache(service, statsCfg.SampleEvery)
 
 	return service
 }
@@ -46,8 +73,20 @@ type ContainerListOptions struct {
 	Limit        int    // Limit number of results
 	Filter       string // Filter by name (substring match)
 	IncludeStats bool   // Include resource stats (CPU, memory, etc.)
+	FetchStats   bool   // Fetch stats directly for running containers while the stats cache is cold
+	StatsAverage bool   // Include moving averages alongside instantaneous stats
+
+	CreatedSince time.Time // Only containers created at or after this time; zero disables
+	StartedSince time.Time // Only containers (re)started at or after this time; zero disables
 }
 
+// Stats availability reported in ContainerInfo.StatsStatus.
+const (
+	StatsAvailable   = "available"   // Stats are attached
+	StatsPending     = "pending"     // Container is running but has not been sampled yet
+	StatsUnavailable = "unavailable" // Container is not running
+)
+
 // ContainerInfo represents detailed container information with stats.
 type ContainerInfo struct {
 	ID          string            `json:"id"`
@@ -63,6 +102,23 @@ type ContainerInfo struct {
 	Mounts      []MountInfo       `json:"mounts"`
 	NetworkMode string            `json:"network_mode"`
 	Stats       *ContainerStats   `json:"stats,omitempty"`
+	StatsStatus string            `json:"stats_status,omitempty"`
+
+	// Populated by ListFailedContainers only
+	ExitCode   *int       `json:"exit_code,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+
+	// Populated by ListContainers when filtering by start time
+	StartedAt *time.Time `json:"started_at,omitempty"`
+
+	// Populated by GetContainer only
+	CommandArgs    []string            `json:"command_args,omitempty"` // Executable followed by its arguments
+	Devices        []DeviceMappingSpec `json:"devices,omitempty"`
+	DeviceRequests []DeviceRequestSpec `json:"device_requests,omitempty"`
+	Ulimits        []UlimitSpec        `json:"ulimits,omitempty"`
+	LogDriver      *LogDriverInfo      `json:"log_driver,omitempty"`
+	OOMKilled      bool                `json:"oom_killed,omitempty"`
+	OOMKilledAt    *time.Time          `json:"oom_killed_at,omitempty"`
 }
 
 // PortInfo represents a container port mapping.
@@ -75,24 +131,72 @@ type PortInfo struct {
 
 // MountInfo represents a container mount.
 type MountInfo struct {
-	Type        string `json:"type"`
-	Name        string `json:"name,omitempty"`
-	Source      string `json:"source"`
-	Destination string `json:"destination"`
-	Mode        string `json:"mode"`
-	RW          bool   `json:"rw"`
+	Type               string `json:"type"`
+	Name               string `json:"name,omitempty"`
+	Source             string `json:"source"`
+	Destination        string `json:"destination"`
+	Mode               string `json:"mode"`
+	RW                 bool   `json:"rw"`
+	Propagation        string `json:"propagation,omitempty"`         // Bind propagation: rprivate (default), private, rshared, shared, rslave or slave
+	Consistency        string `json:"consistency,omitempty"`         // default, consistent, cached or delegated; only reported by container details
+	PropagationWarning string `json:"propagation_warning,omitempty"` // Set for bind mounts whose propagation may affect the host or other containers
+}
+
+// newMountInfo describes a mount point as reported by the daemon.
+func newMountInfo(m types.MountPoint) MountInfo {
+	return MountInfo{
+		Type:               string(m.Type),
+		Name:               m.Name,
+		Source:             m.Source,
+		Destination:        m.Destination,
+		Mode:               m.Mode,
+		RW:                 m.RW,
+		Propagation:        string(m.Propagation),
+		PropagationWarning: propagationWarning(m.Type, m.Propagation),
+	}
+}
+
+// propagationWarning explains why a bind mount's propagation may cause problems, or
+// returns "" if it does not. Shared propagation lets mounts made inside the container
+// appear on the host and in every container binding the same path.
+func propagationWarning(mountType mount.Type, propagation mount.Propagation) string {
+	if mountType != mount.TypeBind {
+		return ""
+	}
+	switch propagation {
+	case mount.PropagationShared, mount.PropagationRShared:
+		return "mounts made in the container propagate to the host and to other containers sharing this path"
+	}
+	return ""
 }
 
 // ContainerStats represents container resource statistics.
 type ContainerStats struct {
-	CPUPercent    float64 `json:"cpu_percent"`
-	MemoryUsage   uint64  `json:"memory_usage"`
-	MemoryLimit   uint64  `json:"memory_limit"`
-	MemoryPercent float64 `json:"memory_percent"`
-	NetworkRx     uint64  `json:"network_rx"`
-	NetworkTx     uint64  `json:"network_tx"`
-	BlockRead     uint64  `json:"block_read"`
-	BlockWrite    uint64  `json:"block_write"`
+	CPUPercent      float64  `json:"cpu_percent"`
+	MemoryUsage     uint64   `json:"memory_usage"`
+	MemoryLimit     uint64   `json:"memory_limit"`
+	MemoryPercent   *float64 `json:"memory_percent"`             // Null when the container has no memory limit
+	MemoryUnlimited bool     `json:"memory_unlimited,omitempty"` // The limit is the host's memory, not one set on the container
+	MemorySwap      uint64   `json:"memory_swap"`
+	MemoryRSS       uint64   `json:"memory_rss"`
+	MemoryCache     uint64   `json:"memory_cache"`
+	MemoryAnon      uint64   `json:"memory_anon,omitempty"` // cgroup v2 only
+	MemoryFile      uint64   `json:"memory_file,omitempty"` // cgroup v2 only
+	NetworkRx       uint64   `json:"network_rx"`
+	NetworkTx       uint64   `json:"network_tx"`
+	BlockRead       uint64   `json:"block_read"`
+	BlockWrite      uint64   `json:"block_write"`
+	BlockUnknown    bool     `json:"block_unknown,omitempty"` // cgroup v2 reported no io.stat entries, so block IO was not measured
+
+	SampledAt time.Time     `json:"sampled_at"`        // When the daemon took the reading
+	Average   *StatsAverage `json:"average,omitempty"` // Smoothed values, set by the stats cache
+}
+
+// StatsAverage holds moving averages of a container's usage over recent stats cache cycles.
+type StatsAverage struct {
+	CPUPercent    float64  `json:"cpu_percent"`
+	MemoryPercent *float64 `json:"memory_percent"` // Null when no averaged reading had a memory limit
+	Samples       int      `json:"samples"`        // Cache cycles averaged; fewer than the window right after start
 }
 
 // DashboardSummary represents aggregate resource usage statistics.
@@ -117,16 +221,20 @@ func (s *ContainerService) ListContainers(ctx context.Context, opts ContainerLis
 		log.Printf("Failed to list containers: %v", err)
 		return nil, fmt.Errorf("failed to list containers: %w", err)
 	}
+	containers = s.scope.Filter(containers)
 
 	var result []ContainerInfo
-	var runningContainers []int // Track indices of running containers
 
 	for _, c := range containers {
+		if !opts.CreatedSince.IsZero() && c.Created < opts.CreatedSince.Unix() {
+			continue
+		}
+
 		// Apply name filter if specified
 		if opts.Filter != "" {
 			matched := false
 			for _, name := range c.Names {
-				if strings.Contains(strings.ToLower(name), strings.ToLower(opts.Filter)) {
+				if strings.Contains(strings.ToLower(containerDisplayName(name)), strings.ToLower(opts.Filter)) {
 					matched = true
 					break
 				}
@@ -139,31 +247,205 @@ func (s *ContainerService) ListContainers(ctx context.Context, opts ContainerLis
 		info := s.convertToContainerInfo(c)
 		result = append(result, info)
 
-		// Track running containers for stats
-		if c.State == "running" {
-			runningContainers = append(runningContainers, len(result)-1)
+		// Apply limit if specified; start times are only known after inspecting, so
+		// the limit is applied once they have been filtered
+		if opts.StartedSince.IsZero() && opts.Limit > 0 && len(result) >= opts.Limit {
+			break
 		}
+	}
 
-		// Apply limit if specified
-		if opts.Limit > 0 && len(result) >= opts.Limit {
-			break
+	if !opts.StartedSince.IsZero() {
+		result = s.filterStartedSince(ctx, result, opts.StartedSince)
+		if opts.Limit > 0 && len(result) > opts.Limit {
+			result = result[:opts.Limit]
+		}
+	}
+
+	// Track running containers for stats
+	var runningContainers []int
+	for i := range result {
+		if result[i].State == "running" {
+			runningContainers = append(runningContainers, i)
 		}
 	}
 
 	// Use cached stats if requested (instant response, no loader!)
-	if opts.IncludeStats && len(runningContainers) > 0 {
-		cachedStats := s.statsCache.GetAllContainerStats()
+	if opts.IncludeStats {
+		for i := range result {
+			result[i].StatsStatus = StatsUnavailable
+		}
 
+		cachedStats := s.statsCache.GetAllContainerStats()
+		var missing []int
 		for _, idx := range runningContainers {
 			if stats, ok := cachedStats[result[idx].ID]; ok {
+				if !opts.StatsAverage && stats.Average != nil {
+					instant := *stats
+					instant.Average = nil
+					stats = &instant
+				}
 				result[idx].Stats = stats
+				result[idx].StatsStatus = StatsAvailable
+			} else {
+				result[idx].StatsStatus = StatsPending
+				missing = append(missing, idx)
 			}
 		}
+
+		// Right after startup the cache is empty; optionally sample this page directly
+		if opts.FetchStats && len(missing) > 0 && !s.statsCache.IsWarm() {
+			s.fetchMissingStats(ctx, result, missing)
+		}
 	}
 
 	return result, nil
 }
 
+// filterStartedSince keeps containers last started at or after since, most recently
+// started first. Start times are read by inspecting each container concurrently
+// within the client's inspect limit; containers that cannot be inspected are dropped.
+func (s *ContainerService) filterStartedSince(ctx context.Context, containers []ContainerInfo, since time.Time) []ContainerInfo {
+	var wg sync.WaitGroup
+
+	for i := range containers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			containerJSON, err := s.dockerClient.ContainerInspect(ctx, containers[i].ID)
+			if err != nil {
+				log.Printf("Failed to inspect container %s for start time: %v", containers[i].Name, err)
+				return
+			}
+			if containerJSON.State == nil {
+				return
+			}
+			if startedAt := parseTimeString(containerJSON.State.StartedAt); !startedAt.IsZero() {
+				containers[i].StartedAt = &startedAt
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	result := []ContainerInfo{}
+	for _, info := range containers {
+		if info.StartedAt != nil && !info.StartedAt.Before(since) {
+			result = append(result, info)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].StartedAt.After(*result[j].StartedAt)
+	})
+
+	return result
+}
+
+// fetchMissingStats fetches stats directly for the containers at the given indices.
+// Containers whose stats cannot be fetched are left pending.
+func (s *ContainerService) fetchMissingStats(ctx context.Context, result []ContainerInfo, indices []int) {
+	var wg sync.WaitGroup
+
+	for _, idx := range indices {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+
+			stats, err := s.getContainerStats(ctx, result[idx].ID)
+			if err != nil {
+				log.Printf("Failed to fetch stats for container %s: %v", result[idx].Name, err)
+				return
+			}
+			result[idx].Stats = stats
+			result[idx].StatsStatus = StatsAvailable
+		}(idx)
+	}
+	wg.Wait()
+}
+
+// ContainerSearchOptions represents criteria for searching containers by configuration.
+type ContainerSearchOptions struct {
+	Env   string // Environment variable to match ("KEY" or "KEY=value")
+	Label string // Label to match ("key" or "key=value")
+	All   bool   // Include stopped containers
+	Limit int    // Maximum number of matches to return
+}
+
+// SearchContainers finds containers whose labels and/or environment match the given options.
+// Label matching is delegated to the Docker daemon; environment matching requires inspecting
+// each candidate, which is done concurrently within the client's inspect limit.
+func (s *ContainerService) SearchContainers(ctx context.Context, opts ContainerSearchOptions) ([]ContainerInfo, error) {
+	listOpts := container.ListOptions{
+		All: opts.All,
+	}
+	if opts.Label != "" {
+		listOpts.Filters = filters.NewArgs(filters.Arg("label", opts.Label))
+	}
+
+	containers, err := s.dockerClient.ContainerList(ctx, listOpts)
+	if err != nil {
+		log.Printf("Failed to list containers for search: %v", err)
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	containers = s.scope.Filter(containers)
+
+	matched := make([]bool, len(containers))
+	if opts.Env == "" {
+		for i := range matched {
+			matched[i] = true
+		}
+	} else {
+		var wg sync.WaitGroup
+
+		for i, c := range containers {
+			wg.Add(1)
+			go func(i int, containerID string) {
+				defer wg.Done()
+
+				containerJSON, err := s.dockerClient.ContainerInspect(ctx, containerID)
+				if err != nil {
+					log.Printf("Failed to inspect container %s during search: %v", containerID, err)
+					return
+				}
+				if containerJSON.Config != nil {
+					matched[i] = matchEnv(containerJSON.Config.Env, opts.Env)
+				}
+			}(i, c.ID)
+		}
+		wg.Wait()
+	}
+
+	result := []ContainerInfo{}
+	for i, c := range containers {
+		if !matched[i] {
+			continue
+		}
+		result = append(result, s.convertToContainerInfo(c))
+		if opts.Limit > 0 && len(result) >= opts.Limit {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// matchEnv reports whether env contains the given "KEY" or "KEY=value" entry.
+func matchEnv(env []string, query string) bool {
+	for _, entry := range env {
+		if strings.Contains(query, "=") {
+			if entry == query {
+				return true
+			}
+			continue
+		}
+		key, _, _ := strings.Cut(entry, "=")
+		if key == query {
+			return true
+		}
+	}
+	return false
+}
+
 // GetContainer retrieves detailed information about a specific container.
 func (s *ContainerService) GetContainer(ctx context.Context, containerID string) (*ContainerInfo, error) {
 	// Get container JSON (detailed info)
@@ -173,18 +455,37 @@ func (s *ContainerService) GetContainer(ctx context.Context, containerID string)
 		return nil, fmt.Errorf("failed to inspect container: %w", err)
 	}
 
-	// Build container info
+	info := containerInfoFromJSON(containerJSON)
+
+	// Get stats if container is running
+	if containerJSON.State.Running {
+		stats, err := s.getContainerStats(ctx, containerID)
+		if err != nil {
+			log.Printf("Failed to get stats for container %s: %v", containerID, err)
+		} else {
+			info.Stats = stats
+		}
+	}
+
+	return info, nil
+}
+
+// containerInfoFromJSON builds the details of an inspected container, without stats.
+// Names go through containerDisplayName, as in convertToContainerInfo, so list and
+// detail responses agree.
+func containerInfoFromJSON(containerJSON types.ContainerJSON) *ContainerInfo {
 	info := &ContainerInfo{
 		ID:          containerJSON.ID,
-		Name:        containerJSON.Name,
+		Name:        containerDisplayName(containerJSON.Name),
 		Image:       containerJSON.Config.Image,
 		ImageID:     containerJSON.Image,
-		Command:     containerJSON.Path,
+		Command:     formatCommand(containerJSON.Path, containerJSON.Args),
 		Created:     parseTime(containerJSON.Created),
 		State:       containerJSON.State.Status,
 		Status:      formatStatus(containerJSON.State),
 		Labels:      containerJSON.Config.Labels,
 		NetworkMode: string(containerJSON.HostConfig.NetworkMode),
+		CommandArgs: commandArgs(containerJSON.Path, containerJSON.Args),
 	}
 
 	// Parse ports
@@ -209,29 +510,33 @@ func (s *ContainerService) GetContainer(ctx context.Context, containerID string)
 		}
 	}
 
-	// Parse mounts
-	for _, mount := range containerJSON.Mounts {
-		info.Mounts = append(info.Mounts, MountInfo{
-			Type:        string(mount.Type),
-			Name:        mount.Name,
-			Source:      mount.Source,
-			Destination: mount.Destination,
-			Mode:        mount.Mode,
-			RW:          mount.RW,
-		})
+	// Parse mounts; consistency is only recorded in the requested mounts
+	consistency := make(map[string]mount.Consistency)
+	if containerJSON.HostConfig != nil {
+		for _, m := range containerJSON.HostConfig.Mounts {
+			consistency[m.Target] = m.Consistency
+		}
+	}
+	for _, m := range containerJSON.Mounts {
+		mountInfo := newMountInfo(m)
+		mountInfo.Consistency = string(consistency[m.Destination])
+		info.Mounts = append(info.Mounts, mountInfo)
 	}
 
-	// Get stats if container is running
-	if containerJSON.State.Running {
-		stats, err := s.getContainerStats(ctx, containerID)
-		if err != nil {
-			log.Printf("Failed to get stats for container %s: %v", containerID, err)
-		} else {
-			info.Stats = stats
+	// Assigned devices and GPU requests
+	info.Devices, info.DeviceRequests = deviceSpecsFromHostConfig(containerJSON.HostConfig)
+	info.Ulimits = ulimitSpecsFromHostConfig(containerJSON.HostConfig)
+	info.LogDriver = logDriverFromHostConfig(containerJSON.HostConfig)
+
+	// The daemon only records the OOM kill; the kill time is when the container finished
+	if containerJSON.State.OOMKilled {
+		info.OOMKilled = true
+		if killedAt := parseTimeString(containerJSON.State.FinishedAt); !killedAt.IsZero() {
+			info.OOMKilledAt = &killedAt
 		}
 	}
 
-	return info, nil
+	return info
 }
 
 // StartContainer starts a stopped container.
@@ -241,23 +546,72 @@ func (s *ContainerService) StartContainer(ctx context.Context, containerID strin
 	if err != nil {
 		return s.logAction("start", "container", containerID, "", false, err)
 	}
+	name := containerDisplayName(containerJSON.Name)
 
 	// Start the container
 	err = s.dockerClient.ContainerStart(ctx, containerID, container.StartOptions{})
 	if err != nil {
-		return s.logAction("start", "container", containerID, containerJSON.Name, false, err)
+		return s.logAction("start", "container", containerID, name, false, err)
 	}
 
-	log.Printf("Container %s started successfully", containerJSON.Name)
-	return s.logAction("start", "container", containerID, containerJSON.Name, true, nil)
+	log.Printf("Container %s started successfully", name)
+	return s.logAction("start", "container", containerID, name, true, nil)
 }
 
 // StopContainer stops a running container.
 func (s *ContainerService) StopContainer(ctx context.Context, containerID string) error {
+	_, err := s.StopContainerWithOptions(ctx, containerID, StopOptions{})
+	return err
+}
+
+// StopOptions controls how a container is stopped.
+type StopOptions struct {
+	DisableRestart bool // Set the restart policy to "no" before stopping
+}
+
+// StopResult reports restart policy details of a stopped container.
+type StopResult struct {
+	RestartPolicy         string `json:"restart_policy"`
+	RestartPolicyDisabled bool   `json:"restart_policy_disabled,omitempty"`
+	PreviousRestartPolicy string `json:"previous_restart_policy,omitempty"`
+	Warning               string `json:"warning,omitempty"`
+}
+
+// StopContainerWithOptions stops a container and reports its restart policy.
+// Containers with an "always" or "on-failure" policy may come back on their own
+// ("always" when the daemon restarts, "on-failure" if the stop exits non-zero), so
+// the result carries a warning unless DisableRestart switched the policy to "no" first.
+// The previous policy is returned so it can be restored later. If the stop fails, the
+// previous policy is restored straight away.
+func (s *ContainerService) StopContainerWithOptions(ctx context.Context, containerID string, opts StopOptions) (*StopResult, error) {
 	// Get container name for logging
 	containerJSON, err := s.dockerClient.ContainerInspect(ctx, containerID)
 	if err != nil {
-		return s.logAction("stop", "container", containerID, "", false, err)
+		return nil, s.logAction("stop", "container", containerID, "", false, err)
+	}
+	name := containerDisplayName(containerJSON.Name)
+
+	policy := container.RestartPolicyMode("no")
+	if containerJSON.HostConfig != nil && containerJSON.HostConfig.RestartPolicy.Name != "" {
+		policy = containerJSON.HostConfig.RestartPolicy.Name
+	}
+	result := &StopResult{RestartPolicy: string(policy)}
+
+	restarts := policy == container.RestartPolicyAlways || policy == container.RestartPolicyOnFailure
+	if restarts && opts.DisableRestart {
+		_, err := s.dockerClient.ContainerUpdate(ctx, containerID, container.UpdateConfig{
+			RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyDisabled},
+		})
+		if err != nil {
+			log.Printf("Failed to disable restart policy for container %s: %v", name, err)
+			return nil, s.logAction("stop", "container", containerID, name, false, fmt.Errorf("failed to disable restart policy: %w", err))
+		}
+		log.Printf("Restart policy of container %s changed from %s to no", name, policy)
+		result.RestartPolicy = string(container.RestartPolicyDisabled)
+		result.RestartPolicyDisabled = true
+		result.PreviousRestartPolicy = string(policy)
+	} else if restarts {
+		result.Warning = fmt.Sprintf("container has restart policy %q and may be started again automatically; stop with disable_restart=true to prevent this", policy)
 	}
 
 	// Stop the container with 10 second timeout
@@ -266,11 +620,28 @@ func (s *ContainerService) StopContainer(ctx context.Context, containerID string
 		Timeout: &timeout,
 	})
 	if err != nil {
-		return s.logAction("stop", "container", containerID, containerJSON.Name, false, err)
+		if result.RestartPolicyDisabled {
+			s.restoreRestartPolicy(containerID, name, containerJSON.HostConfig.RestartPolicy)
+		}
+		return nil, s.logAction("stop", "container", containerID, name, false, err)
 	}
 
-	log.Printf("Container %s stopped successfully", containerJSON.Name)
-	return s.logAction("stop", "container", containerID, containerJSON.Name, true, nil)
+	log.Printf("Container %s stopped successfully", name)
+	return result, s.logAction("stop", "container", containerID, name, true, nil)
+}
+
+// restoreRestartPolicy puts back a restart policy that was disabled for a stop that
+// then failed. It uses its own context, as the stop may have failed because the
+// request's context expired.
+func (s *ContainerService) restoreRestartPolicy(containerID, name string, policy container.RestartPolicy) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := s.dockerClient.ContainerUpdate(ctx, containerID, container.UpdateConfig{RestartPolicy: policy}); err != nil {
+		log.Printf("Failed to restore restart policy %s of container %s after a failed stop: %v", policy.Name, name, err)
+		return
+	}
+	log.Printf("Restart policy of container %s restored to %s after a failed stop", name, policy.Name)
 }
 
 // RestartContainer restarts a container.
@@ -280,6 +651,7 @@ func (s *ContainerService) RestartContainer(ctx context.Context, containerID str
 	if err != nil {
 		return s.logAction("restart", "container", containerID, "", false, err)
 	}
+	name := containerDisplayName(containerJSON.Name)
 
 	// Restart the container with 10 second timeout
 	timeout := 10
@@ -287,11 +659,11 @@ func (s *ContainerService) RestartContainer(ctx context.Context, containerID str
 		Timeout: &timeout,
 	})
 	if err != nil {
-		return s.logAction("restart", "container", containerID, containerJSON.Name, false, err)
+		return s.logAction("restart", "container", containerID, name, false, err)
 	}
 
-	log.Printf("Container %s restarted successfully", containerJSON.Name)
-	return s.logAction("restart", "container", containerID, containerJSON.Name, true, nil)
+	log.Printf("Container %s restarted successfully", name)
+	return s.logAction("restart", "container", containerID, name, true, nil)
 }
 
 // RemoveContainer removes a container (must be stopped first unless force is true).
@@ -301,6 +673,7 @@ func (s *ContainerService) RemoveContainer(ctx context.Context, containerID stri
 	if err != nil {
 		return s.logAction("remove", "container", containerID, "", false, err)
 	}
+	name := containerDisplayName(containerJSON.Name)
 
 	// Remove the container
 	err = s.dockerClient.ContainerRemove(ctx, containerID, container.RemoveOptions{
@@ -308,11 +681,11 @@ func (s *ContainerService) RemoveContainer(ctx context.Context, containerID stri
 		RemoveVolumes: false,
 	})
 	if err != nil {
-		return s.logAction("remove", "container", containerID, containerJSON.Name, false, err)
+		return s.logAction("remove", "container", containerID, name, false, err)
 	}
 
-	log.Printf("Container %s removed successfully", containerJSON.Name)
-	return s.logAction("remove", "container", containerID, containerJSON.Name, true, nil)
+	log.Printf("Container %s removed successfully", name)
+	return s.logAction("remove", "container", containerID, name, true, nil)
 }
 
 // logAction logs an action to the database.
@@ -337,17 +710,38 @@ func (s *ContainerService) logAction(actionType, resourceType, resourceID, resou
 	return err
 }
 
-// getContainerStats retrieves current statistics for a container.
-func (s *ContainerService) getContainerStats(ctx context.Context, containerID string) (*ContainerStats, error) {
-	statsResponse, err := s.dockerClient.ContainerStats(ctx, containerID, false)
+// maxStatsDrain bounds how much of an undecodable stats body is discarded before closing.
+const maxStatsDrain = 64 * 1024
+
+// readContainerStats fetches and decodes a single stats sample for a container.
+// The response body is closed on every path, and drained first so the underlying
+// connection can be reused even when decoding fails part-way through.
+func readContainerStats(ctx context.Context, dockerClient *docker.Client, containerID string) (*container.StatsResponse, error) {
+	statsResponse, err := dockerClient.ContainerStats(ctx, containerID, false)
 	if err != nil {
 		return nil, err
 	}
-	defer statsResponse.Body.Close()
+	return decodeStatsBody(statsResponse.Body)
+}
+
+// decodeStatsBody decodes a single stats sample, then drains and closes body.
+func decodeStatsBody(body io.ReadCloser) (*container.StatsResponse, error) {
+	defer func() {
+		io.Copy(io.Discard, io.LimitReader(body, maxStatsDrain))
+		body.Close()
+	}()
 
-	// Read stats
 	statsJSON := &container.StatsResponse{}
-	if err := decodeStats(statsResponse.Body, statsJSON); err != nil {
+	if err := decodeStats(body, statsJSON); err != nil {
+		return nil, fmt.Errorf("failed to decode stats: %w", err)
+	}
+	return statsJSON, nil
+}
+
+// getContainerStats retrieves current statistics for a container.
+func (s *ContainerService) getContainerStats(ctx context.Context, containerID string) (*ContainerStats, error) {
+	statsJSON, err := readContainerStats(ctx, s.dockerClient, containerID)
+	if err != nil {
 		return nil, err
 	}
 
@@ -355,17 +749,39 @@ func (s *ContainerService) getContainerStats(ctx context.Context, containerID st
 	cpuPercent := statsutil.CalculateCPUPercent(statsJSON)
 	memoryUsage := statsJSON.MemoryStats.Usage
 	memoryLimit := statsJSON.MemoryStats.Limit
-	memoryPercent := float64(memoryUsage) / float64(memoryLimit) * 100.0
+	memory := statsutil.GetMemoryBreakdown(statsJSON)
+
+	// Without a limit of its own the container reports the host's memory as its limit
+	hostMemory, err := s.dockerClient.HostMemory(ctx)
+	if err != nil {
+		log.Printf("Failed to get host memory: %v", err)
+	}
+	var memoryPercent *float64
+	if percent, limited := statsutil.MemoryPercent(memoryUsage, memoryLimit, hostMemory); limited {
+		memoryPercent = &percent
+	}
 
 	stats := &ContainerStats{
 		CPUPercent:    cpuPercent,
 		MemoryUsage:   memoryUsage,
 		MemoryLimit:   memoryLimit,
 		MemoryPercent: memoryPercent,
+		MemorySwap:    memory.Swap,
+		MemoryRSS:     memory.RSS,
+		MemoryCache:   memory.Cache,
+		MemoryAnon:    memory.Anon,
+		MemoryFile:    memory.File,
 		NetworkRx:     statsutil.GetNetworkRx(statsJSON),
 		NetworkTx:     statsutil.GetNetworkTx(statsJSON),
 		BlockRead:     statsutil.GetBlockRead(statsJSON),
 		BlockWrite:    statsutil.GetBlockWrite(statsJSON),
+		SampledAt:     statsJSON.Read,
+
+		MemoryUnlimited: memoryPercent == nil,
+		BlockUnknown:    !statsutil.BlockIOAvailable(statsJSON),
+	}
+	if stats.SampledAt.IsZero() {
+		stats.SampledAt = time.Now()
 	}
 
 	return stats, nil
@@ -373,13 +789,40 @@ func (s *ContainerService) getContainerStats(ctx context.Context, containerID st
 
 // Helper functions
 
+// containerDisplayName strips the leading slash Docker adds to container names.
+// All names returned by the API and recorded in action logs go through this.
+func containerDisplayName(name string) string {
+	return strings.TrimPrefix(name, "/")
+}
+
+// commandArgs returns a container's executable followed by its arguments.
+// The executable is the entrypoint when one is set, so it is already included.
+func commandArgs(path string, args []string) []string {
+	if path == "" {
+		return args
+	}
+	return append([]string{path}, args...)
+}
+
+// formatCommand joins a container's executable and arguments like `docker ps` does.
+// Arguments that are empty or contain whitespace or quotes are quoted to keep the
+// boundaries readable.
+func formatCommand(path string, args []string) string {
+	parts := commandArgs(path, args)
+	quoted := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part == "" || strings.ContainsAny(part, " \t\n\"'") {
+			part = strconv.Quote(part)
+		}
+		quoted = append(quoted, part)
+	}
+	return strings.Join(quoted, " ")
+}
+
 func (s *ContainerService) convertToContainerInfo(c types.Container) ContainerInfo {
 	name := ""
 	if len(c.Names) > 0 {
-		name = c.Names[0]
-		if len(name) > 0 && name[0] == '/' {
-			name = name[1:]
-		}
+		name = containerDisplayName(c.Names[0])
 	}
 
 	info := ContainerInfo{
@@ -405,15 +848,8 @@ func (s *ContainerService) convertToContainerInfo(c types.Container) ContainerIn
 	}
 
 	// Parse mounts
-	for _, mount := range c.Mounts {
-		info.Mounts = append(info.Mounts, MountInfo{
-			Type:        string(mount.Type),
-			Name:        mount.Name,
-			Source:      mount.Source,
-			Destination: mount.Destination,
-			Mode:        mount.Mode,
-			RW:          mount.RW,
-		})
+	for _, m := range c.Mounts {
+		info.Mounts = append(info.Mounts, newMountInfo(m))
 	}
 
 	return info
@@ -478,41 +914,164 @@ type BulkOperationResult struct {
 	Error         string `json:"error,omitempty"`
 }
 
-// BulkStartContainers starts multiple containers in parallel.
-func (s *ContainerService) BulkStartContainers(ctx context.Context, containerIDs []string) []BulkOperationResult {
+// reportBulkResult sends a finished bulk result to the progress channel, if one was given.
+func reportBulkResult(progress chan<- BulkOperationResult, result BulkOperationResult) {
+	if progress != nil {
+		progress <- result
+	}
+}
+
+// BulkStartOptions controls the order of a bulk start.
+type BulkStartOptions struct {
+	// Dependencies maps a container (name or ID) to the containers it depends on.
+	// They are merged with Compose depends_on labels; containers outside the
+	// request are ignored.
+	Dependencies map[string][]string
+	WaitTimeout  time.Duration // How long to wait for a dependency to be running and healthy
+}
+
+// BulkStartContainers starts multiple containers in dependency order and returns the
+// results with the resolved order, as levels of container names. Containers in a level
+// start in parallel, at most the configured bulk concurrency at a time; a level starts
+// once every dependency in earlier levels is running and passing its healthcheck, so
+// containers without dependencies all start in the first level.
+// Dependents of a container that fails to start or become ready are not started.
+// If progress is non-nil, each result is also sent to it as soon as it completes.
+func (s *ContainerService) BulkStartContainers(ctx context.Context, containerIDs []string, opts BulkStartOptions, progress chan<- BulkOperationResult) ([]BulkOperationResult, [][]string) {
 	results := make([]BulkOperationResult, len(containerIDs))
 
+	// Resolve the requested containers; index maps full IDs back to results
+	index := make(map[string]int)
+	names := make(map[string]string)
+	labels := make(map[string]map[string]string)
+	var ids []string
+
 	for i, containerID := range containerIDs {
 		result := BulkOperationResult{
 			ContainerID: containerID,
 		}
 
-		// Get container name
 		containerJSON, err := s.dockerClient.ContainerInspect(ctx, containerID)
-		if err == nil {
-			result.ContainerName = containerJSON.Name
+		if err != nil {
+			result.Error = s.logAction("start", "container", containerID, "", false, err).Error()
+			results[i] = result
+			reportBulkResult(progress, result)
+			continue
 		}
+		result.ContainerName = containerDisplayName(containerJSON.Name)
+		if !s.scope.Allows(containerJSON.Name) {
+			result.Error = fmt.Errorf("%w: %s", ErrContainerOutOfScope, result.ContainerName).Error()
+			results[i] = result
+			reportBulkResult(progress, result)
+			continue
+		}
+		results[i] = result
 
-		// Start container
-		err = s.StartContainer(ctx, containerID)
-		if err != nil {
-			result.Success = false
-			result.Error = err.Error()
-		} else {
-			result.Success = true
+		if _, duplicate := index[containerJSON.ID]; duplicate {
+			result.Error = "container listed more than once"
+			results[i] = result
+			reportBulkResult(progress, result)
+			continue
 		}
+		index[containerJSON.ID] = i
+		names[containerJSON.ID] = result.ContainerName
+		if containerJSON.Config != nil {
+			labels[containerJSON.ID] = containerJSON.Config.Labels
+		}
+		ids = append(ids, containerJSON.ID)
+	}
 
-		results[i] = result
+	deps := startDependencies(ids, names, labels, opts.Dependencies)
+	levels := dependencyLevels(ids, deps)
+
+	hasDependents := make(map[string]bool)
+	for _, list := range deps {
+		for _, dep := range list {
+			hasDependents[dep] = true
+		}
 	}
 
-	return results
+	var mu sync.Mutex
+	notReady := make(map[string]string) // Container ID -> why its dependents cannot start
+
+	order := make([][]string, 0, len(levels))
+	sem := make(chan struct{}, s.bulkConcurrency())
+	for _, level := range levels {
+		levelNames := make([]string, 0, len(level))
+		var wg sync.WaitGroup
+
+		for _, id := range level {
+			levelNames = append(levelNames, names[id])
+
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(id string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				result := results[index[id]]
+
+				mu.Lock()
+				for _, dep := range deps[id] {
+					if reason, failed := notReady[dep]; failed {
+						result.Error = fmt.Sprintf("dependency %s %s", names[dep], reason)
+						break
+					}
+				}
+				mu.Unlock()
+
+				if result.Error != "" {
+					mu.Lock()
+					notReady[id] = "was not started"
+					mu.Unlock()
+				} else if err := s.StartContainer(ctx, id); err != nil {
+					result.Error = err.Error()
+					mu.Lock()
+					notReady[id] = "failed to start"
+					mu.Unlock()
+				} else {
+					result.Success = true
+					if hasDependents[id] {
+						if reason := s.waitDependencyReady(ctx, id, opts.WaitTimeout); reason != "" {
+							mu.Lock()
+							notReady[id] = reason
+							mu.Unlock()
+						}
+					}
+				}
+
+				results[index[id]] = result
+				reportBulkResult(progress, result)
+			}(id)
+		}
+		wg.Wait()
+
+		order = append(order, levelNames)
+	}
+
+	return results, order
 }
 
-// BulkStopContainers stops multiple containers in parallel.
-func (s *ContainerService) BulkStopContainers(ctx context.Context, containerIDs []string) []BulkOperationResult {
+// waitDependencyReady waits for a started dependency to be running and healthy and
+// returns why it is not, or "" once it is ready.
+func (s *ContainerService) waitDependencyReady(ctx context.Context, containerID string, timeout time.Duration) string {
+	readiness, err := s.CheckReady(ctx, containerID, nil, timeout)
+	if err != nil {
+		return fmt.Sprintf("could not be checked: %v", err)
+	}
+	if !readiness.Ready {
+		return fmt.Sprintf("did not become ready within %v: %s", timeout, strings.Join(readiness.Reasons, ", "))
+	}
+	return ""
+}
+
+// BulkStopContainers stops multiple containers in parallel, at most the configured
+// bulk concurrency at a time. Results keep the order of containerIDs.
+// If progress is non-nil, each result is also sent to it as soon as it completes.
+func (s *ContainerService) BulkStopContainers(ctx context.Context, containerIDs []string, progress chan<- BulkOperationResult) []BulkOperationResult {
 	results := make([]BulkOperationResult, len(containerIDs))
 
-	for i, containerID := range containerIDs {
+	s.runBulk(len(containerIDs), func(i int) {
+		containerID := containerIDs[i]
 		result := BulkOperationResult{
 			ContainerID: containerID,
 		}
@@ -520,7 +1079,13 @@ func (s *ContainerService) BulkStopContainers(ctx context.Context, containerIDs
 		// Get container name
 		containerJSON, err := s.dockerClient.ContainerInspect(ctx, containerID)
 		if err == nil {
-			result.ContainerName = containerJSON.Name
+			result.ContainerName = containerDisplayName(containerJSON.Name)
+			if !s.scope.Allows(containerJSON.Name) {
+				result.Error = fmt.Errorf("%w: %s", ErrContainerOutOfScope, result.ContainerName).Error()
+				results[i] = result
+				reportBulkResult(progress, result)
+				return
+			}
 		}
 
 		// Stop container
@@ -533,16 +1098,20 @@ func (s *ContainerService) BulkStopContainers(ctx context.Context, containerIDs
 		}
 
 		results[i] = result
-	}
+		reportBulkResult(progress, result)
+	})
 
 	return results
 }
 
-// BulkRemoveContainers removes multiple containers in parallel.
-func (s *ContainerService) BulkRemoveContainers(ctx context.Context, containerIDs []string, force bool) []BulkOperationResult {
+// BulkRemoveContainers removes multiple containers in parallel, at most the configured
+// bulk concurrency at a time. Results keep the order of containerIDs.
+// If progress is non-nil, each result is also sent to it as soon as it completes.
+func (s *ContainerService) BulkRemoveContainers(ctx context.Context, containerIDs []string, force bool, progress chan<- BulkOperationResult) []BulkOperationResult {
 	results := make([]BulkOperationResult, len(containerIDs))
 
-	for i, containerID := range containerIDs {
+	s.runBulk(len(containerIDs), func(i int) {
+		containerID := containerIDs[i]
 		result := BulkOperationResult{
 			ContainerID: containerID,
 		}
@@ -550,7 +1119,13 @@ func (s *ContainerService) BulkRemoveContainers(ctx context.Context, containerID
 		// Get container name
 		containerJSON, err := s.dockerClient.ContainerInspect(ctx, containerID)
 		if err == nil {
-			result.ContainerName = containerJSON.Name
+			result.ContainerName = containerDisplayName(containerJSON.Name)
+			if !s.scope.Allows(containerJSON.Name) {
+				result.Error = fmt.Errorf("%w: %s", ErrContainerOutOfScope, result.ContainerName).Error()
+				results[i] = result
+				reportBulkResult(progress, result)
+				return
+			}
 		}
 
 		// Remove container
@@ -563,11 +1138,42 @@ func (s *ContainerService) BulkRemoveContainers(ctx context.Context, containerID
 		}
 
 		results[i] = result
-	}
+		reportBulkResult(progress, result)
+	})
 
 	return results
 }
 
+// runBulk calls fn for each index from 0 to n-1, running at most the configured bulk
+// concurrency at a time, and returns once every call has finished.
+func (s *ContainerService) runBulk(n int, fn func(i int)) {
+	sem := make(chan struct{}, s.bulkConcurrency())
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// bulkConcurrency returns how many items of a bulk operation may run at once.
+func (s *ContainerService) bulkConcurrency() int {
+	if s.bulkCfg.Concurrency < 1 {
+		return 1
+	}
+	return s.bulkCfg.Concurrency
+}
+
+// GetCachedStats returns the cached stats of all running containers, keyed by container ID.
+func (s *ContainerService) GetCachedStats() map[string]*ContainerStats {
+	return s.statsCache.GetAllContainerStats()
+}
+
 // GetDashboardSummary retrieves aggregate resource usage statistics for running containers.
 func (s *ContainerService) GetDashboardSummary(ctx context.Context) (*DashboardSummary, error) {
 	// Return cached summary (instant response!)
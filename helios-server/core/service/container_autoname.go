@@ -0,0 +1,55 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path"
+	"regexp"
+
+	"github.com/distribution/reference"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+)
+
+// autoNameBase derives a container name from an image reference: the last path
+// component of the repository ("ghcr.io/acme/api:1.2" gives "api"), after the
+// scope's name prefix so the container stays visible to a scoped instance.
+// Image IDs, which have no repository, give "container".
+func autoNameBase(imageRef, prefix string) string {
+	name := "container"
+	if named, err := reference.ParseNormalizedNamed(imageRef); err == nil {
+		name = path.Base(reference.Path(named))
+	}
+	return prefix + name
+}
+
+// nextAvailableName returns base if no container (running or not) has that name,
+// otherwise the first free name of the form base-2, base-3, ...
+func (s *ContainerService) nextAvailableName(ctx context.Context, base string) (string, error) {
+	containers, err := s.dockerClient.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("name", "^/"+regexp.QuoteMeta(base)+"(-[0-9]+)?$")),
+	})
+	if err != nil {
+		log.Printf("Failed to list containers named %s: %v", base, err)
+		return "", fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	taken := make(map[string]bool)
+	for _, c := range containers {
+		for _, name := range c.Names {
+			taken[containerDisplayName(name)] = true
+		}
+	}
+
+	if !taken[base] {
+		return base, nil
+	}
+	for n := 2; ; n++ {
+		if candidate := fmt.Sprintf("%s-%d", base, n); !taken[candidate] {
+			return candidate, nil
+		}
+	}
+}
@@ -0,0 +1,634 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/go-connections/nat"
+	"github.com/docker/go-units"
+)
+
+// MountSpec describes a volume or bind mount requested at container creation.
+type MountSpec struct {
+	Type     string `json:"type"`   // "bind" or "volume" (default "volume")
+	Source   string `json:"source"` // Host path for binds, volume name for volumes
+	Target   string `json:"target" binding:"required"`
+	ReadOnly bool   `json:"read_only"`
+}
+
+// MountValidationError describes a problem with a single requested mount.
+type MountValidationError struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Reason string `json:"reason"`
+}
+
+// MountValidationErrors collects all problems found while validating mounts.
+type MountValidationErrors []MountValidationError
+
+func (e MountValidationErrors) Error() string {
+	problems := make([]string, 0, len(e))
+	for _, m := range e {
+		problems = append(problems, fmt.Sprintf("%s -> %s: %s", m.Source, m.Target, m.Reason))
+	}
+	return "invalid mounts: " + strings.Join(problems, "; ")
+}
+
+// ValidateBindMounts checks that every bind mount source is an absolute path that exists.
+// Docker silently creates missing bind sources as empty directories, which hides typos,
+// so this is run before ContainerCreate unless the caller opts out.
+// Paths are checked from the Helios process's point of view, so the host paths must be
+// visible to Helios (e.g. mounted at the same location) for the check to be meaningful;
+// otherwise turn it off with HELIOS_VALIDATE_BIND_MOUNTS=false, or per request with
+// skip_mount_validation.
+// Returns nil if all mounts are valid.
+func ValidateBindMounts(mounts []MountSpec) error {
+	var problems MountValidationErrors
+
+	for _, m := range mounts {
+		if m.Type != "bind" {
+			continue
+		}
+
+		if m.Source == "" {
+			problems = append(problems, MountValidationError{Source: m.Source, Target: m.Target, Reason: "source path is required"})
+			continue
+		}
+
+		if !filepath.IsAbs(m.Source) {
+			problems = append(problems, MountValidationError{Source: m.Source, Target: m.Target, Reason: "source path must be absolute"})
+			continue
+		}
+
+		if _, err := os.Stat(m.Source); err != nil {
+			reason := "source path is not accessible"
+			if os.IsNotExist(err) {
+				reason = "source path does not exist"
+			}
+			problems = append(problems, MountValidationError{Source: m.Source, Target: m.Target, Reason: reason})
+		}
+	}
+
+	if len(problems) > 0 {
+		return problems
+	}
+	return nil
+}
+
+// DeviceRequestSpec requests devices from a device driver, as with "docker run --gpus".
+type DeviceRequestSpec struct {
+	Driver       string            `json:"driver,omitempty"`       // Device driver, e.g. "nvidia" (default: chosen by capabilities)
+	Count        int               `json:"count,omitempty"`        // Number of devices, -1 for all
+	DeviceIDs    []string          `json:"device_ids,omitempty"`   // Specific device IDs, exclusive with Count
+	Capabilities [][]string        `json:"capabilities,omitempty"` // OR list of AND lists (default: [["gpu"]])
+	Options      map[string]string `json:"options,omitempty"`
+}
+
+// DeviceMappingSpec maps a host device into the container, as with "docker run --device".
+type DeviceMappingSpec struct {
+	PathOnHost        string `json:"path_on_host" binding:"required"`
+	PathInContainer   string `json:"path_in_container,omitempty"`  // Defaults to PathOnHost
+	CgroupPermissions string `json:"cgroup_permissions,omitempty"` // Any of "rwm" (default "rwm")
+}
+
+// BuildDeviceRequests converts device request specs to Docker device requests.
+// A request without capabilities asks for GPUs.
+func BuildDeviceRequests(specs []DeviceRequestSpec) ([]container.DeviceRequest, error) {
+	requests := make([]container.DeviceRequest, 0, len(specs))
+
+	for i, spec := range specs {
+		if spec.Count < -1 {
+			return nil, fmt.Errorf("device request %d: count must be -1 (all) or a positive number", i)
+		}
+		if spec.Count != 0 && len(spec.DeviceIDs) > 0 {
+			return nil, fmt.Errorf("device request %d: count and device_ids are mutually exclusive", i)
+		}
+
+		count := spec.Count
+		if count == 0 && len(spec.DeviceIDs) == 0 {
+			count = -1
+		}
+
+		capabilities := spec.Capabilities
+		if len(capabilities) == 0 {
+			capabilities = [][]string{{"gpu"}}
+		}
+
+		requests = append(requests, container.DeviceRequest{
+			Driver:       spec.Driver,
+			Count:        count,
+			DeviceIDs:    spec.DeviceIDs,
+			Capabilities: capabilities,
+			Options:      spec.Options,
+		})
+	}
+
+	return requests, nil
+}
+
+// BuildDeviceMappings converts device mapping specs to Docker device mappings,
+// checking that each host device exists.
+func BuildDeviceMappings(specs []DeviceMappingSpec) ([]container.DeviceMapping, error) {
+	mappings := make([]container.DeviceMapping, 0, len(specs))
+
+	for _, spec := range specs {
+		if !filepath.IsAbs(spec.PathOnHost) {
+			return nil, fmt.Errorf("device %s: host path must be absolute", spec.PathOnHost)
+		}
+		if _, err := os.Stat(spec.PathOnHost); err != nil {
+			return nil, fmt.Errorf("device %s: not available on host: %w", spec.PathOnHost, err)
+		}
+
+		permissions := spec.CgroupPermissions
+		if permissions == "" {
+			permissions = "rwm"
+		}
+		if strings.Trim(permissions, "rwm") != "" {
+			return nil, fmt.Errorf("device %s: cgroup permissions must only contain r, w and m", spec.PathOnHost)
+		}
+
+		target := spec.PathInContainer
+		if target == "" {
+			target = spec.PathOnHost
+		}
+
+		mappings = append(mappings, container.DeviceMapping{
+			PathOnHost:        spec.PathOnHost,
+			PathInContainer:   target,
+			CgroupPermissions: permissions,
+		})
+	}
+
+	return mappings, nil
+}
+
+// ErrNoGPURuntime is returned when GPUs are requested but the daemon has no GPU support configured.
+var ErrNoGPURuntime = errors.New("GPU requested but the Docker daemon has no NVIDIA runtime or CDI specs configured")
+
+// validateDeviceRequests checks GPU requests against the daemon's configuration.
+// The daemon only reports whether GPU support is configured, not how many devices
+// are free, so counts are left for the daemon to enforce at start.
+func (s *ContainerService) validateDeviceRequests(ctx context.Context, requests []container.DeviceRequest) error {
+	wantsGPU := false
+	for _, req := range requests {
+		for _, caps := range req.Capabilities {
+			for _, c := range caps {
+				if c == "gpu" {
+					wantsGPU = true
+				}
+			}
+		}
+	}
+	if !wantsGPU {
+		return nil
+	}
+
+	info, err := s.dockerClient.Info(ctx)
+	if err != nil {
+		log.Printf("Failed to query daemon info for device validation: %v", err)
+		return fmt.Errorf("failed to query daemon info: %w", err)
+	}
+
+	if _, ok := info.Runtimes["nvidia"]; ok {
+		return nil
+	}
+	if len(info.CDISpecDirs) > 0 {
+		return nil
+	}
+	return ErrNoGPURuntime
+}
+
+// deviceSpecsFromHostConfig reports the devices assigned to a container.
+func deviceSpecsFromHostConfig(hostConfig *container.HostConfig) ([]DeviceMappingSpec, []DeviceRequestSpec) {
+	if hostConfig == nil {
+		return nil, nil
+	}
+
+	var devices []DeviceMappingSpec
+	for _, d := range hostConfig.Devices {
+		devices = append(devices, DeviceMappingSpec{
+			PathOnHost:        d.PathOnHost,
+			PathInContainer:   d.PathInContainer,
+			CgroupPermissions: d.CgroupPermissions,
+		})
+	}
+
+	var requests []DeviceRequestSpec
+	for _, r := range hostConfig.DeviceRequests {
+		requests = append(requests, DeviceRequestSpec{
+			Driver:       r.Driver,
+			Count:        r.Count,
+			DeviceIDs:    r.DeviceIDs,
+			Capabilities: r.Capabilities,
+			Options:      r.Options,
+		})
+	}
+
+	return devices, requests
+}
+
+// UlimitSpec sets a resource limit for the container's processes, as with "docker run --ulimit".
+type UlimitSpec struct {
+	Name string `json:"name" binding:"required"` // e.g. "nofile", "nproc", "memlock"
+	Soft int64  `json:"soft"`                    // -1 for unlimited
+	Hard int64  `json:"hard"`                    // -1 for unlimited
+}
+
+// validUlimits lists the resource names accepted by the daemon.
+var validUlimits = map[string]bool{
+	"as": true, "core": true, "cpu": true, "data": true, "fsize": true, "locks": true,
+	"memlock": true, "msgqueue": true, "nice": true, "nofile": true, "nproc": true,
+	"rss": true, "rtprio": true, "rttime": true, "sigpending": true, "stack": true,
+}
+
+// BuildUlimits converts ulimit specs to Docker ulimits.
+// Names must be known resource names, each name may appear once, and the soft
+// limit may not exceed the hard limit (-1 meaning unlimited).
+func BuildUlimits(specs []UlimitSpec) ([]*units.Ulimit, error) {
+	ulimits := make([]*units.Ulimit, 0, len(specs))
+	seen := make(map[string]bool, len(specs))
+
+	for _, spec := range specs {
+		name := strings.ToLower(spec.Name)
+		if !validUlimits[name] {
+			return nil, fmt.Errorf("ulimit %q: unknown resource name", spec.Name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("ulimit %q: specified more than once", name)
+		}
+		seen[name] = true
+
+		if spec.Soft < -1 || spec.Hard < -1 {
+			return nil, fmt.Errorf("ulimit %q: limits must be -1 (unlimited) or non-negative", name)
+		}
+		if spec.Hard != -1 && (spec.Soft == -1 || spec.Soft > spec.Hard) {
+			return nil, fmt.Errorf("ulimit %q: soft limit %d exceeds hard limit %d", name, spec.Soft, spec.Hard)
+		}
+
+		ulimits = append(ulimits, &units.Ulimit{Name: name, Soft: spec.Soft, Hard: spec.Hard})
+	}
+
+	return ulimits, nil
+}
+
+// ulimitSpecsFromHostConfig reports the ulimits set on a container.
+// Resources not listed use the daemon's default ulimits.
+func ulimitSpecsFromHostConfig(hostConfig *container.HostConfig) []UlimitSpec {
+	if hostConfig == nil {
+		return nil
+	}
+
+	var specs []UlimitSpec
+	for _, u := range hostConfig.Ulimits {
+		if u == nil {
+			continue
+		}
+		specs = append(specs, UlimitSpec{Name: u.Name, Soft: u.Soft, Hard: u.Hard})
+	}
+	return specs
+}
+
+// Name conflict policies for container creation.
+const (
+	OnConflictError          = "error"           // Fail with ErrNameConflict (default)
+	OnConflictReturnExisting = "return_existing" // Return the existing container unchanged
+	OnConflictReplace        = "replace"         // Remove the existing container, then create
+)
+
+// ErrNameConflict is returned when a container with the requested name already exists.
+var ErrNameConflict = errors.New("a container with this name already exists")
+
+// NameConflictError identifies the existing container, running or stopped, that
+// holds a requested name. It matches ErrNameConflict with errors.Is. The container
+// ID and state are left empty when the container is outside the container scope.
+type NameConflictError struct {
+	Name        string `json:"name"`
+	ContainerID string `json:"container_id,omitempty"`
+	State       string `json:"state,omitempty"`
+}
+
+func (e *NameConflictError) Error() string {
+	if e.ContainerID == "" {
+		return fmt.Sprintf("%v: %s", ErrNameConflict, e.Name)
+	}
+	return fmt.Sprintf("%v: %s (%s, %s)", ErrNameConflict, e.Name, ShortID(e.ContainerID), e.State)
+}
+
+func (e *NameConflictError) Unwrap() error {
+	return ErrNameConflict
+}
+
+// ErrReplaceNotConfirmed is returned when on_conflict is "replace" without confirmation.
+var ErrReplaceNotConfirmed = errors.New("replacing an existing container requires confirmation")
+
+// ValidateConflictPolicy checks an on_conflict value; an empty value means OnConflictError.
+func ValidateConflictPolicy(policy string) error {
+	switch policy {
+	case "", OnConflictError, OnConflictReturnExisting, OnConflictReplace:
+		return nil
+	}
+	return fmt.Errorf("invalid on_conflict %q: must be %s, %s or %s",
+		policy, OnConflictError, OnConflictReturnExisting, OnConflictReplace)
+}
+
+// resolveNameConflict applies the on_conflict policy before creating a container named name.
+// Existing containers are looked up first, including stopped ones, rather than relying
+// on the daemon's error. It returns the existing container when the policy is
+// OnConflictReturnExisting, so the caller can return it instead of creating one;
+// otherwise it returns nil once the name is free, or a *NameConflictError.
+// Replacing requires confirmed to be set, since the existing container is force-removed.
+// A container outside the container scope is never returned or removed; it is reported
+// as a plain conflict without its ID or state.
+func (s *ContainerService) resolveNameConflict(ctx context.Context, name, policy string, confirmed bool) (*ContainerInfo, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	containers, err := s.dockerClient.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("name", "^/"+regexp.QuoteMeta(name)+"$")),
+	})
+	if err != nil {
+		log.Printf("Failed to check for existing container %s: %v", name, err)
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	conflict := s.nameConflict(containers, name)
+	if conflict == nil {
+		return nil, nil
+	}
+	if conflict.ContainerID == "" {
+		return nil, conflict
+	}
+	existingID := conflict.ContainerID
+
+	switch policy {
+	case OnConflictReturnExisting:
+		return s.GetContainer(ctx, existingID)
+	case OnConflictReplace:
+		if !confirmed {
+			return nil, fmt.Errorf("%w: container %s (%s) would be removed", ErrReplaceNotConfirmed, name, ShortID(existingID))
+		}
+		log.Printf("Replacing existing container %s (%s)", name, ShortID(existingID))
+		if err := s.RemoveContainer(ctx, existingID, true); err != nil {
+			return nil, fmt.Errorf("failed to remove existing container: %w", err)
+		}
+		return nil, nil
+	default:
+		return nil, conflict
+	}
+}
+
+// nameConflict returns a *NameConflictError for the container in containers named
+// exactly name, or nil if there is none. Names that merely contain name do not conflict.
+// The ID and state are left out for a container outside the container scope.
+func (s *ContainerService) nameConflict(containers []types.Container, name string) *NameConflictError {
+	for _, c := range containers {
+		for _, n := range c.Names {
+			if n != "/"+name {
+				continue
+			}
+			if !s.scope.AllowsAny(c.Names) {
+				return &NameConflictError{Name: name}
+			}
+			return &NameConflictError{Name: name, ContainerID: c.ID, State: c.State}
+		}
+	}
+	return nil
+}
+
+// ValidatesMounts reports whether bind mount sources are checked when creating req:
+// unless turned off for the instance or for the request.
+func (s *ContainerService) ValidatesMounts(req CreateContainerRequest) bool {
+	return s.validateBindMounts && !req.SkipMountValidation
+}
+
+// validateScopedName checks that the container will be named within the container scope.
+// Without a name the daemon picks a random one, so a name is required when scoped unless
+// auto_name derives one from the scope's prefix.
+func (s *ContainerService) validateScopedName(req CreateContainerRequest) error {
+	if req.Name == "" && req.AutoName {
+		return nil
+	}
+	if !s.scope.Allows(req.Name) {
+		if req.Name == "" {
+			return fmt.Errorf("a name starting with %q is required (or set auto_name)", s.scope.Prefix())
+		}
+		return fmt.Errorf("name %q must start with %q", req.Name, s.scope.Prefix())
+	}
+	return nil
+}
+
+// ErrInvalidContainerSpec is returned when a create request fails validation.
+var ErrInvalidContainerSpec = errors.New("invalid container specification")
+
+// ErrImageNotPresent is returned when creating a container from an image that has not been pulled.
+var ErrImageNotPresent = errors.New("image is not present locally")
+
+// CreateContainerRequest describes a container to create.
+type CreateContainerRequest struct {
+	Image               string              `json:"image" binding:"required"`
+	Name                string              `json:"name"`
+	Env                 []string            `json:"env"`            // KEY=value entries
+	Cmd                 []string            `json:"cmd"`            // Overrides the image's CMD
+	Entrypoint          []string            `json:"entrypoint"`     // Overrides the image's ENTRYPOINT
+	Ports               []string            `json:"ports"`          // [ip:]host:container[/proto], as with "docker run -p"
+	RestartPolicy       string              `json:"restart_policy"` // no, always, unless-stopped or on-failure[:max-retries]
+	Labels              map[string]string   `json:"labels"`
+	Mounts              []MountSpec         `json:"mounts" binding:"dive"`
+	Devices             []DeviceMappingSpec `json:"devices" binding:"dive"`
+	DeviceRequests      []DeviceRequestSpec `json:"device_requests"`
+	Ulimits             []UlimitSpec        `json:"ulimits" binding:"dive"`
+	OnConflict          string              `json:"on_conflict"`           // error (default), return_existing or replace
+	AutoName            bool                `json:"auto_name"`             // Pick a free name: the given one or one derived from the image, suffixed -2, -3, ... if taken
+	ConfirmReplace      bool                `json:"confirm_replace"`       // Required with on_conflict=replace
+	SkipMountValidation bool                `json:"skip_mount_validation"` // Do not check that bind mount sources exist, e.g. when host paths are not visible to Helios
+	Start               bool                `json:"start"`                 // Start the container once created
+}
+
+// CreateContainer validates the request and creates the container, starting it if
+// req.Start is set. With on_conflict=return_existing an existing container of the same
+// name is returned instead. The image must already be present locally; otherwise
+// ErrImageNotPresent is returned. Validation failures match ErrInvalidContainerSpec.
+// If the container is created but fails to start, it is returned along with the error.
+// With req.AutoName set, a taken name is replaced by the next free numbered variant
+// instead of conflicting; the chosen name is the returned container's name.
+func (s *ContainerService) CreateContainer(ctx context.Context, req CreateContainerRequest) (*ContainerInfo, error) {
+	invalid := func(err error) error {
+		return s.logAction("create", "container", "", req.Name, false, fmt.Errorf("%w: %w", ErrInvalidContainerSpec, err))
+	}
+
+	if err := ValidateConflictPolicy(req.OnConflict); err != nil {
+		return nil, invalid(err)
+	}
+	if req.AutoName && req.OnConflict != "" && req.OnConflict != OnConflictError {
+		return nil, invalid(fmt.Errorf("auto_name cannot be combined with on_conflict=%s", req.OnConflict))
+	}
+	if err := s.validateScopedName(req); err != nil {
+		return nil, invalid(err)
+	}
+	if s.ValidatesMounts(req) {
+		if err := ValidateBindMounts(req.Mounts); err != nil {
+			return nil, invalid(err)
+		}
+	}
+	exposedPorts, portBindings, err := nat.ParsePortSpecs(req.Ports)
+	if err != nil {
+		return nil, invalid(fmt.Errorf("invalid ports: %w", err))
+	}
+	restartPolicy, err := parseRestartPolicy(req.RestartPolicy)
+	if err != nil {
+		return nil, invalid(err)
+	}
+
+	devices, err := BuildDeviceMappings(req.Devices)
+	if err != nil {
+		return nil, invalid(err)
+	}
+	deviceRequests, err := BuildDeviceRequests(req.DeviceRequests)
+	if err != nil {
+		return nil, invalid(err)
+	}
+	if err := s.validateDeviceRequests(ctx, deviceRequests); err != nil {
+		if errors.Is(err, ErrNoGPURuntime) {
+			return nil, invalid(err)
+		}
+		return nil, s.logAction("create", "container", "", req.Name, false, err)
+	}
+	ulimits, err := BuildUlimits(req.Ulimits)
+	if err != nil {
+		return nil, invalid(err)
+	}
+
+	if _, _, err := s.dockerClient.ImageInspectWithRaw(ctx, req.Image); err != nil {
+		if errdefs.IsNotFound(err) {
+			err = fmt.Errorf("%w: %s; pull it first with POST /helios/images/pull", ErrImageNotPresent, req.Image)
+		} else {
+			log.Printf("Failed to inspect image %s: %v", req.Image, err)
+			err = fmt.Errorf("failed to inspect image: %w", err)
+		}
+		return nil, s.logAction("create", "container", "", req.Name, false, err)
+	}
+
+	if req.AutoName {
+		base := req.Name
+		if base == "" {
+			base = autoNameBase(req.Image, s.scope.Prefix())
+		}
+		if req.Name, err = s.nextAvailableName(ctx, base); err != nil {
+			return nil, s.logAction("create", "container", "", base, false, err)
+		}
+	}
+
+	existing, err := s.resolveNameConflict(ctx, req.Name, req.OnConflict, req.ConfirmReplace)
+	if err != nil {
+		return nil, s.logAction("create", "container", "", req.Name, false, err)
+	}
+	if existing != nil {
+		log.Printf("Container %s already exists, returning it", req.Name)
+		return existing, nil
+	}
+
+	config := &container.Config{
+		Image:        req.Image,
+		Env:          req.Env,
+		Cmd:          req.Cmd,
+		Entrypoint:   req.Entrypoint,
+		ExposedPorts: exposedPorts,
+		Labels:       s.labels.Apply(req.Labels),
+	}
+	hostConfig := &container.HostConfig{
+		PortBindings:  portBindings,
+		RestartPolicy: restartPolicy,
+		Mounts:        buildMounts(req.Mounts),
+		Resources: container.Resources{
+			Devices:        devices,
+			DeviceRequests: deviceRequests,
+			Ulimits:        ulimits,
+		},
+	}
+
+	created, err := s.dockerClient.ContainerCreate(ctx, config, hostConfig, nil, nil, req.Name)
+	if err != nil {
+		log.Printf("Failed to create container %s from %s: %v", req.Name, req.Image, err)
+		return nil, s.logAction("create", "container", "", req.Name, false, fmt.Errorf("failed to create container: %w", err))
+	}
+	for _, warning := range created.Warnings {
+		log.Printf("Warning while creating container %s: %s", req.Name, warning)
+	}
+
+	name := req.Name
+	if name == "" {
+		name = ShortID(created.ID)
+	}
+	log.Printf("Container %s created from %s (ID: %s)", name, req.Image, ShortID(created.ID))
+	s.logAction("create", "container", created.ID, name, true, nil)
+
+	var startErr error
+	if req.Start {
+		startErr = s.StartContainer(ctx, created.ID)
+	}
+
+	info, err := s.GetContainer(ctx, created.ID)
+	if err != nil {
+		return nil, err
+	}
+	if startErr != nil {
+		return info, fmt.Errorf("container created but failed to start: %w", startErr)
+	}
+	return info, nil
+}
+
+// parseRestartPolicy parses a restart policy as accepted by "docker run --restart".
+// An empty policy means "no".
+func parseRestartPolicy(raw string) (container.RestartPolicy, error) {
+	policy := container.RestartPolicy{Name: container.RestartPolicyDisabled}
+	if raw == "" {
+		return policy, nil
+	}
+
+	name, retries, hasRetries := strings.Cut(raw, ":")
+	policy.Name = container.RestartPolicyMode(name)
+	if hasRetries {
+		n, err := strconv.Atoi(retries)
+		if err != nil || n < 0 {
+			return policy, fmt.Errorf("invalid restart policy %q: maximum retries must be a non-negative number", raw)
+		}
+		policy.MaximumRetryCount = n
+	}
+
+	if err := container.ValidateRestartPolicy(policy); err != nil {
+		return policy, fmt.Errorf("invalid restart policy %q: %w", raw, err)
+	}
+	return policy, nil
+}
+
+// buildMounts converts mount specs to Docker mounts; the type defaults to volume.
+func buildMounts(specs []MountSpec) []mount.Mount {
+	mounts := make([]mount.Mount, 0, len(specs))
+	for _, spec := range specs {
+		mountType := mount.TypeVolume
+		if spec.Type == "bind" {
+			mountType = mount.TypeBind
+		}
+		mounts = append(mounts, mount.Mount{
+			Type:     mountType,
+			Source:   spec.Source,
+			Target:   spec.Target,
+			ReadOnly: spec.ReadOnly,
+		})
+	}
+	return mounts
+}
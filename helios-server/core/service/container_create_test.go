@@ -0,0 +1,97 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+)
+
+func TestNameConflict(t *testing.T) {
+	containers := []types.Container{
+		{ID: "1111111111110000000000000000000000000000000000000000000000000000", Names: []string{"/web-1"}, State: "running"},
+		{ID: "2222222222220000000000000000000000000000000000000000000000000000", Names: []string{"/web"}, State: "exited"},
+		{ID: "3333333333330000000000000000000000000000000000000000000000000000", Names: []string{"/other"}, State: "running"},
+	}
+
+	tests := []struct {
+		name      string
+		scope     *ContainerScope
+		requested string
+		want      *NameConflictError
+		wantError string
+	}{
+		{
+			name:      "stopped container holds the name",
+			requested: "web",
+			want:      &NameConflictError{Name: "web", ContainerID: containers[1].ID, State: "exited"},
+			wantError: "a container with this name already exists: web (222222222222, exited)",
+		},
+		{
+			name:      "running container holds the name",
+			requested: "web-1",
+			want:      &NameConflictError{Name: "web-1", ContainerID: containers[0].ID, State: "running"},
+			wantError: "a container with this name already exists: web-1 (111111111111, running)",
+		},
+		{name: "name is a prefix of another", requested: "we"},
+		{name: "name is free", requested: "db"},
+		{
+			name:      "holder outside the scope",
+			scope:     NewContainerScope("team-a-"),
+			requested: "other",
+			want:      &NameConflictError{Name: "other"},
+			wantError: "a container with this name already exists: other",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ContainerService{scope: tt.scope}
+			got := s.nameConflict(containers, tt.requested)
+
+			if tt.want == nil {
+				if got != nil {
+					t.Fatalf("nameConflict(%q) = %+v, want nil", tt.requested, got)
+				}
+				return
+			}
+			if got == nil || *got != *tt.want {
+				t.Fatalf("nameConflict(%q) = %+v, want %+v", tt.requested, got, tt.want)
+			}
+			if got.Error() != tt.wantError {
+				t.Errorf("Error() = %q, want %q", got.Error(), tt.wantError)
+			}
+			if !errors.Is(got, ErrNameConflict) {
+				t.Error("conflict does not match ErrNameConflict")
+			}
+		})
+	}
+}
+
+func TestNameConflictErrorJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *NameConflictError
+		want string
+	}{
+		{
+			name: "in scope",
+			err:  &NameConflictError{Name: "web", ContainerID: "abc", State: "exited"},
+			want: `{"name":"web","container_id":"abc","state":"exited"}`,
+		},
+		{name: "out of scope", err: &NameConflictError{Name: "web"}, want: `{"name":"web"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := json.Marshal(tt.err)
+			if err != nil {
+				t.Fatalf("Marshal error = %v", err)
+			}
+			if string(encoded) != tt.want {
+				t.Errorf("Marshal = %s, want %s", encoded, tt.want)
+			}
+		})
+	}
+}
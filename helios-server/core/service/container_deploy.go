@@ -0,0 +1,41 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/errdefs"
+)
+
+// EnsureImage pulls imageRef unless it is already present locally, forwarding pull
+// progress to progress. It reports whether the image was pulled.
+func (s *ContainerService) EnsureImage(ctx context.Context, imageRef string, progress chan<- PullProgress) (bool, error) {
+	_, _, err := s.dockerClient.ImageInspectWithRaw(ctx, imageRef)
+	if err == nil {
+		return false, nil
+	}
+	if !errdefs.IsNotFound(err) {
+		log.Printf("Failed to inspect image %s: %v", imageRef, err)
+		return false, fmt.Errorf("failed to inspect image: %w", err)
+	}
+
+	log.Printf("Image %s not present locally, pulling", imageRef)
+	reader, err := s.dockerClient.ImagePull(ctx, imageRef, image.PullOptions{
+		RegistryAuth: s.credentials.RegistryAuth(imageRef),
+	})
+	if err != nil {
+		log.Printf("Failed to start pull for image %s: %v", imageRef, err)
+		return false, s.logAction("pull", "image", imageRef, imageRef, false, fmt.Errorf("failed to pull image: %w", err))
+	}
+	if err := streamPullProgress(ctx, reader, progress); err != nil {
+		log.Printf("Failed to pull image %s: %v", imageRef, err)
+		return false, s.logAction("pull", "image", imageRef, imageRef, false, fmt.Errorf("failed to pull image: %w", err))
+	}
+
+	log.Printf("Successfully pulled image: %s", imageRef)
+	s.logAction("pull", "image", imageRef, imageRef, true, nil)
+	return true, nil
+}
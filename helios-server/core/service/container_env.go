@@ -0,0 +1,117 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+// redactedValue replaces the value of environment variables that look sensitive.
+const redactedValue = "********"
+
+// sensitiveEnvMarkers are name fragments that mark an environment variable as sensitive.
+var sensitiveEnvMarkers = []string{"PASSWORD", "PASSWD", "SECRET", "TOKEN", "CREDENTIAL", "PRIVATE", "APIKEY", "API_KEY", "ACCESS_KEY", "AUTH"}
+
+// EnvVar is one environment variable of a container compared with its image.
+type EnvVar struct {
+	Name       string `json:"name"`
+	Value      string `json:"value"`
+	ImageValue string `json:"image_value,omitempty"` // Image default, set for overridden variables
+	Redacted   bool   `json:"redacted,omitempty"`
+}
+
+// EnvDiff splits a container's environment into variables it adds, variables
+// whose image default it overrides, and variables inherited unchanged from the image.
+type EnvDiff struct {
+	ContainerID string   `json:"container_id"`
+	Name        string   `json:"name"`
+	Image       string   `json:"image"`
+	Added       []EnvVar `json:"added"`
+	Overridden  []EnvVar `json:"overridden"`
+	Inherited   []EnvVar `json:"inherited"`
+}
+
+// DiffContainerEnv compares a container's environment with its image's defaults.
+// Values of variables whose names look sensitive are redacted.
+func (s *ContainerService) DiffContainerEnv(ctx context.Context, containerID string) (*EnvDiff, error) {
+	containerJSON, err := s.dockerClient.ContainerInspect(ctx, containerID)
+	if err != nil {
+		log.Printf("Failed to inspect container %s: %v", containerID, err)
+		return nil, fmt.Errorf("failed to inspect container: %w", err)
+	}
+
+	imageInspect, _, err := s.dockerClient.ImageInspectWithRaw(ctx, containerJSON.Image)
+	if err != nil {
+		log.Printf("Failed to inspect image %s of container %s: %v", containerJSON.Image, containerID, err)
+		return nil, fmt.Errorf("failed to inspect image: %w", err)
+	}
+
+	var containerEnv, imageEnv []string
+	image := containerJSON.Image
+	if containerJSON.Config != nil {
+		containerEnv = containerJSON.Config.Env
+		image = containerJSON.Config.Image
+	}
+	if imageInspect.Config != nil {
+		imageEnv = imageInspect.Config.Env
+	}
+
+	imageValues := envMap(imageEnv)
+	diff := &EnvDiff{
+		ContainerID: containerJSON.ID,
+		Name:        containerDisplayName(containerJSON.Name),
+		Image:       image,
+		Added:       []EnvVar{},
+		Overridden:  []EnvVar{},
+		Inherited:   []EnvVar{},
+	}
+
+	for name, value := range envMap(containerEnv) {
+		imageValue, inImage := imageValues[name]
+		v := EnvVar{Name: name, Value: value}
+		switch {
+		case !inImage:
+			diff.Added = append(diff.Added, redactEnv(v))
+		case imageValue != value:
+			v.ImageValue = imageValue
+			diff.Overridden = append(diff.Overridden, redactEnv(v))
+		default:
+			diff.Inherited = append(diff.Inherited, redactEnv(v))
+		}
+	}
+
+	for _, vars := range [][]EnvVar{diff.Added, diff.Overridden, diff.Inherited} {
+		sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+	}
+
+	return diff, nil
+}
+
+// envMap converts "KEY=value" entries to a map; later entries win.
+func envMap(env []string) map[string]string {
+	values := make(map[string]string, len(env))
+	for _, entry := range env {
+		key, value, _ := strings.Cut(entry, "=")
+		values[key] = value
+	}
+	return values
+}
+
+// redactEnv hides the values of a variable whose name looks sensitive.
+func redactEnv(v EnvVar) EnvVar {
+	upper := strings.ToUpper(v.Name)
+	for _, marker := range sensitiveEnvMarkers {
+		if strings.Contains(upper, marker) {
+			v.Value = redactedValue
+			if v.ImageValue != "" {
+				v.ImageValue = redactedValue
+			}
+			v.Redacted = true
+			break
+		}
+	}
+	return v
+}
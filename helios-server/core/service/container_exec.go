@@ -0,0 +1,231 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"nfcunha/helios/utils/docker"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+)
+
+// execExitPollInterval is how often a finished exec is inspected until its exit code is known.
+const execExitPollInterval = 50 * time.Millisecond
+
+// ExecOptions controls how a one-off command runs inside a container.
+type ExecOptions struct {
+	Tty        bool   // Allocate a TTY; output is then a single unframed stream
+	User       string // User to run as; defaults to the container's user
+	WorkingDir string // Working directory; defaults to the container's
+}
+
+// ExecResult is the buffered outcome of a one-off command.
+type ExecResult struct {
+	ExecID     string `json:"exec_id"`
+	ExitCode   int    `json:"exit_code"`
+	Output     string `json:"output"`              // Combined stdout and stderr
+	Truncated  bool   `json:"truncated,omitempty"` // Output exceeded the configured limit
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// ExecContainer runs cmd in a running container, waits for it to exit and returns its
+// combined stdout and stderr with the exit code. Output beyond the configured limit is
+// read but discarded, so the command is not blocked on a full pipe. The command itself
+// is not written to the action log, as it may carry secrets.
+func (s *ContainerService) ExecContainer(ctx context.Context, containerID string, cmd []string, opts ExecOptions) (*ExecResult, error) {
+	containerJSON, err := s.dockerClient.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, s.logAction("exec", "container", containerID, "", false, err)
+	}
+	name := containerDisplayName(containerJSON.Name)
+	if containerJSON.State == nil || !containerJSON.State.Running {
+		return nil, s.logAction("exec", "container", containerJSON.ID, name, false, fmt.Errorf("%w: %s", ErrContainerNotRunning, name))
+	}
+	start := time.Now()
+
+	created, err := s.dockerClient.ContainerExecCreate(ctx, containerJSON.ID, container.ExecOptions{
+		Cmd:          cmd,
+		Tty:          opts.Tty,
+		User:         opts.User,
+		WorkingDir:   opts.WorkingDir,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		log.Printf("Failed to create exec in container %s: %v", name, err)
+		return nil, s.logAction("exec", "container", containerJSON.ID, name, false, fmt.Errorf("failed to create exec: %w", err))
+	}
+
+	attach, err := s.dockerClient.ContainerExecAttach(ctx, created.ID, container.ExecAttachOptions{Tty: opts.Tty})
+	if err != nil {
+		log.Printf("Failed to attach to exec in container %s: %v", name, err)
+		return nil, s.logAction("exec", "container", containerJSON.ID, name, false, fmt.Errorf("failed to attach to exec: %w", err))
+	}
+	defer attach.Close()
+
+	// Close the connection when the request ends so a command that never exits does not hold it
+	stop := context.AfterFunc(ctx, attach.Close)
+	defer stop()
+
+	output := &cappedBuffer{limit: int(s.execCfg.MaxOutput)}
+	if opts.Tty {
+		_, err = io.Copy(output, attach.Reader)
+	} else {
+		err = copyMultiplexed(output, attach.Reader)
+	}
+	if err != nil && ctx.Err() == nil {
+		log.Printf("Failed to read exec output in container %s: %v", name, err)
+		return nil, s.logAction("exec", "container", containerJSON.ID, name, false, fmt.Errorf("failed to read exec output: %w", err))
+	}
+
+	// The output stream can end just before the daemon records the exit code
+	var inspect container.ExecInspect
+	for {
+		inspect, err = s.dockerClient.ContainerExecInspect(ctx, created.ID)
+		if err != nil {
+			log.Printf("Failed to inspect exec in container %s: %v", name, err)
+			return nil, s.logAction("exec", "container", containerJSON.ID, name, false, fmt.Errorf("failed to inspect exec: %w", err))
+		}
+		if !inspect.Running {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, s.logAction("exec", "container", containerJSON.ID, name, false, fmt.Errorf("command did not finish: %w", ctx.Err()))
+		case <-time.After(execExitPollInterval):
+		}
+	}
+
+	log.Printf("Exec in container %s exited with code %d", name, inspect.ExitCode)
+	s.logAction("exec", "container", containerJSON.ID, name, true, nil)
+
+	return &ExecResult{
+		ExecID:     created.ID,
+		ExitCode:   inspect.ExitCode,
+		Output:     string(output.data),
+		Truncated:  output.truncated,
+		DurationMs: time.Since(start).Milliseconds(),
+	}, nil
+}
+
+// cappedBuffer keeps the first limit bytes written to it and discards the rest.
+type cappedBuffer struct {
+	data      []byte
+	limit     int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - len(b.data); room < len(p) {
+		b.data = append(b.data, p[:max(room, 0)]...)
+		b.truncated = true
+		return len(p), nil
+	}
+	b.data = append(b.data, p...)
+	return len(p), nil
+}
+
+// ExecSession is an interactive command running in a container with a TTY and stdin
+// attached. Reads return the terminal output and writes go to the command's stdin.
+type ExecSession struct {
+	ID            string
+	ContainerID   string
+	ContainerName string
+
+	dockerClient *docker.Client
+	hijacked     types.HijackedResponse
+}
+
+// StreamExec starts cmd in a running container with a TTY and stdin attached and
+// returns the session bridging to it. The caller must Close the session.
+func (s *ContainerService) StreamExec(ctx context.Context, containerID string, cmd []string, opts ExecOptions) (*ExecSession, error) {
+	containerJSON, err := s.dockerClient.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, s.logAction("exec", "container", containerID, "", false, err)
+	}
+	name := containerDisplayName(containerJSON.Name)
+	if containerJSON.State == nil || !containerJSON.State.Running {
+		return nil, s.logAction("exec", "container", containerJSON.ID, name, false, fmt.Errorf("%w: %s", ErrContainerNotRunning, name))
+	}
+
+	created, err := s.dockerClient.ContainerExecCreate(ctx, containerJSON.ID, container.ExecOptions{
+		Cmd:          cmd,
+		Tty:          true,
+		User:         opts.User,
+		WorkingDir:   opts.WorkingDir,
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		log.Printf("Failed to create exec in container %s: %v", name, err)
+		return nil, s.logAction("exec", "container", containerJSON.ID, name, false, fmt.Errorf("failed to create exec: %w", err))
+	}
+
+	hijacked, err := s.dockerClient.ContainerExecAttach(ctx, created.ID, container.ExecAttachOptions{Tty: true})
+	if err != nil {
+		log.Printf("Failed to attach to exec in container %s: %v", name, err)
+		return nil, s.logAction("exec", "container", containerJSON.ID, name, false, fmt.Errorf("failed to attach to exec: %w", err))
+	}
+
+	log.Printf("Interactive exec started in container %s", name)
+	s.logAction("exec", "container", containerJSON.ID, name, true, nil)
+
+	return &ExecSession{
+		ID:            created.ID,
+		ContainerID:   containerJSON.ID,
+		ContainerName: name,
+		dockerClient:  s.dockerClient,
+		hijacked:      hijacked,
+	}, nil
+}
+
+// Read reads terminal output from the command.
+func (e *ExecSession) Read(p []byte) (int, error) {
+	return e.hijacked.Reader.Read(p)
+}
+
+// Write sends input to the command's stdin.
+func (e *ExecSession) Write(p []byte) (int, error) {
+	return e.hijacked.Conn.Write(p)
+}
+
+// Resize sets the size of the command's terminal.
+func (e *ExecSession) Resize(ctx context.Context, cols, rows uint) error {
+	if err := e.dockerClient.ContainerExecResize(ctx, e.ID, container.ResizeOptions{Width: cols, Height: rows}); err != nil {
+		return fmt.Errorf("failed to resize exec terminal: %w", err)
+	}
+	return nil
+}
+
+// ExitCode returns the command's exit code, waiting briefly for the daemon to record it
+// once the output has ended.
+func (e *ExecSession) ExitCode(ctx context.Context) (int, error) {
+	for {
+		inspect, err := e.dockerClient.ContainerExecInspect(ctx, e.ID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to inspect exec: %w", err)
+		}
+		if !inspect.Running {
+			return inspect.ExitCode, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(execExitPollInterval):
+		}
+	}
+}
+
+// Close closes the connection to the command. A shell still running ends when its
+// terminal goes away.
+func (e *ExecSession) Close() {
+	e.hijacked.Close()
+}
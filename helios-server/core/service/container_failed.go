@@ -0,0 +1,86 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+)
+
+// ListFailedContainers lists stopped containers that exited with a non-zero code,
+// most recently finished first. Exit code and finish time are read by inspecting each
+// exited container, which is done concurrently within the client's inspect limit.
+func (s *ContainerService) ListFailedContainers(ctx context.Context, opts ContainerListOptions) ([]ContainerInfo, error) {
+	listOpts := container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("status", "exited")),
+	}
+
+	containers, err := s.dockerClient.ContainerList(ctx, listOpts)
+	if err != nil {
+		log.Printf("Failed to list exited containers: %v", err)
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	containers = s.scope.Filter(containers)
+
+	var candidates []ContainerInfo
+	for _, c := range containers {
+		info := s.convertToContainerInfo(c)
+		if opts.Filter != "" && !strings.Contains(strings.ToLower(info.Name), strings.ToLower(opts.Filter)) {
+			continue
+		}
+		candidates = append(candidates, info)
+	}
+
+	var wg sync.WaitGroup
+
+	for i := range candidates {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			containerJSON, err := s.dockerClient.ContainerInspect(ctx, candidates[i].ID)
+			if err != nil {
+				log.Printf("Failed to inspect exited container %s: %v", candidates[i].Name, err)
+				return
+			}
+			if containerJSON.State == nil {
+				return
+			}
+
+			exitCode := containerJSON.State.ExitCode
+			candidates[i].ExitCode = &exitCode
+			if finishedAt := parseTimeString(containerJSON.State.FinishedAt); !finishedAt.IsZero() {
+				candidates[i].FinishedAt = &finishedAt
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	result := []ContainerInfo{}
+	for _, info := range candidates {
+		if info.ExitCode != nil && *info.ExitCode != 0 {
+			result = append(result, info)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i].FinishedAt, result[j].FinishedAt
+		if a == nil || b == nil {
+			return b == nil && a != nil
+		}
+		return a.After(*b)
+	})
+
+	if opts.Limit > 0 && len(result) > opts.Limit {
+		result = result[:opts.Limit]
+	}
+
+	return result, nil
+}
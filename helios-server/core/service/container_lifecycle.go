@@ -0,0 +1,128 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"nfcunha/helios/core/repository"
+)
+
+// lifecycleActions lists the action log entries that change a container's state.
+var lifecycleActions = map[string]bool{
+	"create":     true,
+	"start":      true,
+	"stop":       true,
+	"restart":    true,
+	"update":     true,
+	"rename":     true,
+	"break_loop": true,
+	"remove":     true,
+}
+
+// lifecycleDedupWindow is how close a Docker event must be to a matching Helios
+// action to be treated as that action's echo rather than a separate transition.
+const lifecycleDedupWindow = 5 * time.Second
+
+// LifecycleTransition is one entry in a container's lifecycle timeline.
+type LifecycleTransition struct {
+	Time     time.Time `json:"time"`
+	Action   string    `json:"action"` // e.g. start, stop, die, oom, remove
+	Source   string    `json:"source"` // helios (action log) or docker (daemon event)
+	Success  *bool     `json:"success,omitempty"`
+	Error    string    `json:"error,omitempty"`
+	ExitCode string    `json:"exit_code,omitempty"` // For die events
+	Name     string    `json:"name,omitempty"`      // Container name at the time
+}
+
+// LifecycleTimeline reconstructs what happened to a container from the actions
+// taken through Helios and the Docker events recorded in the event log.
+type LifecycleTimeline struct {
+	actionLogRepo *repository.ActionLogRepository
+	eventLogRepo  *repository.EventLogRepository
+}
+
+// NewLifecycleTimeline creates a lifecycle timeline reader.
+func NewLifecycleTimeline(actionLogRepo *repository.ActionLogRepository, eventLogRepo *repository.EventLogRepository) *LifecycleTimeline {
+	return &LifecycleTimeline{
+		actionLogRepo: actionLogRepo,
+		eventLogRepo:  eventLogRepo,
+	}
+}
+
+// ForContainer returns up to limit of the container's most recent lifecycle
+// transitions, oldest first. Docker events that merely echo an action taken
+// through Helios are dropped, so each transition appears once.
+func (t *LifecycleTimeline) ForContainer(containerID string, limit int) ([]LifecycleTransition, error) {
+	actions, err := t.actionLogRepo.GetByResource("container", containerID, limit*2)
+	if err != nil {
+		log.Printf("Failed to load action logs for container %s: %v", containerID, err)
+		return nil, fmt.Errorf("failed to load action logs: %w", err)
+	}
+	events, err := t.eventLogRepo.GetByContainer(containerID, limit*2)
+	if err != nil {
+		log.Printf("Failed to load Docker events for container %s: %v", containerID, err)
+		return nil, fmt.Errorf("failed to load Docker events: %w", err)
+	}
+
+	timeline := []LifecycleTransition{}
+	for _, action := range actions {
+		if !lifecycleActions[action.ActionType] {
+			continue
+		}
+		success := action.Success
+		timeline = append(timeline, LifecycleTransition{
+			Time:    action.ExecutedAt,
+			Action:  action.ActionType,
+			Source:  "helios",
+			Success: &success,
+			Error:   action.ErrorMessage,
+			Name:    action.ResourceName,
+		})
+	}
+
+	heliosActions := timeline
+	for _, eventLog := range events {
+		var event DockerEvent
+		if err := json.Unmarshal([]byte(eventLog.Metadata), &event); err != nil {
+			continue
+		}
+		action := event.Action
+		if action == "destroy" {
+			action = "remove"
+		}
+		if echoesHeliosAction(heliosActions, action, event.Time) {
+			continue
+		}
+		timeline = append(timeline, LifecycleTransition{
+			Time:     event.Time,
+			Action:   action,
+			Source:   "docker",
+			ExitCode: event.Attributes["exitCode"],
+			Name:     event.Name,
+		})
+	}
+
+	sort.SliceStable(timeline, func(i, j int) bool { return timeline[i].Time.Before(timeline[j].Time) })
+	if len(timeline) > limit {
+		timeline = timeline[len(timeline)-limit:]
+	}
+	return timeline, nil
+}
+
+// echoesHeliosAction reports whether a successful Helios action of the same kind
+// happened within lifecycleDedupWindow of the event.
+func echoesHeliosAction(actions []LifecycleTransition, action string, at time.Time) bool {
+	for _, a := range actions {
+		if a.Action != action || a.Success == nil || !*a.Success {
+			continue
+		}
+		if diff := a.Time.Sub(at); diff < lifecycleDedupWindow && diff > -lifecycleDedupWindow {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// BreakLoopResult reports the state of a container after its restart loop was broken.
+type BreakLoopResult struct {
+	ContainerID           string `json:"container_id"`
+	Name                  string `json:"name"`
+	State                 string `json:"state"`
+	ExitCode              int    `json:"exit_code"`
+	RestartCount          int    `json:"restart_count"` // Restarts before the loop was broken
+	RestartPolicy         string `json:"restart_policy"`
+	PreviousRestartPolicy string `json:"previous_restart_policy"`
+}
+
+// BreakRestartLoop sets a container's restart policy to "no" and then stops it, so a
+// crashing container with an "always" or "on-failure" policy stays down. Stopping
+// alone is not enough while the daemon keeps restarting it. The previous policy is
+// returned so it can be restored once the container is fixed.
+func (s *ContainerService) BreakRestartLoop(ctx context.Context, containerID string) (*BreakLoopResult, error) {
+	containerJSON, err := s.dockerClient.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, s.logAction("break_loop", "container", containerID, "", false, err)
+	}
+	name := containerDisplayName(containerJSON.Name)
+
+	previous := container.RestartPolicyMode("no")
+	if containerJSON.HostConfig != nil && containerJSON.HostConfig.RestartPolicy.Name != "" {
+		previous = containerJSON.HostConfig.RestartPolicy.Name
+	}
+
+	// Disable the policy first; otherwise the daemon may start the container again
+	// between the stop and the update
+	_, err = s.dockerClient.ContainerUpdate(ctx, containerID, container.UpdateConfig{
+		RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyDisabled},
+	})
+	if err != nil {
+		log.Printf("Failed to disable restart policy for container %s: %v", name, err)
+		return nil, s.logAction("break_loop", "container", containerID, name, false, fmt.Errorf("failed to disable restart policy: %w", err))
+	}
+
+	timeout := 10
+	err = s.dockerClient.ContainerStop(ctx, containerID, container.StopOptions{
+		Timeout: &timeout,
+	})
+	if err != nil {
+		log.Printf("Failed to stop container %s after disabling its restart policy: %v", name, err)
+		return nil, s.logAction("break_loop", "container", containerID, name, false, err)
+	}
+
+	result := &BreakLoopResult{
+		ContainerID:           containerJSON.ID,
+		Name:                  name,
+		RestartCount:          containerJSON.RestartCount,
+		RestartPolicy:         string(container.RestartPolicyDisabled),
+		PreviousRestartPolicy: string(previous),
+	}
+
+	// Report the state after the stop
+	stopped, err := s.dockerClient.ContainerInspect(ctx, containerID)
+	if err != nil {
+		log.Printf("Failed to inspect container %s after breaking its restart loop: %v", name, err)
+	} else if stopped.State != nil {
+		result.State = stopped.State.Status
+		result.ExitCode = stopped.State.ExitCode
+	}
+
+	log.Printf("Restart loop of container %s broken (restart policy %s -> no, %d restarts)", name, previous, result.RestartCount)
+	return result, s.logAction("break_loop", "container", containerID, name, true, nil)
+}
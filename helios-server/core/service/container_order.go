@@ -0,0 +1,176 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"strings"
+
+	"github.com/docker/docker/api/types"
+)
+
+// Docker Compose labels used to derive start order.
+const (
+	composeProjectLabel   = "com.docker.compose.project"
+	composeServiceLabel   = "com.docker.compose.service"
+	composeDependsOnLabel = "com.docker.compose.depends_on"
+)
+
+// dependencyOrder sorts containers so that every container comes after the containers
+// it depends on, i.e. in start order; reverse it for stop order. Dependencies are read
+// from the Compose depends_on label ("service:condition:restart,...") and resolved
+// within the same Compose project. Containers without dependencies keep their relative
+// order, and dependency cycles are broken at the first container seen.
+func dependencyOrder(containers []types.Container) []types.Container {
+	byService := make(map[string][]int)
+	for i, c := range containers {
+		if key := composeServiceKey(c.Labels[composeProjectLabel], c.Labels[composeServiceLabel]); key != "" {
+			byService[key] = append(byService[key], i)
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(containers))
+	ordered := make([]types.Container, 0, len(containers))
+
+	var visit func(i int)
+	visit = func(i int) {
+		if state[i] != unvisited {
+			return // Already placed, or a cycle
+		}
+		state[i] = visiting
+
+		c := containers[i]
+		for _, dep := range composeDependencies(c.Labels[composeDependsOnLabel]) {
+			for _, j := range byService[composeServiceKey(c.Labels[composeProjectLabel], dep)] {
+				visit(j)
+			}
+		}
+
+		state[i] = visited
+		ordered = append(ordered, c)
+	}
+
+	for i := range containers {
+		visit(i)
+	}
+	return ordered
+}
+
+// composeServiceKey identifies a Compose service across projects; "" if either part is missing.
+func composeServiceKey(project, service string) string {
+	if project == "" || service == "" {
+		return ""
+	}
+	return project + "/" + service
+}
+
+// composeDependencies parses the service names out of a Compose depends_on label.
+func composeDependencies(label string) []string {
+	var deps []string
+	for _, entry := range strings.Split(label, ",") {
+		name, _, _ := strings.Cut(strings.TrimSpace(entry), ":")
+		if name != "" {
+			deps = append(deps, name)
+		}
+	}
+	return deps
+}
+
+// startDependencies builds the dependency graph of the given containers from their
+// Compose depends_on labels and an explicit spec keyed by container name or ID
+// (prefix). Only dependencies among the given containers are kept.
+func startDependencies(ids []string, names map[string]string, labels map[string]map[string]string, spec map[string][]string) map[string][]string {
+	byService := make(map[string][]string)
+	for _, id := range ids {
+		if key := composeServiceKey(labels[id][composeProjectLabel], labels[id][composeServiceLabel]); key != "" {
+			byService[key] = append(byService[key], id)
+		}
+	}
+
+	match := func(ref string) string {
+		ref = strings.TrimPrefix(ref, "/")
+		for _, id := range ids {
+			if names[id] == ref || (ref != "" && strings.HasPrefix(id, ref)) {
+				return id
+			}
+		}
+		return ""
+	}
+
+	deps := make(map[string][]string)
+	add := func(id, dep string) {
+		if dep == "" || dep == id {
+			return
+		}
+		for _, existing := range deps[id] {
+			if existing == dep {
+				return
+			}
+		}
+		deps[id] = append(deps[id], dep)
+	}
+
+	for _, id := range ids {
+		project := labels[id][composeProjectLabel]
+		for _, service := range composeDependencies(labels[id][composeDependsOnLabel]) {
+			for _, dep := range byService[composeServiceKey(project, service)] {
+				add(id, dep)
+			}
+		}
+	}
+	for ref, refs := range spec {
+		id := match(ref)
+		if id == "" {
+			continue
+		}
+		for _, depRef := range refs {
+			add(id, match(depRef))
+		}
+	}
+
+	return deps
+}
+
+// dependencyLevels groups ids into start levels: a container's level is one more
+// than the highest level among its dependencies, so every level only depends on
+// earlier ones. Ids keep their relative order within a level, and dependency
+// cycles are broken at the first container seen.
+func dependencyLevels(ids []string, deps map[string][]string) [][]string {
+	const visiting = -1
+	level := make(map[string]int)
+
+	var visit func(id string) int
+	visit = func(id string) int {
+		if l, ok := level[id]; ok {
+			if l == visiting {
+				return -1 // Cycle; ignore this edge
+			}
+			return l
+		}
+		level[id] = visiting
+
+		l := 0
+		for _, dep := range deps[id] {
+			l = max(l, visit(dep)+1)
+		}
+
+		level[id] = l
+		return l
+	}
+
+	var levels [][]string
+	for _, id := range ids {
+		visit(id)
+	}
+	for _, id := range ids {
+		l := level[id]
+		for len(levels) <= l {
+			levels = append(levels, nil)
+		}
+		levels[l] = append(levels[l], id)
+	}
+	return levels
+}
@@ -0,0 +1,219 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// readyPollInterval is how often readiness is re-checked while waiting.
+const readyPollInterval = time.Second
+
+// ErrNoHealthcheck is returned when waiting for a container that has no Docker healthcheck.
+var ErrNoHealthcheck = errors.New("container has no healthcheck defined")
+
+// HealthWait is the outcome of waiting for a container's healthcheck to pass.
+type HealthWait struct {
+	ContainerID string `json:"container_id"`
+	Name        string `json:"name"`
+	Status      string `json:"status"` // starting, healthy or unhealthy; empty before the first start
+	Healthy     bool   `json:"healthy"`
+	TimedOut    bool   `json:"timed_out"`
+	WaitedMs    int64  `json:"waited_ms"`
+}
+
+// ContainerReadiness combines a container's state, Docker healthcheck and an
+// optional log pattern into a single ready flag.
+type ContainerReadiness struct {
+	ContainerID string    `json:"container_id"`
+	Name        string    `json:"name"`
+	Ready       bool      `json:"ready"`
+	Running     bool      `json:"running"`
+	State       string    `json:"state"`
+	Health      string    `json:"health"` // none, starting, healthy or unhealthy
+	LogPattern  string    `json:"log_pattern,omitempty"`
+	LogMatched  *bool     `json:"log_matched,omitempty"`
+	Reasons     []string  `json:"reasons,omitempty"` // Why the container is not ready
+	CheckedAt   time.Time `json:"checked_at"`
+}
+
+// CheckReady reports whether a container is ready: running, passing its Docker
+// healthcheck if one is defined, and, when pattern is set, having logged a line
+// matching it since it last started. With a positive wait the check is repeated
+// until the container is ready or wait elapses; the last result is returned.
+func (s *ContainerService) CheckReady(ctx context.Context, containerID string, pattern *regexp.Regexp, wait time.Duration) (*ContainerReadiness, error) {
+	deadline := time.Now().Add(wait)
+
+	for {
+		readiness, err := s.checkReadyOnce(ctx, containerID, pattern)
+		if err != nil {
+			return nil, err
+		}
+		if readiness.Ready || wait <= 0 || time.Now().Add(readyPollInterval).After(deadline) {
+			return readiness, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return readiness, nil
+		case <-time.After(readyPollInterval):
+		}
+	}
+}
+
+// checkReadyOnce performs a single readiness check.
+func (s *ContainerService) checkReadyOnce(ctx context.Context, containerID string, pattern *regexp.Regexp) (*ContainerReadiness, error) {
+	containerJSON, err := s.dockerClient.ContainerInspect(ctx, containerID)
+	if err != nil {
+		log.Printf("Failed to inspect container %s for readiness: %v", containerID, err)
+		return nil, fmt.Errorf("failed to inspect container: %w", err)
+	}
+
+	readiness := &ContainerReadiness{
+		ContainerID: containerJSON.ID,
+		Name:        containerDisplayName(containerJSON.Name),
+		Health:      "none",
+		CheckedAt:   time.Now(),
+	}
+
+	state := containerJSON.State
+	if state != nil {
+		readiness.State = state.Status
+		readiness.Running = state.Running && !state.Restarting
+		if state.Health != nil && state.Health.Status != "" {
+			readiness.Health = state.Health.Status
+		}
+	}
+
+	if !readiness.Running {
+		readiness.Reasons = append(readiness.Reasons, fmt.Sprintf("container is %s", readiness.State))
+	}
+	if readiness.Health != "none" && readiness.Health != "healthy" {
+		readiness.Reasons = append(readiness.Reasons, fmt.Sprintf("healthcheck is %s", readiness.Health))
+	}
+
+	if pattern != nil {
+		readiness.LogPattern = pattern.String()
+		matched := false
+		if readiness.Running {
+			tty := containerJSON.Config != nil && containerJSON.Config.Tty
+			matched, err = s.logsMatch(ctx, containerID, state.StartedAt, tty, pattern)
+			if err != nil {
+				return nil, err
+			}
+		}
+		readiness.LogMatched = &matched
+		if !matched {
+			readiness.Reasons = append(readiness.Reasons, fmt.Sprintf("no log line matches %q", pattern.String()))
+		}
+	}
+
+	readiness.Ready = len(readiness.Reasons) == 0
+	return readiness, nil
+}
+
+// logsMatch reports whether any log line written since the given start time matches pattern.
+func (s *ContainerService) logsMatch(ctx context.Context, containerID, since string, tty bool, pattern *regexp.Regexp) (bool, error) {
+	reader, err := s.dockerClient.ContainerLogs(ctx, containerID, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Since:      since,
+	})
+	if err != nil {
+		log.Printf("Failed to read logs of container %s for readiness: %v", containerID, err)
+		return false, fmt.Errorf("failed to get container logs: %w", err)
+	}
+	defer reader.Close()
+
+	var lines io.Reader = reader
+	if !tty {
+		// Strip the 8-byte multiplexing headers from stdout/stderr frames
+		pr, pw := io.Pipe()
+		defer pr.Close()
+		go func() {
+			header := make([]byte, 8)
+			for {
+				if _, err := io.ReadFull(reader, header); err != nil {
+					if err == io.EOF {
+						err = nil
+					}
+					pw.CloseWithError(err)
+					return
+				}
+				size := uint32(header[4])<<24 | uint32(header[5])<<16 | uint32(header[6])<<8 | uint32(header[7])
+				if _, err := io.CopyN(pw, reader, int64(size)); err != nil {
+					pw.CloseWithError(err)
+					return
+				}
+			}
+		}()
+		lines = pr
+	}
+
+	scanner := bufio.NewScanner(lines)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if pattern.Match(scanner.Bytes()) {
+			return true, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("failed to read container logs: %w", err)
+	}
+	return false, nil
+}
+
+// WaitHealthy polls a container's Docker healthcheck until it reports healthy or
+// timeout elapses, and returns the last observed status. Containers whose
+// configuration (including the image's) defines no healthcheck fail with ErrNoHealthcheck.
+func (s *ContainerService) WaitHealthy(ctx context.Context, containerID string, timeout time.Duration) (*HealthWait, error) {
+	start := time.Now()
+	deadline := start.Add(timeout)
+
+	for {
+		containerJSON, err := s.dockerClient.ContainerInspect(ctx, containerID)
+		if err != nil {
+			log.Printf("Failed to inspect container %s for health: %v", containerID, err)
+			return nil, fmt.Errorf("failed to inspect container: %w", err)
+		}
+
+		var check *container.HealthConfig
+		if containerJSON.Config != nil {
+			check = containerJSON.Config.Healthcheck
+		}
+		if check == nil || len(check.Test) == 0 || check.Test[0] == "NONE" {
+			return nil, ErrNoHealthcheck
+		}
+
+		result := &HealthWait{
+			ContainerID: containerJSON.ID,
+			Name:        containerDisplayName(containerJSON.Name),
+		}
+		if containerJSON.State != nil && containerJSON.State.Health != nil {
+			result.Status = containerJSON.State.Health.Status
+		}
+		result.Healthy = result.Status == "healthy"
+
+		if result.Healthy || !time.Now().Add(readyPollInterval).Before(deadline) {
+			result.TimedOut = !result.Healthy
+			result.WaitedMs = time.Since(start).Milliseconds()
+			return result, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			result.TimedOut = true
+			result.WaitedMs = time.Since(start).Milliseconds()
+			return result, nil
+		case <-time.After(readyPollInterval):
+		}
+	}
+}
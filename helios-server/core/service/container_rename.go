@@ -0,0 +1,63 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/docker/docker/errdefs"
+)
+
+// containerNamePattern is the daemon's pattern for valid container names.
+var containerNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]+$`)
+
+// ErrInvalidContainerName is returned when a container name does not match containerNamePattern.
+var ErrInvalidContainerName = errors.New("invalid container name")
+
+// RenameContainer renames a container without recreating it and returns its updated details.
+// The action is logged against the new name, as "old -> new". Renaming to a name held
+// by another container fails with a *NameConflictError; renaming to the current name
+// is a no-op. The new name must stay within the container scope, so a container cannot
+// be hidden from this instance or moved into another tenant's prefix.
+func (s *ContainerService) RenameContainer(ctx context.Context, containerID, newName string) (*ContainerInfo, error) {
+	newName = strings.TrimPrefix(newName, "/")
+	if !containerNamePattern.MatchString(newName) {
+		return nil, fmt.Errorf("%w %q: must match %s", ErrInvalidContainerName, newName, containerNamePattern.String())
+	}
+	if !s.scope.Allows(newName) {
+		return nil, fmt.Errorf("%w %q: must start with %q", ErrInvalidContainerName, newName, s.scope.Prefix())
+	}
+
+	containerJSON, err := s.dockerClient.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, s.logAction("rename", "container", containerID, newName, false, err)
+	}
+	oldName := containerDisplayName(containerJSON.Name)
+	if oldName == newName {
+		return s.GetContainer(ctx, containerJSON.ID)
+	}
+	resourceName := fmt.Sprintf("%s -> %s", oldName, newName)
+
+	if _, err := s.resolveNameConflict(ctx, newName, OnConflictError, false); err != nil {
+		return nil, s.logAction("rename", "container", containerJSON.ID, resourceName, false, err)
+	}
+
+	if err := s.dockerClient.ContainerRename(ctx, containerJSON.ID, newName); err != nil {
+		// Another container may have taken the name since the check above
+		if errdefs.IsConflict(err) {
+			if _, conflict := s.resolveNameConflict(ctx, newName, OnConflictError, false); conflict != nil {
+				err = conflict
+			}
+		}
+		return nil, s.logAction("rename", "container", containerJSON.ID, resourceName, false, err)
+	}
+
+	log.Printf("Container %s renamed to %s", oldName, newName)
+	s.logAction("rename", "container", containerJSON.ID, resourceName, true, nil)
+
+	return s.GetContainer(ctx, containerJSON.ID)
+}
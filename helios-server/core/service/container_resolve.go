@@ -0,0 +1,90 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+)
+
+// ErrContainerNotFound is returned when a container reference matches no container.
+var ErrContainerNotFound = errors.New("container not found")
+
+// AmbiguousContainerError is returned when a partial ID matches several containers.
+type AmbiguousContainerError struct {
+	Ref        string
+	Candidates []ContainerInfo
+}
+
+func (e *AmbiguousContainerError) Error() string {
+	return fmt.Sprintf("container reference %q is ambiguous: matches %d containers", e.Ref, len(e.Candidates))
+}
+
+// ResolveContainer resolves a container name, full ID or partial ID to a full container ID.
+// Resolution follows Docker's order: exact ID, then exact name (with or without the
+// leading slash), then unique ID prefix. A prefix matching several containers yields
+// an *AmbiguousContainerError listing the candidates; no match yields ErrContainerNotFound.
+// Containers outside the configured scope are never matched.
+func (s *ContainerService) ResolveContainer(ctx context.Context, ref string) (string, error) {
+	if ref == "" {
+		return "", ErrContainerNotFound
+	}
+
+	containers, err := s.dockerClient.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		log.Printf("Failed to list containers to resolve %s: %v", ref, err)
+		return "", fmt.Errorf("failed to list containers: %w", err)
+	}
+	return s.matchContainer(s.scope.Filter(containers), ref)
+}
+
+// matchContainer picks the container ref refers to among containers, in the order
+// described on ResolveContainer.
+func (s *ContainerService) matchContainer(containers []types.Container, ref string) (string, error) {
+	if ref == "" {
+		return "", ErrContainerNotFound
+	}
+
+	name := containerDisplayName(ref)
+	nameMatch := -1
+	var prefixMatches []int
+
+	for i, c := range containers {
+		if c.ID == ref {
+			return c.ID, nil
+		}
+		if nameMatch < 0 {
+			for _, n := range c.Names {
+				if containerDisplayName(n) == name {
+					nameMatch = i
+					break
+				}
+			}
+		}
+		if strings.HasPrefix(c.ID, ref) {
+			prefixMatches = append(prefixMatches, i)
+		}
+	}
+
+	if nameMatch >= 0 {
+		return containers[nameMatch].ID, nil
+	}
+
+	switch len(prefixMatches) {
+	case 0:
+		return "", fmt.Errorf("%w: %s", ErrContainerNotFound, ref)
+	case 1:
+		return containers[prefixMatches[0]].ID, nil
+	}
+
+	candidates := make([]ContainerInfo, 0, len(prefixMatches))
+	for _, i := range prefixMatches {
+		candidates = append(candidates, s.convertToContainerInfo(containers[i]))
+	}
+	return "", &AmbiguousContainerError{Ref: ref, Candidates: candidates}
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+)
+
+func TestMatchContainer(t *testing.T) {
+	// Another container is named after this one's ID; the ID must win, as in Docker
+	const idNamedContainer = "0123456789ab0000000000000000000000000000000000000000000000000006"
+	containers := []types.Container{
+		{ID: "abc123def4560000000000000000000000000000000000000000000000000001", Names: []string{"/web"}},
+		{ID: "abc123fff0000000000000000000000000000000000000000000000000000002", Names: []string{"/db"}},
+		{ID: "9f8e7d6c5b4a0000000000000000000000000000000000000000000000000003", Names: []string{"/abc123"}},
+		{ID: "web0000000000000000000000000000000000000000000000000000000000004", Names: []string{"/cache"}},
+		{ID: "5555000000000000000000000000000000000000000000000000000000000005", Names: []string{"/" + idNamedContainer}},
+		{ID: idNamedContainer, Names: []string{"/app"}},
+	}
+
+	tests := []struct {
+		name          string
+		ref           string
+		want          string
+		wantNotFound  bool
+		wantAmbiguous int
+	}{
+		{name: "full ID", ref: containers[1].ID, want: containers[1].ID},
+		{name: "exact name", ref: "db", want: containers[1].ID},
+		{name: "full ID before name", ref: idNamedContainer, want: idNamedContainer},
+		{name: "name with slash", ref: "/db", want: containers[1].ID},
+		{name: "name before ID prefix", ref: "web", want: containers[0].ID},
+		{name: "name before ambiguous prefix", ref: "abc123", want: containers[2].ID},
+		{name: "unique partial ID", ref: "abc123d", want: containers[0].ID},
+		{name: "short partial ID", ref: "9f8e", want: containers[2].ID},
+		{name: "ambiguous partial ID", ref: "abc1", wantAmbiguous: 2},
+		{name: "no match", ref: "missing", wantNotFound: true},
+		{name: "empty", ref: "", wantNotFound: true},
+	}
+
+	s := &ContainerService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.matchContainer(containers, tt.ref)
+
+			var ambiguous *AmbiguousContainerError
+			switch {
+			case tt.wantNotFound:
+				if !errors.Is(err, ErrContainerNotFound) {
+					t.Fatalf("matchContainer(%q) error = %v, want ErrContainerNotFound", tt.ref, err)
+				}
+			case tt.wantAmbiguous > 0:
+				if !errors.As(err, &ambiguous) {
+					t.Fatalf("matchContainer(%q) error = %v, want *AmbiguousContainerError", tt.ref, err)
+				}
+				if len(ambiguous.Candidates) != tt.wantAmbiguous {
+					t.Errorf("matchContainer(%q) candidates = %d, want %d", tt.ref, len(ambiguous.Candidates), tt.wantAmbiguous)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("matchContainer(%q) error = %v", tt.ref, err)
+				}
+				if got != tt.want {
+					t.Errorf("matchContainer(%q) = %s, want %s", tt.ref, got, tt.want)
+				}
+			}
+		})
+	}
+}
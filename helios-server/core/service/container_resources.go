@@ -0,0 +1,153 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// minContainerMemory is the smallest memory limit the daemon accepts.
+const minContainerMemory = 6 * 1024 * 1024
+
+// ErrInvalidResources is returned when a resource update request fails validation.
+var ErrInvalidResources = errors.New("invalid resource limits")
+
+// UpdateResourcesRequest changes a running or stopped container's limits in place.
+// Fields left out keep their current value.
+type UpdateResourcesRequest struct {
+	Memory        *int64  `json:"memory"`         // Bytes, at least 6MB
+	MemorySwap    *int64  `json:"memory_swap"`    // Bytes of memory plus swap; -1 for unlimited swap
+	NanoCPUs      *int64  `json:"nano_cpus"`      // CPU quota in billionths of a CPU (1500000000 = 1.5 CPUs)
+	CPUShares     *int64  `json:"cpu_shares"`     // Relative CPU weight (default 1024)
+	RestartPolicy *string `json:"restart_policy"` // no, always, unless-stopped or on-failure[:max-retries]
+}
+
+// String lists the fields being changed, for logging.
+func (r UpdateResourcesRequest) String() string {
+	var parts []string
+	if r.Memory != nil {
+		parts = append(parts, fmt.Sprintf("memory=%d", *r.Memory))
+	}
+	if r.MemorySwap != nil {
+		parts = append(parts, fmt.Sprintf("memory_swap=%d", *r.MemorySwap))
+	}
+	if r.NanoCPUs != nil {
+		parts = append(parts, fmt.Sprintf("cpus=%g", float64(*r.NanoCPUs)/1e9))
+	}
+	if r.CPUShares != nil {
+		parts = append(parts, fmt.Sprintf("cpu_shares=%d", *r.CPUShares))
+	}
+	if r.RestartPolicy != nil {
+		parts = append(parts, fmt.Sprintf("restart_policy=%s", *r.RestartPolicy))
+	}
+	return strings.Join(parts, " ")
+}
+
+// ContainerResources are a container's limits after an update.
+type ContainerResources struct {
+	ContainerID   string   `json:"container_id"`
+	Name          string   `json:"name"`
+	Memory        int64    `json:"memory"`      // 0 means unlimited
+	MemorySwap    int64    `json:"memory_swap"` // 0 means twice the memory limit, -1 unlimited
+	NanoCPUs      int64    `json:"nano_cpus"`   // 0 means unlimited
+	CPUShares     int64    `json:"cpu_shares"`
+	RestartPolicy string   `json:"restart_policy"`
+	Warnings      []string `json:"warnings,omitempty"`
+}
+
+// UpdateContainerResources applies new memory, CPU and restart policy settings to a
+// container without recreating it and returns the resulting limits. The action is
+// logged with the requested limits. Validation failures match ErrInvalidResources.
+func (s *ContainerService) UpdateContainerResources(ctx context.Context, containerID string, req UpdateResourcesRequest) (*ContainerResources, error) {
+	update, err := buildResourceUpdate(req)
+	if err != nil {
+		return nil, s.logAction("update_resources", "container", containerID, req.String(), false, fmt.Errorf("%w: %w", ErrInvalidResources, err))
+	}
+
+	containerJSON, err := s.dockerClient.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, s.logAction("update_resources", "container", containerID, req.String(), false, err)
+	}
+	name := containerDisplayName(containerJSON.Name)
+	resourceName := fmt.Sprintf("%s: %s", name, req)
+
+	response, err := s.dockerClient.ContainerUpdate(ctx, containerJSON.ID, update)
+	if err != nil {
+		log.Printf("Failed to update resources of container %s: %v", name, err)
+		return nil, s.logAction("update_resources", "container", containerJSON.ID, resourceName, false, fmt.Errorf("failed to update container resources: %w", err))
+	}
+	for _, warning := range response.Warnings {
+		log.Printf("Warning while updating resources of container %s: %s", name, warning)
+	}
+	log.Printf("Container %s resources updated: %s", name, req)
+	s.logAction("update_resources", "container", containerJSON.ID, resourceName, true, nil)
+
+	updated, err := s.dockerClient.ContainerInspect(ctx, containerJSON.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect container: %w", err)
+	}
+	result := &ContainerResources{
+		ContainerID: updated.ID,
+		Name:        name,
+		Warnings:    response.Warnings,
+	}
+	if hc := updated.HostConfig; hc != nil {
+		result.Memory = hc.Memory
+		result.MemorySwap = hc.MemorySwap
+		result.NanoCPUs = hc.NanoCPUs
+		result.CPUShares = hc.CPUShares
+		result.RestartPolicy = string(hc.RestartPolicy.Name)
+	}
+	return result, nil
+}
+
+// buildResourceUpdate validates a resource update request and converts it to the
+// daemon's update configuration.
+func buildResourceUpdate(req UpdateResourcesRequest) (container.UpdateConfig, error) {
+	var update container.UpdateConfig
+
+	if req.Memory == nil && req.MemorySwap == nil && req.NanoCPUs == nil && req.CPUShares == nil && req.RestartPolicy == nil {
+		return update, errors.New("no limits to update: set memory, memory_swap, nano_cpus, cpu_shares or restart_policy")
+	}
+	if req.Memory != nil {
+		if *req.Memory < minContainerMemory {
+			return update, fmt.Errorf("memory must be at least 6MB (%d bytes), got %d", minContainerMemory, *req.Memory)
+		}
+		update.Memory = *req.Memory
+	}
+	if req.MemorySwap != nil {
+		if *req.MemorySwap != -1 && *req.MemorySwap <= 0 {
+			return update, fmt.Errorf("memory_swap must be -1 (unlimited) or a positive number of bytes, got %d", *req.MemorySwap)
+		}
+		if req.Memory != nil && *req.MemorySwap != -1 && *req.MemorySwap < *req.Memory {
+			return update, fmt.Errorf("memory_swap (%d) must be at least memory (%d); it includes memory", *req.MemorySwap, *req.Memory)
+		}
+		update.MemorySwap = *req.MemorySwap
+	}
+	if req.NanoCPUs != nil {
+		if *req.NanoCPUs <= 0 {
+			return update, fmt.Errorf("nano_cpus must be positive, got %d", *req.NanoCPUs)
+		}
+		update.NanoCPUs = *req.NanoCPUs
+	}
+	if req.CPUShares != nil {
+		if *req.CPUShares < 2 {
+			return update, fmt.Errorf("cpu_shares must be at least 2, got %d", *req.CPUShares)
+		}
+		update.CPUShares = *req.CPUShares
+	}
+	if req.RestartPolicy != nil {
+		policy, err := parseRestartPolicy(*req.RestartPolicy)
+		if err != nil {
+			return update, err
+		}
+		update.RestartPolicy = policy
+	}
+
+	return update, nil
+}
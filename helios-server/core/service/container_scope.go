@@ -0,0 +1,67 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+)
+
+// ErrContainerOutOfScope is returned when an operation targets a container outside the configured scope.
+var ErrContainerOutOfScope = errors.New("container is outside the configured name prefix")
+
+// ContainerScope restricts Helios to containers whose name starts with a configured prefix,
+// so a tenant-scoped instance only sees and acts on its own containers.
+// A nil scope or an empty prefix allows every container.
+type ContainerScope struct {
+	prefix string
+}
+
+// NewContainerScope creates a container scope for the given name prefix.
+func NewContainerScope(prefix string) *ContainerScope {
+	return &ContainerScope{prefix: containerDisplayName(prefix)}
+}
+
+// Prefix returns the configured name prefix, or "" when unscoped.
+func (s *ContainerScope) Prefix() string {
+	if s == nil {
+		return ""
+	}
+	return s.prefix
+}
+
+// Allows reports whether a container name (with or without the leading slash) is in scope.
+func (s *ContainerScope) Allows(name string) bool {
+	if s == nil || s.prefix == "" {
+		return true
+	}
+	return strings.HasPrefix(containerDisplayName(name), s.prefix)
+}
+
+// AllowsAny reports whether any of a container's names is in scope.
+func (s *ContainerScope) AllowsAny(names []string) bool {
+	if s == nil || s.prefix == "" {
+		return true
+	}
+	for _, name := range names {
+		if s.Allows(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the listed containers that are in scope.
+func (s *ContainerScope) Filter(containers []types.Container) []types.Container {
+	if s == nil || s.prefix == "" {
+		return containers
+	}
+	filtered := make([]types.Container, 0, len(containers))
+	for _, c := range containers {
+		if s.AllowsAny(c.Names) {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,93 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// ErrContainerNotRunning is returned when an operation needs a running container.
+var ErrContainerNotRunning = errors.New("container is not running")
+
+// ContainerStatsSnapshot is a container's stats with their freshness.
+type ContainerStatsSnapshot struct {
+	ContainerID string          `json:"container_id"`
+	Cached      bool            `json:"cached"` // False when the stats cache had no entry and stats were fetched live
+	SampledAt   time.Time       `json:"sampled_at"`
+	AgeSeconds  float64         `json:"age_seconds"`
+	Stats       *ContainerStats `json:"stats"`
+}
+
+// GetContainerStats returns a running container's stats from the stats cache, fetching
+// them live only when the container has not been sampled yet. ErrContainerNotRunning is
+// returned for containers that are not running.
+func (s *ContainerService) GetContainerStats(ctx context.Context, containerID string) (*ContainerStatsSnapshot, error) {
+	snapshot := &ContainerStatsSnapshot{ContainerID: containerID, Cached: true}
+
+	stats := s.statsCache.GetContainerStats(containerID)
+	if stats == nil {
+		containerJSON, err := s.dockerClient.ContainerInspect(ctx, containerID)
+		if err != nil {
+			log.Printf("Failed to inspect container %s: %v", containerID, err)
+			return nil, fmt.Errorf("failed to inspect container: %w", err)
+		}
+		if containerJSON.State == nil || !containerJSON.State.Running {
+			return nil, fmt.Errorf("%w: %s", ErrContainerNotRunning, containerDisplayName(containerJSON.Name))
+		}
+
+		stats, err = s.getContainerStats(ctx, containerID)
+		if err != nil {
+			log.Printf("Failed to get stats for container %s: %v", containerID, err)
+			return nil, fmt.Errorf("failed to get container stats: %w", err)
+		}
+		snapshot.Cached = false
+	}
+
+	snapshot.Stats = stats
+	snapshot.SampledAt = stats.SampledAt
+	snapshot.AgeSeconds = time.Since(stats.SampledAt).Seconds()
+	return snapshot, nil
+}
+
+// StreamContainerStats samples a running container's stats live every interval and
+// passes each reading to send, starting immediately. It returns nil once ctx is done,
+// ErrContainerNotRunning once the container stops, and send's error if it fails.
+func (s *ContainerService) StreamContainerStats(ctx context.Context, containerID string, interval time.Duration, send func(*ContainerStats) error) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		containerJSON, err := s.dockerClient.ContainerInspect(ctx, containerID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			log.Printf("Failed to inspect container %s: %v", containerID, err)
+			return fmt.Errorf("failed to inspect container: %w", err)
+		}
+		if containerJSON.State == nil || !containerJSON.State.Running {
+			return fmt.Errorf("%w: %s", ErrContainerNotRunning, containerDisplayName(containerJSON.Name))
+		}
+
+		stats, err := s.getContainerStats(ctx, containerID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			log.Printf("Failed to get stats for container %s: %v", containerID, err)
+			return fmt.Errorf("failed to get container stats: %w", err)
+		}
+		if err := send(stats); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,311 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"regexp"
+	"strings"
+	"time"
+
+	"nfcunha/helios/core/models"
+	"nfcunha/helios/core/repository"
+
+	"github.com/distribution/reference"
+	"github.com/docker/go-connections/nat"
+)
+
+// templateNamePattern restricts template names to URL-safe identifiers.
+var templateNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// Template errors.
+var (
+	ErrTemplateNotFound = errors.New("template not found")
+	ErrTemplateExists   = errors.New("a template with this name already exists")
+	ErrInvalidTemplate  = errors.New("invalid template")
+)
+
+// ContainerTemplate is a named container creation spec that can be instantiated repeatedly.
+type ContainerTemplate struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Spec        CreateContainerRequest `json:"spec"`
+	CreatedAt   time.Time              `json:"created_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+}
+
+// TemplateOverrides adjusts a template's spec for one container.
+type TemplateOverrides struct {
+	Name           string            `json:"name"`            // Container name; replaces the template's
+	Ports          []string          `json:"ports"`           // Replaces the template's ports when set
+	Env            []string          `json:"env"`             // KEY=value entries added to the template's, replacing variables of the same name
+	Labels         map[string]string `json:"labels"`          // Added to the template's labels
+	Start          *bool             `json:"start"`           // Replaces the template's start setting when set
+	AutoName       bool              `json:"auto_name"`       // Pick a free name instead of conflicting
+	ConfirmReplace bool              `json:"confirm_replace"` // Required if the template uses on_conflict=replace
+}
+
+// TemplateService manages container templates and creates containers from them.
+type TemplateService struct {
+	templateRepo     *repository.TemplateRepository
+	containerService *ContainerService
+	actionLogRepo    *repository.ActionLogRepository
+}
+
+// NewTemplateService creates a new template service.
+func NewTemplateService(templateRepo *repository.TemplateRepository, containerService *ContainerService, actionLogRepo *repository.ActionLogRepository) *TemplateService {
+	return &TemplateService{
+		templateRepo:     templateRepo,
+		containerService: containerService,
+		actionLogRepo:    actionLogRepo,
+	}
+}
+
+// ListTemplates returns all templates ordered by name.
+func (s *TemplateService) ListTemplates() ([]*ContainerTemplate, error) {
+	records, err := s.templateRepo.List()
+	if err != nil {
+		log.Printf("Failed to list templates: %v", err)
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+
+	templates := make([]*ContainerTemplate, 0, len(records))
+	for _, record := range records {
+		template, err := templateFromRecord(record)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+	return templates, nil
+}
+
+// GetTemplate returns a template by name, or ErrTemplateNotFound.
+func (s *TemplateService) GetTemplate(name string) (*ContainerTemplate, error) {
+	record, found, err := s.templateRepo.Get(name)
+	if err != nil {
+		log.Printf("Failed to get template %s: %v", name, err)
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+	return templateFromRecord(record)
+}
+
+// CreateTemplate validates and saves a new template. Returns ErrTemplateExists if
+// the name is taken and an error matching ErrInvalidTemplate if validation fails.
+func (s *TemplateService) CreateTemplate(name, description string, spec CreateContainerRequest) (*ContainerTemplate, error) {
+	record, err := s.newTemplateRecord(name, description, spec)
+	if err != nil {
+		return nil, s.logAction("create", "template", name, name, false, err)
+	}
+	record.CreatedAt = record.UpdatedAt
+
+	created, err := s.templateRepo.Create(record)
+	if err != nil {
+		log.Printf("Failed to save template %s: %v", name, err)
+		return nil, s.logAction("create", "template", name, name, false, fmt.Errorf("failed to save template: %w", err))
+	}
+	if !created {
+		return nil, s.logAction("create", "template", name, name, false, fmt.Errorf("%w: %s", ErrTemplateExists, name))
+	}
+
+	log.Printf("Template %s created", name)
+	s.logAction("create", "template", name, name, true, nil)
+	return templateFromRecord(record)
+}
+
+// UpdateTemplate validates and replaces the description and spec of a template.
+// Returns ErrTemplateNotFound if it does not exist.
+func (s *TemplateService) UpdateTemplate(name, description string, spec CreateContainerRequest) (*ContainerTemplate, error) {
+	record, err := s.newTemplateRecord(name, description, spec)
+	if err != nil {
+		return nil, s.logAction("update", "template", name, name, false, err)
+	}
+
+	updated, err := s.templateRepo.Update(record)
+	if err != nil {
+		log.Printf("Failed to update template %s: %v", name, err)
+		return nil, s.logAction("update", "template", name, name, false, fmt.Errorf("failed to update template: %w", err))
+	}
+	if !updated {
+		return nil, s.logAction("update", "template", name, name, false, fmt.Errorf("%w: %s", ErrTemplateNotFound, name))
+	}
+
+	log.Printf("Template %s updated", name)
+	s.logAction("update", "template", name, name, true, nil)
+	return s.GetTemplate(name)
+}
+
+// DeleteTemplate removes a template. Returns ErrTemplateNotFound if it does not exist.
+// Containers created from it are not affected.
+func (s *TemplateService) DeleteTemplate(name string) error {
+	deleted, err := s.templateRepo.Delete(name)
+	if err != nil {
+		log.Printf("Failed to delete template %s: %v", name, err)
+		return s.logAction("remove", "template", name, name, false, fmt.Errorf("failed to delete template: %w", err))
+	}
+	if !deleted {
+		return s.logAction("remove", "template", name, name, false, fmt.Errorf("%w: %s", ErrTemplateNotFound, name))
+	}
+
+	log.Printf("Template %s deleted", name)
+	return s.logAction("remove", "template", name, name, true, nil)
+}
+
+// CreateFromTemplate creates a container from a template's spec with the overrides
+// applied. Errors are those of ContainerService.CreateContainer, or ErrTemplateNotFound.
+func (s *TemplateService) CreateFromTemplate(ctx context.Context, name string, overrides TemplateOverrides) (*ContainerInfo, error) {
+	template, err := s.GetTemplate(name)
+	if err != nil {
+		return nil, err
+	}
+
+	req := template.Spec
+	if overrides.Name != "" {
+		req.Name = overrides.Name
+	}
+	if overrides.Ports != nil {
+		req.Ports = overrides.Ports
+	}
+	if len(overrides.Env) > 0 {
+		req.Env = mergeEnv(req.Env, overrides.Env)
+	}
+	if len(overrides.Labels) > 0 {
+		labels := make(map[string]string, len(req.Labels)+len(overrides.Labels))
+		maps.Copy(labels, req.Labels)
+		maps.Copy(labels, overrides.Labels)
+		req.Labels = labels
+	}
+	if overrides.Start != nil {
+		req.Start = *overrides.Start
+	}
+	req.AutoName = req.AutoName || overrides.AutoName
+	req.ConfirmReplace = overrides.ConfirmReplace
+
+	log.Printf("Creating container from template %s", name)
+	return s.containerService.CreateContainer(ctx, req)
+}
+
+// ValidateContainerSpec checks a container creation request without contacting the
+// daemon, so templates are rejected when saved rather than when used. The image does
+// not need to be present yet, and bind mount sources are only checked with checkMounts.
+// Failures match ErrInvalidContainerSpec.
+func ValidateContainerSpec(req CreateContainerRequest, checkMounts bool) error {
+	invalid := func(err error) error {
+		return fmt.Errorf("%w: %w", ErrInvalidContainerSpec, err)
+	}
+
+	if _, err := reference.ParseAnyReference(req.Image); err != nil {
+		return invalid(fmt.Errorf("invalid image %q: %w", req.Image, err))
+	}
+	if req.Name != "" && !containerNamePattern.MatchString(req.Name) {
+		return invalid(fmt.Errorf("invalid name %q: must match %s", req.Name, containerNamePattern.String()))
+	}
+	if err := ValidateConflictPolicy(req.OnConflict); err != nil {
+		return invalid(err)
+	}
+	if req.AutoName && req.OnConflict != "" && req.OnConflict != OnConflictError {
+		return invalid(fmt.Errorf("auto_name cannot be combined with on_conflict=%s", req.OnConflict))
+	}
+	if checkMounts {
+		if err := ValidateBindMounts(req.Mounts); err != nil {
+			return invalid(err)
+		}
+	}
+	if _, _, err := nat.ParsePortSpecs(req.Ports); err != nil {
+		return invalid(fmt.Errorf("invalid ports: %w", err))
+	}
+	if _, err := parseRestartPolicy(req.RestartPolicy); err != nil {
+		return invalid(err)
+	}
+	if _, err := BuildDeviceMappings(req.Devices); err != nil {
+		return invalid(err)
+	}
+	if _, err := BuildDeviceRequests(req.DeviceRequests); err != nil {
+		return invalid(err)
+	}
+	if _, err := BuildUlimits(req.Ulimits); err != nil {
+		return invalid(err)
+	}
+	return nil
+}
+
+// newTemplateRecord validates a template and encodes it for storage.
+// Replace confirmations are not stored; they must be given each time the template is used.
+func (s *TemplateService) newTemplateRecord(name, description string, spec CreateContainerRequest) (*models.ContainerTemplate, error) {
+	if !templateNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("%w: name %q must match %s", ErrInvalidTemplate, name, templateNamePattern.String())
+	}
+	if err := ValidateContainerSpec(spec, s.containerService.ValidatesMounts(spec)); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTemplate, err)
+	}
+	spec.ConfirmReplace = false
+
+	encoded, err := json.Marshal(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode template spec: %w", err)
+	}
+
+	return &models.ContainerTemplate{
+		Name:        name,
+		Description: strings.TrimSpace(description),
+		Spec:        string(encoded),
+		UpdatedAt:   time.Now(),
+	}, nil
+}
+
+// templateFromRecord decodes a stored template.
+func templateFromRecord(record *models.ContainerTemplate) (*ContainerTemplate, error) {
+	template := &ContainerTemplate{
+		Name:        record.Name,
+		Description: record.Description,
+		CreatedAt:   record.CreatedAt,
+		UpdatedAt:   record.UpdatedAt,
+	}
+	if err := json.Unmarshal([]byte(record.Spec), &template.Spec); err != nil {
+		return nil, fmt.Errorf("failed to decode template %s: %w", record.Name, err)
+	}
+	return template, nil
+}
+
+// mergeEnv returns base with the entries of overrides added, replacing entries of the same variable.
+func mergeEnv(base, overrides []string) []string {
+	overridden := envMap(overrides)
+
+	merged := make([]string, 0, len(base)+len(overrides))
+	for _, entry := range base {
+		key, _, _ := strings.Cut(entry, "=")
+		if _, ok := overridden[key]; !ok {
+			merged = append(merged, entry)
+		}
+	}
+	return append(merged, overrides...)
+}
+
+// logAction logs an action to the database.
+func (s *TemplateService) logAction(actionType, resourceType, resourceID, resourceName string, success bool, err error) error {
+	actionLog := &models.ActionLog{
+		ActionType:   actionType,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		ResourceName: resourceName,
+		Success:      success,
+		ExecutedAt:   time.Now(),
+	}
+
+	if err != nil {
+		actionLog.ErrorMessage = err.Error()
+	}
+
+	if logErr := s.actionLogRepo.Create(actionLog); logErr != nil {
+		log.Printf("Failed to log action: %v", logErr)
+	}
+
+	return err
+}
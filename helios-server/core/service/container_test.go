@@ -0,0 +1,136 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+)
+
+func TestFormatCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		args []string
+		want string
+	}{
+		{name: "empty", path: "", args: nil, want: ""},
+		{name: "no arguments", path: "nginx", args: nil, want: "nginx"},
+		{name: "arguments", path: "nginx", args: []string{"-g", "daemon off;"}, want: `nginx -g "daemon off;"`},
+		{name: "no path", path: "", args: []string{"redis-server", "--port", "6380"}, want: "redis-server --port 6380"},
+		{name: "empty argument", path: "echo", args: []string{""}, want: `echo ""`},
+		{name: "tab and newline", path: "printf", args: []string{"a\tb\n"}, want: `printf "a\tb\n"`},
+		{name: "quotes", path: "sh", args: []string{"-c", `echo "it's"`}, want: `sh -c "echo \"it's\""`},
+		{name: "path with space", path: "/opt/my app/run", args: []string{"--fast"}, want: `"/opt/my app/run" --fast`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatCommand(tt.path, tt.args); got != tt.want {
+				t.Errorf("formatCommand(%q, %q) = %s, want %s", tt.path, tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+// statsBody is a stats response body that records whether it was drained and closed.
+// After its content it fails with err, or reports EOF if err is nil.
+type statsBody struct {
+	content *strings.Reader
+	err     error
+	drained bool
+	closed  bool
+}
+
+func (b *statsBody) Read(p []byte) (int, error) {
+	n, _ := b.content.Read(p)
+	if n > 0 {
+		return n, nil
+	}
+	b.drained = true
+	if b.err != nil {
+		return 0, b.err
+	}
+	return 0, io.EOF
+}
+
+func (b *statsBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestDecodeStatsBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		err      error
+		wantErr  bool
+		wantCPUs uint32
+	}{
+		{name: "valid sample", content: `{"cpu_stats":{"online_cpus":4}}`, wantCPUs: 4},
+		{name: "connection error mid-decode", content: `{"cpu_stats":{"onl`, err: errors.New("connection reset"), wantErr: true},
+		{name: "malformed sample with trailing data", content: `{"cpu_stats":oops} more data`, wantErr: true},
+		{name: "empty body", content: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := &statsBody{content: strings.NewReader(tt.content), err: tt.err}
+			stats, err := decodeStatsBody(body)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeStatsBody() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !body.closed {
+				t.Error("body was not closed")
+			}
+			if !body.drained {
+				t.Error("body was not drained")
+			}
+			if err == nil && stats.CPUStats.OnlineCPUs != tt.wantCPUs {
+				t.Errorf("online CPUs = %d, want %d", stats.CPUStats.OnlineCPUs, tt.wantCPUs)
+			}
+		})
+	}
+}
+
+func TestContainerNameConsistency(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string // As reported by the daemon
+		want string
+	}{
+		{name: "leading slash", raw: "/web", want: "web"},
+		{name: "no leading slash", raw: "web", want: "web"},
+		{name: "bare slash", raw: "/", want: ""},
+		{name: "only the first slash is stripped", raw: "//web", want: "/web"},
+		{name: "empty", raw: "", want: ""},
+	}
+
+	s := &ContainerService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := containerDisplayName(tt.raw); got != tt.want {
+				t.Errorf("containerDisplayName(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+
+			listed := s.convertToContainerInfo(types.Container{ID: "abc", Names: []string{tt.raw}})
+			detailed := containerInfoFromJSON(types.ContainerJSON{
+				ContainerJSONBase: &types.ContainerJSONBase{
+					ID:         "abc",
+					Name:       tt.raw,
+					State:      &types.ContainerState{Status: "exited"},
+					HostConfig: &container.HostConfig{},
+				},
+				Config:          &container.Config{},
+				NetworkSettings: &types.NetworkSettings{},
+			})
+			if listed.Name != tt.want || detailed.Name != tt.want {
+				t.Errorf("list name = %q, detail name = %q, want both %q", listed.Name, detailed.Name, tt.want)
+			}
+		})
+	}
+}
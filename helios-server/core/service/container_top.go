@@ -0,0 +1,67 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Metrics containers can be ranked by in TopContainers.
+const (
+	TopByCPU     = "cpu"     // CPU percentage
+	TopByMemory  = "memory"  // Memory usage in bytes
+	TopByNetwork = "network" // Received plus transmitted bytes
+)
+
+// TopContainer is a container's rank entry in a resource usage leaderboard.
+type TopContainer struct {
+	ID    string          `json:"id"`
+	Name  string          `json:"name"`
+	Value float64         `json:"value"` // Value of the ranking metric
+	Stats *ContainerStats `json:"stats"`
+}
+
+// TopContainers ranks running containers by a resource metric using the stats cache,
+// returning at most limit entries (all when limit <= 0). Containers are ordered from
+// heaviest to lightest unless ascending is set.
+func (s *ContainerService) TopContainers(by string, ascending bool, limit int) ([]TopContainer, error) {
+	var metric func(*ContainerStats) float64
+	switch by {
+	case TopByCPU:
+		metric = func(st *ContainerStats) float64 { return st.CPUPercent }
+	case TopByMemory:
+		metric = func(st *ContainerStats) float64 { return float64(st.MemoryUsage) }
+	case TopByNetwork:
+		metric = func(st *ContainerStats) float64 { return float64(st.NetworkRx + st.NetworkTx) }
+	default:
+		return nil, fmt.Errorf("invalid metric %q: must be %s, %s or %s", by, TopByCPU, TopByMemory, TopByNetwork)
+	}
+
+	stats := s.statsCache.GetAllContainerStats()
+	names := s.statsCache.GetContainerNames()
+
+	result := make([]TopContainer, 0, len(stats))
+	for id, st := range stats {
+		result = append(result, TopContainer{
+			ID:    id,
+			Name:  names[id],
+			Value: metric(st),
+			Stats: st,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Value == result[j].Value {
+			return result[i].Name < result[j].Name
+		}
+		if ascending {
+			return result[i].Value < result[j].Value
+		}
+		return result[i].Value > result[j].Value
+	})
+
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
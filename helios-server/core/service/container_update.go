@@ -0,0 +1,236 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
+)
+
+// ContainerUpdateResult represents the outcome of an update-in-place operation.
+type ContainerUpdateResult struct {
+	ContainerID    string `json:"container_id"`
+	ContainerName  string `json:"container_name"`
+	Image          string `json:"image"`
+	OldImageID     string `json:"old_image_id"`
+	NewImageID     string `json:"new_image_id"`
+	Updated        bool   `json:"updated"`
+	Message        string `json:"message"`
+	OldContainerID string `json:"old_container_id,omitempty"`
+}
+
+// UpdateContainer pulls the container's image reference and, if a newer image was fetched,
+// recreates the container with the same configuration on the new image.
+// Pull progress is streamed on the progress channel; the final result or error is
+// delivered on the result and error channels once the operation completes.
+// If recreation fails, the original container is restored.
+func (s *ContainerService) UpdateContainer(ctx context.Context, containerID string) (<-chan PullProgress, <-chan *ContainerUpdateResult, <-chan error, error) {
+	containerJSON, err := s.dockerClient.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, nil, nil, s.logAction("update", "container", containerID, "", false, err)
+	}
+	name := containerDisplayName(containerJSON.Name)
+
+	imageRef := containerJSON.Config.Image
+	if strings.HasPrefix(imageRef, "sha256:") {
+		err := fmt.Errorf("container %s was created from an image ID, not a tag; nothing to pull", name)
+		return nil, nil, nil, s.logAction("update", "container", containerID, name, false, err)
+	}
+
+	reader, err := s.dockerClient.ImagePull(ctx, imageRef, image.PullOptions{
+		RegistryAuth: s.credentials.RegistryAuth(imageRef),
+	})
+	if err != nil {
+		log.Printf("Failed to start pull for image %s: %v", imageRef, err)
+		return nil, nil, nil, s.logAction("update", "container", containerID, name, false, err)
+	}
+
+	progressChan := make(chan PullProgress, 10)
+	resultChan := make(chan *ContainerUpdateResult, 1)
+	errChan := make(chan error, 1)
+
+	go func() {
+		defer close(progressChan)
+		defer close(resultChan)
+		defer close(errChan)
+
+		if err := streamPullProgress(ctx, reader, progressChan); err != nil {
+			errChan <- s.logAction("update", "container", containerID, name, false, err)
+			return
+		}
+
+		result, err := s.recreateIfNewer(ctx, containerJSON, imageRef)
+		if err != nil {
+			errChan <- s.logAction("update", "container", containerID, name, false, err)
+			return
+		}
+
+		if result.Updated {
+			log.Printf("Container %s updated: image %s -> %s", result.ContainerName, result.OldImageID, result.NewImageID)
+			resourceName := fmt.Sprintf("%s (%s -> %s)", result.ContainerName, ShortID(result.OldImageID), ShortID(result.NewImageID))
+			s.logAction("update", "container", result.ContainerID, resourceName, true, nil)
+		}
+		resultChan <- result
+	}()
+
+	return progressChan, resultChan, errChan, nil
+}
+
+// streamPullProgress decodes a pull stream, forwarding progress and returning the first error.
+func streamPullProgress(ctx context.Context, reader io.ReadCloser, progressChan chan<- PullProgress) error {
+	defer reader.Close()
+
+	decoder := json.NewDecoder(reader)
+	for {
+		var progress PullProgress
+		if err := decoder.Decode(&progress); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("failed to decode progress: %w", err)
+		}
+
+		select {
+		case progressChan <- progress:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		if progress.Error != "" {
+			return fmt.Errorf("pull error: %s", progress.Error)
+		}
+	}
+}
+
+// recreateIfNewer compares the freshly pulled image with the container's image and
+// recreates the container on the new image when they differ.
+func (s *ContainerService) recreateIfNewer(ctx context.Context, old types.ContainerJSON, imageRef string) (*ContainerUpdateResult, error) {
+	name := containerDisplayName(old.Name)
+
+	pulled, _, err := s.dockerClient.ImageInspectWithRaw(ctx, imageRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect pulled image: %w", err)
+	}
+
+	result := &ContainerUpdateResult{
+		ContainerID:   old.ID,
+		ContainerName: name,
+		Image:         imageRef,
+		OldImageID:    old.Image,
+		NewImageID:    pulled.ID,
+	}
+
+	if pulled.ID == old.Image {
+		result.Message = "Container is already up to date"
+		return result, nil
+	}
+
+	newID, err := s.recreateContainer(ctx, old, old.Config, old.HostConfig)
+	if err != nil {
+		return nil, fmt.Errorf("update from image %s -> %s: %w", ShortID(old.Image), ShortID(pulled.ID), err)
+	}
+
+	result.ContainerID = newID
+	result.OldContainerID = old.ID
+	result.Updated = true
+	result.Message = "Container updated to the latest image"
+	return result, nil
+}
+
+// recreateContainer replaces old with a new container built from the given configuration,
+// keeping the same name and network attachments. The old container is stopped and renamed
+// out of the way first; if anything fails it is renamed back and restarted.
+// On success the old container is removed and the new container ID is returned.
+func (s *ContainerService) recreateContainer(ctx context.Context, old types.ContainerJSON, config *container.Config, hostConfig *container.HostConfig) (string, error) {
+	name := containerDisplayName(old.Name)
+	wasRunning := old.State != nil && old.State.Running
+	backupName := fmt.Sprintf("%s-helios-old-%d", name, time.Now().Unix())
+
+	timeout := 10
+	if wasRunning {
+		if err := s.dockerClient.ContainerStop(ctx, old.ID, container.StopOptions{Timeout: &timeout}); err != nil {
+			return "", fmt.Errorf("failed to stop container: %w", err)
+		}
+	}
+
+	if err := s.dockerClient.ContainerRename(ctx, old.ID, backupName); err != nil {
+		s.restoreContainer(ctx, old.ID, "", wasRunning)
+		return "", fmt.Errorf("failed to rename old container: %w", err)
+	}
+
+	// The primary network is attached at create time, others are connected afterwards
+	primaryNetwork := string(hostConfig.NetworkMode)
+	endpoints := map[string]*network.EndpointSettings{}
+	if old.NetworkSettings != nil {
+		for netName, ep := range old.NetworkSettings.Networks {
+			endpoints[netName] = &network.EndpointSettings{
+				IPAMConfig: ep.IPAMConfig,
+				Links:      ep.Links,
+				Aliases:    ep.Aliases,
+				DriverOpts: ep.DriverOpts,
+			}
+		}
+	}
+	networkingConfig := &network.NetworkingConfig{EndpointsConfig: map[string]*network.EndpointSettings{}}
+	if ep, ok := endpoints[primaryNetwork]; ok {
+		networkingConfig.EndpointsConfig[primaryNetwork] = ep
+	}
+
+	created, err := s.dockerClient.ContainerCreate(ctx, config, hostConfig, networkingConfig, nil, name)
+	if err != nil {
+		s.restoreContainer(ctx, old.ID, name, wasRunning)
+		return "", fmt.Errorf("failed to create container: %w", err)
+	}
+
+	rollback := func(cause error) (string, error) {
+		if rmErr := s.dockerClient.ContainerRemove(ctx, created.ID, container.RemoveOptions{Force: true}); rmErr != nil {
+			log.Printf("Failed to remove new container %s during rollback: %v", created.ID, rmErr)
+		}
+		s.restoreContainer(ctx, old.ID, name, wasRunning)
+		return "", cause
+	}
+
+	for netName, ep := range endpoints {
+		if netName == primaryNetwork {
+			continue
+		}
+		if err := s.dockerClient.NetworkConnect(ctx, netName, created.ID, ep); err != nil {
+			return rollback(fmt.Errorf("failed to connect network %s: %w", netName, err))
+		}
+	}
+
+	if wasRunning {
+		if err := s.dockerClient.ContainerStart(ctx, created.ID, container.StartOptions{}); err != nil {
+			return rollback(fmt.Errorf("failed to start new container: %w", err))
+		}
+	}
+
+	if err := s.dockerClient.ContainerRemove(ctx, old.ID, container.RemoveOptions{}); err != nil {
+		log.Printf("Recreated container %s but failed to remove old container %s: %v", name, old.ID, err)
+	}
+
+	return created.ID, nil
+}
+
+// restoreContainer renames a container back to its original name and restarts it if needed.
+func (s *ContainerService) restoreContainer(ctx context.Context, containerID, name string, start bool) {
+	if name != "" {
+		if err := s.dockerClient.ContainerRename(ctx, containerID, name); err != nil {
+			log.Printf("Failed to restore name of container %s: %v", containerID, err)
+		}
+	}
+	if start {
+		if err := s.dockerClient.ContainerStart(ctx, containerID, container.StartOptions{}); err != nil {
+			log.Printf("Failed to restart container %s during rollback: %v", containerID, err)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"nfcunha/helios/core/repository"
+	"nfcunha/helios/utils/config"
+)
+
+// bytesPerGiB converts memory readings to GiB for the cost model.
+const bytesPerGiB = 1024 * 1024 * 1024
+
+// ContainerCost is the estimated cost of one container over a time range.
+type ContainerCost struct {
+	ContainerID   string  `json:"container_id"`
+	ContainerName string  `json:"container_name"`
+	Samples       int     `json:"samples"`
+	CPUHours      float64 `json:"cpu_hours"` // CPU core-hours
+	GBHours       float64 `json:"gb_hours"`  // Memory GiB-hours
+	CPUCost       float64 `json:"cpu_cost"`
+	MemoryCost    float64 `json:"memory_cost"`
+	TotalCost     float64 `json:"total_cost"`
+}
+
+// CostModel is the pricing used for an estimate.
+type CostModel struct {
+	CPUHour  float64 `json:"cpu_hour"`
+	GBHour   float64 `json:"gb_hour"`
+	Currency string  `json:"currency"`
+}
+
+// CostReport is the estimated cost of all containers over a time range.
+type CostReport struct {
+	From       time.Time       `json:"from"`
+	To         time.Time       `json:"to"`
+	Model      CostModel       `json:"model"`
+	Containers []ContainerCost `json:"containers"`
+	TotalCost  float64         `json:"total_cost"`
+}
+
+// CostEstimator estimates per-container resource cost from recorded health check readings.
+type CostEstimator struct {
+	repo          *repository.HealthCheckLogRepository
+	healthChecker *HealthChecker
+	model         config.CostConfig
+}
+
+// NewCostEstimator creates a new cost estimator using the given cost model.
+func NewCostEstimator(repo *repository.HealthCheckLogRepository, healthChecker *HealthChecker, model config.CostConfig) *CostEstimator {
+	return &CostEstimator{
+		repo:          repo,
+		healthChecker: healthChecker,
+		model:         model,
+	}
+}
+
+// EstimateCosts estimates the cost of every container with readings between from and to.
+// Each reading is assumed to hold until the next one, up to twice the health check
+// interval, so gaps while a container was stopped or Helios was down are not billed.
+// The last reading of a container counts for one interval.
+func (e *CostEstimator) EstimateCosts(from, to time.Time) (*CostReport, error) {
+	samples, err := e.repo.GetUsageSamples(from, to)
+	if err != nil {
+		log.Printf("Failed to load usage samples: %v", err)
+		return nil, fmt.Errorf("failed to load usage samples: %w", err)
+	}
+
+	interval := e.healthChecker.Config().Interval
+	maxGap := 2 * interval
+
+	report := &CostReport{
+		From:       from,
+		To:         to,
+		Model:      CostModel{CPUHour: e.model.CPUHour, GBHour: e.model.GBHour, Currency: e.model.Currency},
+		Containers: []ContainerCost{},
+	}
+
+	for i := 0; i < len(samples); {
+		cost := ContainerCost{
+			ContainerID:   samples[i].ContainerID,
+			ContainerName: samples[i].ContainerName,
+		}
+
+		for ; i < len(samples) && samples[i].ContainerID == cost.ContainerID; i++ {
+			sample := samples[i]
+
+			weight := interval
+			if i+1 < len(samples) && samples[i+1].ContainerID == cost.ContainerID {
+				weight = min(samples[i+1].CheckedAt.Sub(sample.CheckedAt), maxGap)
+			}
+			weight = min(weight, to.Sub(sample.CheckedAt))
+			hours := weight.Hours()
+
+			cost.Samples++
+			cost.CPUHours += sample.ResourceCPU / 100 * hours
+			cost.GBHours += float64(sample.ResourceMemory) / bytesPerGiB * hours
+			cost.ContainerName = sample.ContainerName // Report the most recent name
+		}
+
+		cost.CPUCost = cost.CPUHours * e.model.CPUHour
+		cost.MemoryCost = cost.GBHours * e.model.GBHour
+		cost.TotalCost = cost.CPUCost + cost.MemoryCost
+		report.TotalCost += cost.TotalCost
+		report.Containers = append(report.Containers, cost)
+	}
+
+	sort.SliceStable(report.Containers, func(i, j int) bool {
+		return report.Containers[i].TotalCost > report.Containers[j].TotalCost
+	})
+
+	return report, nil
+}
@@ -0,0 +1,231 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"nfcunha/helios/core/models"
+	"nfcunha/helios/core/repository"
+	"nfcunha/helios/utils/docker"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+)
+
+// DiskService reports and reclaims disk space used by Docker.
+type DiskService struct {
+	dockerClient  *docker.Client
+	actionLogRepo *repository.ActionLogRepository
+}
+
+// DiskUsageCategory is the disk usage of one kind of Docker object.
+type DiskUsageCategory struct {
+	Count       int   `json:"count"`
+	Active      int   `json:"active"` // Objects in use by a container (running containers, for containers)
+	Size        int64 `json:"size"`
+	Reclaimable int64 `json:"reclaimable"`
+}
+
+// DiskUsageReport summarizes the disk space used by Docker, like `docker system df`.
+type DiskUsageReport struct {
+	Images     DiskUsageCategory `json:"images"`
+	Containers DiskUsageCategory `json:"containers"`
+	Volumes    DiskUsageCategory `json:"volumes"`
+	BuildCache DiskUsageCategory `json:"build_cache"`
+	TotalSize  int64             `json:"total_size"`
+}
+
+// BuildCacheEntry is one BuildKit cache record.
+type BuildCacheEntry struct {
+	ID          string     `json:"id"`
+	Type        string     `json:"type"`
+	Description string     `json:"description"`
+	Size        int64      `json:"size"`
+	InUse       bool       `json:"in_use"`
+	Shared      bool       `json:"shared"`
+	UsageCount  int        `json:"usage_count"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+}
+
+// BuildCacheReport summarizes the build cache, largest entries first.
+type BuildCacheReport struct {
+	TotalSize   int64             `json:"total_size"`
+	Reclaimable int64             `json:"reclaimable"` // Entries neither in use nor shared
+	Entries     []BuildCacheEntry `json:"entries"`
+}
+
+// BuildCachePruneResult reports a build cache prune.
+type BuildCachePruneResult struct {
+	CachesDeleted  []string `json:"caches_deleted"`
+	SpaceReclaimed uint64   `json:"space_reclaimed"`
+}
+
+// NewDiskService creates a new disk service.
+func NewDiskService(dockerClient *docker.Client, actionLogRepo *repository.ActionLogRepository) *DiskService {
+	return &DiskService{
+		dockerClient:  dockerClient,
+		actionLogRepo: actionLogRepo,
+	}
+}
+
+// GetDiskUsage reports the disk space used by images, containers, volumes and the build cache.
+func (s *DiskService) GetDiskUsage(ctx context.Context) (*DiskUsageReport, error) {
+	usage, err := s.dockerClient.DiskUsage(ctx, types.DiskUsageOptions{})
+	if err != nil {
+		log.Printf("Failed to get disk usage: %v", err)
+		return nil, fmt.Errorf("failed to get disk usage: %w", err)
+	}
+
+	report := &DiskUsageReport{}
+
+	var imageSizes int64
+	for _, img := range usage.Images {
+		report.Images.Count++
+		imageSizes += img.Size
+		if img.Containers > 0 {
+			report.Images.Active++
+		} else {
+			// Layers shared with other images are not freed by removing this one
+			report.Images.Reclaimable += img.Size - max(img.SharedSize, 0)
+		}
+	}
+	// Image sizes count shared layers once per image; the daemon's layer total does not
+	report.Images.Size = usage.LayersSize
+	if report.Images.Size <= 0 {
+		report.Images.Size = imageSizes
+	}
+
+	for _, c := range usage.Containers {
+		report.Containers.Count++
+		report.Containers.Size += c.SizeRw
+		if c.State == "running" {
+			report.Containers.Active++
+		} else {
+			report.Containers.Reclaimable += c.SizeRw
+		}
+	}
+
+	for _, v := range usage.Volumes {
+		report.Volumes.Count++
+		if v.UsageData == nil || v.UsageData.Size < 0 {
+			continue
+		}
+		report.Volumes.Size += v.UsageData.Size
+		if v.UsageData.RefCount > 0 {
+			report.Volumes.Active++
+		} else {
+			report.Volumes.Reclaimable += v.UsageData.Size
+		}
+	}
+
+	cache := buildCacheReport(usage.BuildCache)
+	report.BuildCache = DiskUsageCategory{
+		Count:       len(cache.Entries),
+		Size:        cache.TotalSize,
+		Reclaimable: cache.Reclaimable,
+	}
+	for _, entry := range cache.Entries {
+		if entry.InUse {
+			report.BuildCache.Active++
+		}
+	}
+
+	report.TotalSize = report.Images.Size + report.Containers.Size + report.Volumes.Size + report.BuildCache.Size
+	return report, nil
+}
+
+// GetBuildCache reports the BuildKit build cache.
+func (s *DiskService) GetBuildCache(ctx context.Context) (*BuildCacheReport, error) {
+	usage, err := s.dockerClient.DiskUsage(ctx, types.DiskUsageOptions{
+		Types: []types.DiskUsageObject{types.BuildCacheObject},
+	})
+	if err != nil {
+		log.Printf("Failed to get build cache usage: %v", err)
+		return nil, fmt.Errorf("failed to get build cache usage: %w", err)
+	}
+
+	return buildCacheReport(usage.BuildCache), nil
+}
+
+// PruneBuildCache removes unused build cache. Without all, only cache not referenced
+// by any image is removed; with a positive until, only cache unused for that long.
+func (s *DiskService) PruneBuildCache(ctx context.Context, all bool, until time.Duration) (*BuildCachePruneResult, error) {
+	opts := types.BuildCachePruneOptions{All: all}
+	if until > 0 {
+		opts.Filters = filters.NewArgs(filters.Arg("until", until.String()))
+	}
+
+	report, err := s.dockerClient.BuildCachePrune(ctx, opts)
+	if err != nil {
+		log.Printf("Failed to prune build cache: %v", err)
+		s.logAction("prune", "build_cache", "all", "all", false, err)
+		return nil, fmt.Errorf("failed to prune build cache: %w", err)
+	}
+
+	result := &BuildCachePruneResult{
+		CachesDeleted:  report.CachesDeleted,
+		SpaceReclaimed: report.SpaceReclaimed,
+	}
+	if result.CachesDeleted == nil {
+		result.CachesDeleted = []string{}
+	}
+
+	log.Printf("Pruned %d build cache entries, reclaimed space: %d bytes", len(result.CachesDeleted), result.SpaceReclaimed)
+	s.logAction("prune", "build_cache", "all", "all", true, nil)
+	return result, nil
+}
+
+// buildCacheReport summarizes build cache records, largest first.
+func buildCacheReport(records []*types.BuildCache) *BuildCacheReport {
+	report := &BuildCacheReport{Entries: make([]BuildCacheEntry, 0, len(records))}
+
+	for _, record := range records {
+		report.TotalSize += record.Size
+		if !record.InUse && !record.Shared {
+			report.Reclaimable += record.Size
+		}
+		report.Entries = append(report.Entries, BuildCacheEntry{
+			ID:          record.ID,
+			Type:        record.Type,
+			Description: record.Description,
+			Size:        record.Size,
+			InUse:       record.InUse,
+			Shared:      record.Shared,
+			UsageCount:  record.UsageCount,
+			CreatedAt:   record.CreatedAt,
+			LastUsedAt:  record.LastUsedAt,
+		})
+	}
+
+	sort.SliceStable(report.Entries, func(i, j int) bool {
+		return report.Entries[i].Size > report.Entries[j].Size
+	})
+	return report
+}
+
+// logAction logs an action to the database.
+func (s *DiskService) logAction(actionType, resourceType, resourceID, resourceName string, success bool, err error) error {
+	actionLog := &models.ActionLog{
+		ActionType:   actionType,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		ResourceName: resourceName,
+		Success:      success,
+		ExecutedAt:   time.Now(),
+	}
+
+	if err != nil {
+		actionLog.ErrorMessage = err.Error()
+	}
+
+	if logErr := s.actionLogRepo.Create(actionLog); logErr != nil {
+		log.Printf("Failed to log action: %v", logErr)
+	}
+
+	return err
+}
@@ -0,0 +1,139 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"nfcunha/helios/core/repository"
+
+	"github.com/docker/docker/api/types/events"
+)
+
+// DockerEvent is a decoded daemon event as sent to clients.
+type DockerEvent struct {
+	Type       string            `json:"type"`   // container, image, volume or network
+	Action     string            `json:"action"` // e.g. start, die, destroy, pull
+	ID         string            `json:"id"`
+	Name       string            `json:"name,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Time       time.Time         `json:"time"`
+}
+
+// NewDockerEvent decodes a daemon event message.
+func NewDockerEvent(msg events.Message) DockerEvent {
+	event := DockerEvent{
+		Type:       string(msg.Type),
+		Action:     string(msg.Action),
+		ID:         msg.Actor.ID,
+		Name:       msg.Actor.Attributes["name"],
+		Attributes: msg.Actor.Attributes,
+		Time:       time.Unix(0, msg.TimeNano),
+	}
+	if msg.TimeNano == 0 {
+		event.Time = time.Unix(msg.Time, 0)
+	}
+	return event
+}
+
+// IsLifecycleEvent reports whether a daemon event concerns the lifecycle of a
+// container, image, volume or network. Exec events are excluded; they fire for
+// every health probe.
+func IsLifecycleEvent(msg events.Message) bool {
+	switch msg.Type {
+	case events.ContainerEventType:
+		return !strings.HasPrefix(string(msg.Action), "exec_")
+	case events.ImageEventType, events.VolumeEventType, events.NetworkEventType:
+		return true
+	}
+	return false
+}
+
+// significantActions lists the lifecycle events recorded in the event log, by type.
+// Container state changes are all kept so a container's lifecycle timeline can
+// include transitions made outside Helios; kill is left out since stop implies it.
+var significantActions = map[events.Type]map[events.Action]bool{
+	events.ContainerEventType: {
+		events.ActionCreate: true, events.ActionStart: true, events.ActionRestart: true,
+		events.ActionStop: true, events.ActionDie: true, events.ActionOOM: true,
+		events.ActionPause: true, events.ActionUnPause: true, events.ActionRename: true,
+		events.ActionDestroy: true,
+	},
+	events.ImageEventType:   {events.ActionPull: true, events.ActionDelete: true},
+	events.VolumeEventType:  {events.ActionCreate: true, events.ActionDestroy: true},
+	events.NetworkEventType: {events.ActionCreate: true, events.ActionDestroy: true},
+}
+
+// DockerEventRecorder stores significant daemon events in the event log, so
+// changes made outside Helios leave a trail.
+type DockerEventRecorder struct {
+	eventLogRepo *repository.EventLogRepository
+	scope        *ContainerScope
+	cancel       context.CancelFunc
+}
+
+// NewDockerEventRecorder creates a recorder and starts consuming events from the bus.
+// Container events outside the given scope are ignored.
+func NewDockerEventRecorder(eventBus *EventBus, eventLogRepo *repository.EventLogRepository, scope *ContainerScope) *DockerEventRecorder {
+	ctx, cancel := context.WithCancel(context.Background())
+	recorder := &DockerEventRecorder{
+		eventLogRepo: eventLogRepo,
+		scope:        scope,
+		cancel:       cancel,
+	}
+
+	msgs, unsubscribe := eventBus.Subscribe(64)
+	go func() {
+		defer unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-msgs:
+				if !ok {
+					return
+				}
+				recorder.record(msg)
+			}
+		}
+	}()
+
+	log.Println("Docker event recorder started")
+	return recorder
+}
+
+// record stores the event if it is significant.
+func (r *DockerEventRecorder) record(msg events.Message) {
+	if !significantActions[msg.Type][msg.Action] {
+		return
+	}
+	if msg.Type == events.ContainerEventType && !r.scope.Allows(msg.Actor.Attributes["name"]) {
+		return
+	}
+
+	event := NewDockerEvent(msg)
+	name := event.Name
+	if name == "" {
+		name = event.ID
+	}
+
+	level := "info"
+	message := fmt.Sprintf("%s %s: %s", event.Type, event.Action, name)
+	if msg.Action == events.ActionDie {
+		exitCode := msg.Actor.Attributes["exitCode"]
+		message = fmt.Sprintf("container %s exited with code %s", name, exitCode)
+		if exitCode != "0" {
+			level = "warning"
+		}
+	}
+
+	r.eventLogRepo.EmitEvent("docker", level, message, event)
+}
+
+// Stop stops consuming events.
+func (r *DockerEventRecorder) Stop() {
+	r.cancel()
+}
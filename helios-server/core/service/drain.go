@@ -0,0 +1,175 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"nfcunha/helios/core/models"
+	"nfcunha/helios/core/repository"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// ErrDrainPending is returned when draining while a previous drain has not been restored.
+var ErrDrainPending = errors.New("a previous drain has not been restored yet")
+
+// ErrNothingToRestore is returned when restoring without a pending drain.
+var ErrNothingToRestore = errors.New("no drained containers to restore")
+
+// DrainResult reports the outcome of a drain or restore.
+type DrainResult struct {
+	DrainID   string                `json:"drain_id"`
+	Results   []BulkOperationResult `json:"results"`
+	Succeeded int                   `json:"succeeded"`
+	Failed    int                   `json:"failed"`
+}
+
+// DrainService stops all running containers for maintenance and starts exactly
+// those containers again afterwards.
+type DrainService struct {
+	containerService *ContainerService
+	repo             *repository.DrainRepository
+}
+
+// NewDrainService creates a new drain service.
+func NewDrainService(containerService *ContainerService, repo *repository.DrainRepository) *DrainService {
+	return &DrainService{
+		containerService: containerService,
+		repo:             repo,
+	}
+}
+
+// Drain records every running container in scope and stops them, dependents before
+// their Compose dependencies. The container Helios itself runs in is left running.
+// Containers are recorded before they are stopped, so an interrupted drain can still be restored.
+func (s *DrainService) Drain(ctx context.Context) (*DrainResult, error) {
+	pending, err := s.repo.GetPending()
+	if err != nil {
+		log.Printf("Failed to load pending drain: %v", err)
+		return nil, fmt.Errorf("failed to load pending drain: %w", err)
+	}
+	if len(pending) > 0 {
+		return nil, ErrDrainPending
+	}
+
+	containers, err := s.containerService.dockerClient.ContainerList(ctx, container.ListOptions{})
+	if err != nil {
+		log.Printf("Failed to list containers to drain: %v", err)
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	containers = s.containerService.scope.Filter(containers)
+
+	// Stop order is the reverse of start order
+	ordered := dependencyOrder(containers)
+	self := selfContainerID()
+	drainID := strconv.FormatInt(time.Now().UnixNano(), 10)
+	now := time.Now()
+
+	var records []*models.DrainedContainer
+	for i := len(ordered) - 1; i >= 0; i-- {
+		c := ordered[i]
+		if self != "" && strings.HasPrefix(c.ID, self) {
+			continue
+		}
+		name := ""
+		if len(c.Names) > 0 {
+			name = containerDisplayName(c.Names[0])
+		}
+		records = append(records, &models.DrainedContainer{
+			DrainID:       drainID,
+			ContainerID:   c.ID,
+			ContainerName: name,
+			Position:      len(records),
+			DrainedAt:     now,
+		})
+	}
+
+	if err := s.repo.Create(records); err != nil {
+		log.Printf("Failed to record drained containers: %v", err)
+		return nil, s.containerService.logAction("drain", "system", drainID, "", false, fmt.Errorf("failed to record drained containers: %w", err))
+	}
+
+	result := &DrainResult{DrainID: drainID, Results: []BulkOperationResult{}}
+	for _, record := range records {
+		op := BulkOperationResult{ContainerID: record.ContainerID, ContainerName: record.ContainerName}
+		if err := s.containerService.StopContainer(ctx, record.ContainerID); err != nil {
+			op.Error = err.Error()
+			result.Failed++
+		} else {
+			op.Success = true
+			result.Succeeded++
+		}
+		result.Results = append(result.Results, op)
+	}
+
+	log.Printf("Drain %s stopped %d containers (%d failed)", drainID, result.Succeeded, result.Failed)
+	s.containerService.logAction("drain", "system", drainID, fmt.Sprintf("%d containers", len(records)), result.Failed == 0, drainError(result))
+	return result, nil
+}
+
+// Restore starts the containers of the pending drain in the reverse of the order they
+// were stopped. Containers that cannot be started (e.g. removed since) are reported
+// but still marked restored, so a later drain is not blocked.
+func (s *DrainService) Restore(ctx context.Context) (*DrainResult, error) {
+	pending, err := s.repo.GetPending()
+	if err != nil {
+		log.Printf("Failed to load pending drain: %v", err)
+		return nil, fmt.Errorf("failed to load pending drain: %w", err)
+	}
+	if len(pending) == 0 {
+		return nil, ErrNothingToRestore
+	}
+
+	result := &DrainResult{DrainID: pending[0].DrainID, Results: []BulkOperationResult{}}
+	for i := len(pending) - 1; i >= 0; i-- {
+		record := pending[i]
+		op := BulkOperationResult{ContainerID: record.ContainerID, ContainerName: record.ContainerName}
+		if err := s.containerService.StartContainer(ctx, record.ContainerID); err != nil {
+			op.Error = err.Error()
+			result.Failed++
+		} else {
+			op.Success = true
+			result.Succeeded++
+		}
+		result.Results = append(result.Results, op)
+
+		if err := s.repo.MarkRestored(record.ID, time.Now()); err != nil {
+			log.Printf("Failed to mark drained container %s restored: %v", record.ContainerName, err)
+		}
+	}
+
+	log.Printf("Restore of drain %s started %d containers (%d failed)", result.DrainID, result.Succeeded, result.Failed)
+	s.containerService.logAction("restore", "system", result.DrainID, fmt.Sprintf("%d containers", len(pending)), result.Failed == 0, drainError(result))
+	return result, nil
+}
+
+// drainError summarizes failed operations for the action log.
+func drainError(result *DrainResult) error {
+	if result.Failed == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d containers failed", result.Failed, len(result.Results))
+}
+
+// selfContainerID returns the short ID of the container Helios runs in, or "" when it
+// does not appear to run in a container. Docker sets the hostname to the short ID.
+func selfContainerID() string {
+	if _, err := os.Stat("/.dockerenv"); err != nil {
+		return ""
+	}
+	hostname, err := os.Hostname()
+	if err != nil || len(hostname) != 12 {
+		return ""
+	}
+	if _, err := strconv.ParseUint(hostname, 16, 64); err != nil {
+		return ""
+	}
+	return hostname
+}
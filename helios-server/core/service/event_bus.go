@@ -0,0 +1,162 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"nfcunha/helios/core/repository"
+	"nfcunha/helios/utils/docker"
+
+	"github.com/docker/docker/api/types/events"
+)
+
+// EventBus fans out Docker daemon events to internal subscribers.
+// A single daemon subscription is shared by all subscribers. Each subscriber has its
+// own buffered channel; when a subscriber falls behind, events for it are dropped
+// instead of blocking the bus.
+type EventBus struct {
+	dockerClient *docker.Client
+	eventLogRepo *repository.EventLogRepository
+	subscribers  map[int]chan events.Message
+	nextID       int
+	mu           sync.RWMutex
+	ctx          context.Context
+	cancel       context.CancelFunc
+}
+
+// NewEventBus creates a new event bus and starts the daemon subscription.
+// Losing and regaining the connection to the daemon is recorded in the event log.
+func NewEventBus(dockerClient *docker.Client, eventLogRepo *repository.EventLogRepository) *EventBus {
+	ctx, cancel := context.WithCancel(context.Background())
+	bus := &EventBus{
+		dockerClient: dockerClient,
+		eventLogRepo: eventLogRepo,
+		subscribers:  make(map[int]chan events.Message),
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+
+	// Start background subscription
+	go bus.run()
+
+	return bus
+}
+
+// Subscribe registers a new subscriber with the given channel buffer size.
+// Returns the event channel and a function that unsubscribes and closes it.
+func (b *EventBus) Subscribe(bufferSize int) (<-chan events.Message, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	ch := make(chan events.Message, bufferSize)
+	b.subscribers[id] = ch
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subscribers, id)
+			close(ch)
+		})
+	}
+
+	return ch, unsubscribe
+}
+
+// Publish delivers an event to all subscribers without blocking.
+func (b *EventBus) Publish(msg events.Message) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for id, ch := range b.subscribers {
+		select {
+		case ch <- msg:
+		default:
+			log.Printf("Event bus subscriber %d is full, dropping %s/%s event", id, msg.Type, msg.Action)
+		}
+	}
+}
+
+// run subscribes to the daemon event stream and republishes every event.
+// The subscription is re-established with a backoff if the stream fails; while the
+// daemon does not answer pings, resubscribing waits.
+func (b *EventBus) run() {
+	backoff := time.Second
+	var lostAt time.Time
+
+	for {
+		if !lostAt.IsZero() {
+			if !b.reachable() {
+				select {
+				case <-b.ctx.Done():
+					return
+				case <-time.After(backoff):
+				}
+				if backoff < 30*time.Second {
+					backoff *= 2
+				}
+				continue
+			}
+			downtime := time.Since(lostAt).Round(time.Second)
+			log.Printf("Connection to the Docker daemon restored after %v", downtime)
+			b.eventLogRepo.EmitEvent("docker", "info", "Connection to the Docker daemon restored", map[string]interface{}{
+				"downtime_seconds": downtime.Seconds(),
+			})
+			lostAt = time.Time{}
+		}
+
+		msgs, errs := b.dockerClient.Events(b.ctx, events.ListOptions{})
+		log.Println("Event bus subscribed to Docker events")
+
+	stream:
+		for {
+			select {
+			case <-b.ctx.Done():
+				return
+			case msg := <-msgs:
+				backoff = time.Second
+				b.Publish(msg)
+			case err := <-errs:
+				if b.ctx.Err() != nil {
+					return
+				}
+				log.Printf("Docker event stream failed: %v (reconnecting in %v)", err, backoff)
+				if !b.reachable() {
+					lostAt = time.Now()
+					b.eventLogRepo.EmitEvent("docker", "error", "Lost connection to the Docker daemon", map[string]interface{}{
+						"error": err.Error(),
+					})
+				}
+				break stream
+			}
+		}
+
+		select {
+		case <-b.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		if backoff < 30*time.Second {
+			backoff *= 2
+		}
+	}
+}
+
+// reachable reports whether the daemon answers a ping.
+func (b *EventBus) reachable() bool {
+	ctx, cancel := context.WithTimeout(b.ctx, 5*time.Second)
+	defer cancel()
+	return b.dockerClient.Ping(ctx) == nil
+}
+
+// Stop cancels the daemon subscription.
+func (b *EventBus) Stop() {
+	b.cancel()
+}
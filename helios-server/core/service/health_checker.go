@@ -0,0 +1,501 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"nfcunha/helios/core/models"
+	"nfcunha/helios/core/repository"
+	"nfcunha/helios/utils/config"
+	"nfcunha/helios/utils/docker"
+	"nfcunha/helios/utils/metrics"
+	"nfcunha/helios/utils/statsutil"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+)
+
+// HealthChecker periodically records resource health for running containers.
+// Containers that start between ticks are checked as soon as their start event arrives,
+// and a pass can be triggered out of band with RunOnce. Containers that die because
+// they were OOM-killed are recorded as soon as their die event arrives.
+// Each pass also records published ports and flags containers whose port set changed.
+type HealthChecker struct {
+	dockerClient *docker.Client
+	repo         *repository.HealthCheckLogRepository
+	eventLogRepo *repository.EventLogRepository
+	notifier     *WebhookNotifier
+	scope        *ContainerScope
+	cfg          config.HealthCheckConfig
+	hysteresis   *HealthHysteresis
+	ports        map[string]string // containerID -> last recorded port set, guarded by runMu
+	reconfigured chan struct{}
+	cfgMu        sync.RWMutex
+	runMu        sync.Mutex // Serializes check passes
+	ctx          context.Context
+	cancel       context.CancelFunc
+}
+
+// NewHealthChecker creates a new health checker and starts the background loop.
+// Ticks are skipped while the checker is disabled, but RunOnce always performs a pass
+// and OOM kills are always recorded.
+func NewHealthChecker(dockerClient *docker.Client, repo *repository.HealthCheckLogRepository, eventLogRepo *repository.EventLogRepository, notifier *WebhookNotifier, scope *ContainerScope, cfg config.HealthCheckConfig, containerEvents <-chan events.Message) *HealthChecker {
+	ctx, cancel := context.WithCancel(context.Background())
+	checker := &HealthChecker{
+		dockerClient: dockerClient,
+		repo:         repo,
+		eventLogRepo: eventLogRepo,
+		notifier:     notifier,
+		scope:        scope,
+		cfg:          cfg,
+		hysteresis:   NewHealthHysteresis(cfg.BreachCount, cfg.RecoveryCount),
+		ports:        make(map[string]string),
+		reconfigured: make(chan struct{}, 1),
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+
+	go checker.loop(containerEvents)
+
+	return checker
+}
+
+// Config returns the active health check configuration.
+func (h *HealthChecker) Config() config.HealthCheckConfig {
+	h.cfgMu.RLock()
+	defer h.cfgMu.RUnlock()
+	return h.cfg
+}
+
+// Reconfigure replaces the active configuration and restarts the tick interval.
+// Breach streaks are reset when the breach or recovery counts change.
+// Configuration is only read from the environment at startup, so the settings
+// import is the one way to change it while running.
+func (h *HealthChecker) Reconfigure(cfg config.HealthCheckConfig) {
+	h.cfgMu.Lock()
+	if cfg.BreachCount != h.cfg.BreachCount || cfg.RecoveryCount != h.cfg.RecoveryCount {
+		h.hysteresis = NewHealthHysteresis(cfg.BreachCount, cfg.RecoveryCount)
+	}
+	h.cfg = cfg
+	h.cfgMu.Unlock()
+
+	log.Printf("Health checker reconfigured (enabled: %v, interval: %v, CPU threshold: %.1f%%, Memory threshold: %.1f%%, breaches: %d, recoveries: %d)",
+		cfg.Enabled, cfg.Interval, cfg.CPUThreshold, cfg.MemoryThreshold, cfg.BreachCount, cfg.RecoveryCount)
+
+	select {
+	case h.reconfigured <- struct{}{}:
+	default:
+	}
+}
+
+// RunOnce performs an immediate health check pass over all running containers.
+// Returns the number of containers checked.
+func (h *HealthChecker) RunOnce(ctx context.Context) (int, error) {
+	h.runMu.Lock()
+	defer h.runMu.Unlock()
+
+	containers, err := h.dockerClient.ContainerList(ctx, container.ListOptions{})
+	if err != nil {
+		log.Printf("Failed to list containers: %v", err)
+		return 0, fmt.Errorf("failed to list containers: %w", err)
+	}
+	containers = h.scope.Filter(containers)
+
+	cfg, hysteresis := h.snapshot()
+
+	active := make(map[string]bool, len(containers))
+	for _, c := range containers {
+		active[c.ID] = true
+		h.checkContainer(ctx, c, cfg, hysteresis)
+	}
+	hysteresis.Retain(active)
+	metrics.RetainHealth(active)
+	for id := range h.ports {
+		if !active[id] {
+			delete(h.ports, id)
+		}
+	}
+
+	return len(containers), nil
+}
+
+// Stop stops the background loop.
+func (h *HealthChecker) Stop() {
+	h.cancel()
+}
+
+// loop runs check passes at the configured interval until stopped.
+func (h *HealthChecker) loop(containerEvents <-chan events.Message) {
+	cfg := h.Config()
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	log.Printf("Health checker started (enabled: %v, interval: %v, CPU threshold: %.1f%%, Memory threshold: %.1f%%, breaches: %d, recoveries: %d)",
+		cfg.Enabled, cfg.Interval, cfg.CPUThreshold, cfg.MemoryThreshold, cfg.BreachCount, cfg.RecoveryCount)
+
+	for {
+		select {
+		case <-h.ctx.Done():
+			return
+		case <-h.reconfigured:
+			ticker.Reset(h.Config().Interval)
+		case msg, ok := <-containerEvents:
+			if !ok {
+				containerEvents = nil
+				continue
+			}
+			if msg.Type != events.ContainerEventType {
+				continue
+			}
+			switch {
+			case msg.Action == events.ActionStart && h.Config().Enabled:
+				h.checkContainerByID(msg.Actor.ID)
+			case msg.Action == events.ActionDie:
+				h.checkOOMKilled(msg.Actor.ID)
+			}
+		case <-ticker.C:
+			if !h.Config().Enabled {
+				continue
+			}
+			log.Println("Running health check...")
+			if _, err := h.RunOnce(h.ctx); err != nil {
+				log.Printf("Health check pass failed: %v", err)
+			}
+		}
+	}
+}
+
+// snapshot returns the current configuration and hysteresis tracker together.
+func (h *HealthChecker) snapshot() (config.HealthCheckConfig, *HealthHysteresis) {
+	h.cfgMu.RLock()
+	defer h.cfgMu.RUnlock()
+	return h.cfg, h.hysteresis
+}
+
+// checkContainerByID performs a health check on a single container identified by ID.
+func (h *HealthChecker) checkContainerByID(containerID string) {
+	h.runMu.Lock()
+	defer h.runMu.Unlock()
+
+	containers, err := h.dockerClient.ContainerList(h.ctx, container.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("id", containerID)),
+	})
+	if err != nil {
+		log.Printf("Failed to look up container %s for health check: %v", containerID, err)
+		return
+	}
+	containers = h.scope.Filter(containers)
+
+	cfg, hysteresis := h.snapshot()
+	for _, c := range containers {
+		h.checkContainer(h.ctx, c, cfg, hysteresis)
+	}
+}
+
+// checkContainer performs health check on a single container.
+func (h *HealthChecker) checkContainer(ctx context.Context, c types.Container, cfg config.HealthCheckConfig, hysteresis *HealthHysteresis) {
+	// Get container name (remove leading slash)
+	containerName := ""
+	if len(c.Names) > 0 {
+		containerName = containerDisplayName(c.Names[0])
+	}
+
+	ports := h.trackPorts(c, containerName)
+
+	// Get container stats; the response body is closed by readContainerStats on every path
+	statsData, err := readContainerStats(ctx, h.dockerClient, c.ID)
+	if err != nil {
+		log.Printf("Failed to get stats for container %s: %v", containerName, err)
+		// Log error to database
+		healthLog := &models.HealthCheckLog{
+			ContainerID:    c.ID,
+			ContainerName:  containerName,
+			Status:         "error",
+			ErrorMessage:   err.Error(),
+			CheckedAt:      time.Now(),
+			PublishedPorts: &ports,
+		}
+		h.storeHealthLog(healthLog)
+		return
+	}
+
+	// Calculate CPU percentage
+	cpuPercent := statsutil.CalculateCPUPercent(statsData)
+
+	// Calculate memory percentage; containers without a memory limit are never flagged
+	// for memory, since their usage could only be compared with the host's memory
+	hostMemory, err := h.dockerClient.HostMemory(ctx)
+	if err != nil {
+		log.Printf("Failed to get host memory: %v", err)
+	}
+	memoryPercent, memoryLimited := statsutil.MemoryPercent(statsData.MemoryStats.Usage, statsData.MemoryStats.Limit, hostMemory)
+
+	// Record which thresholds this reading breached, and their values at the time
+	var triggers []string
+	var cpuThreshold, memoryThreshold *float64
+	if cpuPercent > cfg.CPUThreshold {
+		triggers = append(triggers, "cpu")
+		cpuThreshold = &cfg.CPUThreshold
+	}
+	if memoryLimited && memoryPercent > cfg.MemoryThreshold {
+		triggers = append(triggers, "memory")
+		memoryThreshold = &cfg.MemoryThreshold
+	}
+	trigger := strings.Join(triggers, ",")
+
+	// Determine status, smoothing out short spikes
+	status := "healthy"
+	critical, changed := hysteresis.Observe(c.ID, trigger != "")
+	if critical {
+		status = "resource_critical"
+		log.Printf("Container %s is resource critical (CPU: %.2f%%, Memory: %.2f%%, trigger: %s)", containerName, cpuPercent, memoryPercent, trigger)
+	}
+	if changed {
+		h.logThresholdChange(c.ID, containerName, critical, trigger, cpuPercent, memoryPercent, memoryLimited, cfg)
+	}
+
+	// Store health check log
+	healthLog := &models.HealthCheckLog{
+		ContainerID:         c.ID,
+		ContainerName:       containerName,
+		Status:              status,
+		ResourceCPU:         cpuPercent,
+		ResourceMemory:      statsData.MemoryStats.Usage,
+		ResourceMemoryLimit: statsData.MemoryStats.Limit,
+		ResourceNetworkRx:   statsutil.GetNetworkRx(statsData),
+		ResourceNetworkTx:   statsutil.GetNetworkTx(statsData),
+		CheckedAt:           time.Now(),
+		PublishedPorts:      &ports,
+		Trigger:             trigger,
+		CPUThreshold:        cpuThreshold,
+		MemoryThreshold:     memoryThreshold,
+	}
+
+	h.storeHealthLog(healthLog)
+}
+
+// logThresholdChange records a container becoming resource critical, or recovering,
+// in the event log.
+func (h *HealthChecker) logThresholdChange(containerID, containerName string, critical bool, trigger string, cpuPercent, memoryPercent float64, memoryLimited bool, cfg config.HealthCheckConfig) {
+	data := map[string]interface{}{
+		"container_id":     containerID,
+		"container_name":   containerName,
+		"cpu_percent":      cpuPercent,
+		"cpu_threshold":    cfg.CPUThreshold,
+		"memory_threshold": cfg.MemoryThreshold,
+	}
+	if memoryLimited {
+		data["memory_percent"] = memoryPercent
+	}
+
+	if critical {
+		data["trigger"] = trigger
+		h.eventLogRepo.EmitEvent("health_check", "warning",
+			fmt.Sprintf("Container %s exceeded its resource thresholds (%s)", containerName, trigger), data)
+		return
+	}
+	h.eventLogRepo.EmitEvent("health_check", "info",
+		fmt.Sprintf("Container %s is back within its resource thresholds", containerName), data)
+}
+
+// storeHealthLog stores a health check result and publishes it to the metrics endpoint.
+func (h *HealthChecker) storeHealthLog(healthLog *models.HealthCheckLog) {
+	if err := h.repo.Create(healthLog); err != nil {
+		log.Printf("Failed to store health check log: %v", err)
+	}
+	metrics.RecordHealth(metrics.HealthReading{
+		ContainerID:   healthLog.ContainerID,
+		ContainerName: healthLog.ContainerName,
+		Status:        healthLog.Status,
+		CPUPercent:    healthLog.ResourceCPU,
+		MemoryUsage:   healthLog.ResourceMemory,
+		MemoryLimit:   healthLog.ResourceMemoryLimit,
+		NetworkRx:     healthLog.ResourceNetworkRx,
+		NetworkTx:     healthLog.ResourceNetworkTx,
+		CheckedAt:     healthLog.CheckedAt,
+	})
+}
+
+// trackPorts returns the container's published port set and reports a change against
+// the previously recorded set. After a restart the previous set is read from the database.
+func (h *HealthChecker) trackPorts(c types.Container, containerName string) string {
+	current := portSet(c.Ports)
+
+	previous, known := h.ports[c.ID]
+	if !known {
+		var err error
+		previous, known, err = h.repo.GetLatestPorts(c.ID)
+		if err != nil {
+			log.Printf("Failed to load recorded ports for container %s: %v", containerName, err)
+		}
+	}
+	h.ports[c.ID] = current
+
+	if !known || previous == current {
+		return current
+	}
+
+	added, removed := diffPortSets(previous, current)
+	log.Printf("Published ports of container %s changed from [%s] to [%s]", containerName, previous, current)
+
+	data := map[string]interface{}{
+		"container_id":   c.ID,
+		"container_name": containerName,
+		"previous":       previous,
+		"current":        current,
+		"added":          added,
+		"removed":        removed,
+	}
+	message := fmt.Sprintf("Published ports of container %s changed", containerName)
+
+	h.eventLogRepo.EmitEvent("port_change", "warning", message, data)
+
+	h.notifier.Notify("container_ports_changed", message, data)
+	return current
+}
+
+// portSet encodes published ports as a sorted, comma-separated "ip:public->private/proto" list.
+// Exposed but unpublished ports are ignored.
+func portSet(ports []types.Port) string {
+	seen := make(map[string]bool)
+	var entries []string
+	for _, p := range ports {
+		if p.PublicPort == 0 {
+			continue
+		}
+		entry := fmt.Sprintf("%s:%d->%d/%s", p.IP, p.PublicPort, p.PrivatePort, p.Type)
+		if !seen[entry] {
+			seen[entry] = true
+			entries = append(entries, entry)
+		}
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
+
+// diffPortSets returns the entries of current missing from previous and vice versa.
+func diffPortSets(previous, current string) (added, removed []string) {
+	split := func(set string) map[string]bool {
+		entries := make(map[string]bool)
+		for _, entry := range strings.Split(set, ",") {
+			if entry != "" {
+				entries[entry] = true
+			}
+		}
+		return entries
+	}
+	prev, curr := split(previous), split(current)
+
+	added, removed = []string{}, []string{}
+	for entry := range curr {
+		if !prev[entry] {
+			added = append(added, entry)
+		}
+	}
+	for entry := range prev {
+		if !curr[entry] {
+			removed = append(removed, entry)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+// PortHistory returns the distinct published port sets recorded for a container, most recent first.
+func (h *HealthChecker) PortHistory(containerID string, limit int) ([]*models.PortSetRecord, error) {
+	records, err := h.repo.GetPortHistory(containerID, limit)
+	if err != nil {
+		log.Printf("Failed to load port history for container %s: %v", containerID, err)
+		return nil, fmt.Errorf("failed to load port history: %w", err)
+	}
+	if records == nil {
+		records = []*models.PortSetRecord{}
+	}
+	return records, nil
+}
+
+// HealthHistory returns the health check results recorded for a container, most recent first.
+func (h *HealthChecker) HealthHistory(containerID string, limit int) ([]*models.HealthCheckLog, error) {
+	logs, err := h.repo.GetByContainerID(containerID, limit)
+	if err != nil {
+		log.Printf("Failed to load health history for container %s: %v", containerID, err)
+		return nil, fmt.Errorf("failed to load health history: %w", err)
+	}
+	if logs == nil {
+		logs = []*models.HealthCheckLog{}
+	}
+	return logs, nil
+}
+
+// StatsHistory returns the resource readings recorded for a container since the given
+// time, oldest first. When there are more than limit, the most recent ones are returned.
+func (h *HealthChecker) StatsHistory(containerID string, since time.Time, limit int) ([]*models.HealthCheckLog, error) {
+	samples, err := h.repo.GetContainerStatsHistory(containerID, since, limit)
+	if err != nil {
+		log.Printf("Failed to load stats history for container %s: %v", containerID, err)
+		return nil, fmt.Errorf("failed to load stats history: %w", err)
+	}
+	if samples == nil {
+		samples = []*models.HealthCheckLog{}
+	}
+	slices.Reverse(samples)
+	return samples, nil
+}
+
+// checkOOMKilled records a container that died because it was OOM-killed.
+// A distinct health check entry and an event log entry are stored and a webhook is fired.
+func (h *HealthChecker) checkOOMKilled(containerID string) {
+	containerJSON, err := h.dockerClient.ContainerInspect(h.ctx, containerID)
+	if err != nil {
+		log.Printf("Failed to inspect container %s after die event: %v", containerID, err)
+		return
+	}
+	if containerJSON.State == nil || !containerJSON.State.OOMKilled || !h.scope.Allows(containerJSON.Name) {
+		return
+	}
+
+	containerName := containerDisplayName(containerJSON.Name)
+	killedAt := parseTimeString(containerJSON.State.FinishedAt)
+	if killedAt.IsZero() {
+		killedAt = time.Now()
+	}
+	log.Printf("Container %s was OOM-killed (exit code: %d)", containerName, containerJSON.State.ExitCode)
+
+	var memoryLimit uint64
+	if containerJSON.HostConfig != nil && containerJSON.HostConfig.Memory > 0 {
+		memoryLimit = uint64(containerJSON.HostConfig.Memory)
+	}
+
+	healthLog := &models.HealthCheckLog{
+		ContainerID:         containerJSON.ID,
+		ContainerName:       containerName,
+		Status:              "oom_killed",
+		ResourceMemoryLimit: memoryLimit,
+		ErrorMessage:        fmt.Sprintf("container was killed by the OOM killer (exit code %d)", containerJSON.State.ExitCode),
+		CheckedAt:           killedAt,
+	}
+	h.storeHealthLog(healthLog)
+
+	data := map[string]interface{}{
+		"container_id":   containerJSON.ID,
+		"container_name": containerName,
+		"exit_code":      containerJSON.State.ExitCode,
+		"memory_limit":   memoryLimit,
+		"killed_at":      killedAt,
+	}
+	message := fmt.Sprintf("Container %s was OOM-killed", containerName)
+
+	h.eventLogRepo.EmitEvent("health_check", "error", message, data)
+
+	h.notifier.Notify("container_oom_killed", message, data)
+}
@@ -0,0 +1,80 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import "sync"
+
+// HealthHysteresis tracks consecutive threshold breaches per container so that
+// short spikes do not flip a container between healthy and critical on every check.
+// A container becomes critical after breachCount consecutive breaching readings and
+// is cleared after recoveryCount consecutive normal readings.
+type HealthHysteresis struct {
+	breachCount   int
+	recoveryCount int
+	states        map[string]*hysteresisState
+	mu            sync.Mutex
+}
+
+// hysteresisState holds the streak counters for a single container.
+type hysteresisState struct {
+	critical   bool
+	breaches   int
+	recoveries int
+}
+
+// NewHealthHysteresis creates a tracker with the given consecutive reading requirements.
+// Counts below 1 are treated as 1, which disables hysteresis for that direction.
+func NewHealthHysteresis(breachCount, recoveryCount int) *HealthHysteresis {
+	if breachCount < 1 {
+		breachCount = 1
+	}
+	if recoveryCount < 1 {
+		recoveryCount = 1
+	}
+	return &HealthHysteresis{
+		breachCount:   breachCount,
+		recoveryCount: recoveryCount,
+		states:        make(map[string]*hysteresisState),
+	}
+}
+
+// Observe records a reading for a container and returns whether it is currently
+// critical, and whether this reading changed that.
+func (h *HealthHysteresis) Observe(containerID string, breached bool) (critical, changed bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	state, ok := h.states[containerID]
+	if !ok {
+		state = &hysteresisState{}
+		h.states[containerID] = state
+	}
+	wasCritical := state.critical
+
+	if breached {
+		state.breaches++
+		state.recoveries = 0
+		if !state.critical && state.breaches >= h.breachCount {
+			state.critical = true
+		}
+	} else {
+		state.recoveries++
+		state.breaches = 0
+		if state.critical && state.recoveries >= h.recoveryCount {
+			state.critical = false
+		}
+	}
+
+	return state.critical, state.critical != wasCritical
+}
+
+// Retain drops tracking state for containers not in the given set of IDs.
+func (h *HealthHysteresis) Retain(containerIDs map[string]bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for id := range h.states {
+		if !containerIDs[id] {
+			delete(h.states, id)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import "strings"
+
+// ShortID returns the 12-character short form of a container or image ID, without a
+// "sha256:" prefix. Shorter input, such as a name passed where an ID was expected, is
+// returned unchanged.
+func ShortID(id string) string {
+	id = strings.TrimPrefix(id, "sha256:")
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
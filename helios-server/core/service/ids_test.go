@@ -0,0 +1,27 @@
+package service
+
+import "testing"
+
+func TestShortID(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		want string
+	}{
+		{name: "empty", id: "", want: ""},
+		{name: "shorter than 12", id: "abc123", want: "abc123"},
+		{name: "exactly 12", id: "0123456789ab", want: "0123456789ab"},
+		{name: "full ID", id: "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef", want: "0123456789ab"},
+		{name: "sha256 prefix", id: "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef", want: "0123456789ab"},
+		{name: "sha256 prefix only", id: "sha256:", want: ""},
+		{name: "container name", id: "web", want: "web"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ShortID(tt.id); got != tt.want {
+				t.Errorf("ShortID(%q) = %q, want %q", tt.id, got, tt.want)
+			}
+		})
+	}
+}
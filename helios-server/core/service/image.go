@@ -4,19 +4,22 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"nfcunha/helios/core/models"
 	"nfcunha/helios/core/repository"
+	"nfcunha/helios/utils/config"
 	"nfcunha/helios/utils/docker"
 
+	"github.com/distribution/reference"
 	"github.com/docker/docker/api/types/container"
-	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/registry"
 )
@@ -25,13 +28,23 @@ import (
 type ImageService struct {
 	dockerClient  *docker.Client
 	actionLogRepo *repository.ActionLogRepository
+	protection    *PruneProtection
+	scope         *ContainerScope
+	credentials   *RegistryCredentials
+	buildCfg      config.BuildConfig
 }
 
 // NewImageService creates a new image service.
-func NewImageService(dockerClient *docker.Client, actionLogRepo *repository.ActionLogRepository) *ImageService {
+// Pulls from registries with configured credentials are authenticated automatically,
+// including base image pulls during builds. Cascade removals only touch containers within the given scope.
+func NewImageService(dockerClient *docker.Client, actionLogRepo *repository.ActionLogRepository, protection *PruneProtection, scope *ContainerScope, credentials *RegistryCredentials, buildCfg config.BuildConfig) *ImageService {
 	return &ImageService{
 		dockerClient:  dockerClient,
 		actionLogRepo: actionLogRepo,
+		protection:    protection,
+		scope:         scope,
+		credentials:   credentials,
+		buildCfg:      buildCfg,
 	}
 }
 
@@ -91,6 +104,9 @@ type PullProgress struct {
 	ID          string                 `json:"id"`
 	Error       string                 `json:"error,omitempty"`
 	ErrorDetail map[string]interface{} `json:"errorDetail,omitempty"`
+	Retry       *PullRetry             `json:"retry,omitempty"`  // Set on the status update announcing a retry
+	Stream      string                 `json:"stream,omitempty"` // Build output
+	Aux         *BuildAux              `json:"aux,omitempty"`    // Set by builds on the message carrying the built image ID
 }
 
 // GetImages retrieves all Docker images.
@@ -179,11 +195,13 @@ func (s *ImageService) InspectImage(ctx context.Context, imageID string) (*Image
 }
 
 // PullImage pulls an image from a registry.
-// Returns a channel that provides progress updates.
+// Returns a channel that provides progress updates. Pulls interrupted by transient
+// errors are retried with backoff, reusing the layers Docker already downloaded;
+// each retry is announced on the progress channel with Retry set.
 func (s *ImageService) PullImage(ctx context.Context, imageName string) (<-chan PullProgress, <-chan error, error) {
 	// Start pull
-	reader, err := s.dockerClient.ImagePull(ctx, imageName, image.PullOptions{})
-	if err != nil {
+	reader, err := s.startPull(ctx, imageName)
+	if err != nil && !isRetryablePullError(err) {
 		log.Printf("Failed to start pull for image %s: %v", imageName, err)
 		s.logAction("pull", "image", imageName, imageName, false, err)
 		return nil, nil, fmt.Errorf("failed to pull image: %w", err)
@@ -199,71 +217,54 @@ func (s *ImageService) PullImage(ctx context.Context, imageName string) (<-chan
 	go func() {
 		defer close(progressChan)
 		defer close(errChan)
-		defer reader.Close()
-
-		decoder := json.NewDecoder(reader)
-		hasError := false
-		for {
-			var progress PullProgress
-			if err := decoder.Decode(&progress); err != nil {
-				if err == io.EOF {
-					// Pull completed successfully (only if no errors occurred)
-					if !hasError {
-						s.logAction("pull", "image", imageName, imageName, true, nil)
-						log.Printf("Successfully pulled image: %s", imageName)
-					}
+
+		for attempt := 1; ; attempt++ {
+			if err == nil {
+				var failed *PullProgress
+				failed, err = decodePull(ctx, reader, progressChan)
+				reader.Close()
+				if err == nil {
+					s.logAction("pull", "image", imageName, imageName, true, nil)
+					log.Printf("Successfully pulled image: %s", imageName)
 					return
 				}
-				// If we already sent an error, don't send decode errors
-				if !hasError {
-					errChan <- fmt.Errorf("failed to decode progress: %w", err)
-					s.logAction("pull", "image", imageName, imageName, false, err)
-				}
-				return
-			}
 
-			// Check for errors in progress
-			if progress.Error != "" || len(progress.ErrorDetail) > 0 {
-				hasError = true
-				errMsg := progress.Error
-				if len(progress.ErrorDetail) > 0 {
-					if detailMsg, ok := progress.ErrorDetail["message"].(string); ok {
-						errMsg += ": " + detailMsg
-					} else {
-						// Fallback: convert the whole map to string
-						errMsg += fmt.Sprintf(": %v", progress.ErrorDetail)
+				// Only the final failure is forwarded; clients treat an error event as fatal
+				if failed != nil && (attempt >= pullMaxAttempts || !isRetryablePullError(err)) {
+					select {
+					case progressChan <- *failed:
+					case <-ctx.Done():
 					}
 				}
-				err := fmt.Errorf("%s", errMsg)
-				// Send the error progress to frontend before sending to errChan
-				select {
-				case progressChan <- progress:
-				case <-ctx.Done():
-				}
-				errChan <- err
-				s.logAction("pull", "image", imageName, imageName, false, err)
-				log.Printf("Failed to pull image %s: %v", imageName, err)
-				return
 			}
 
-			if strings.Contains(strings.ToLower(progress.Status), "error") {
-				hasError = true
-				err := fmt.Errorf("pull error: %s", progress.Status)
-				// Send the error progress to frontend before sending to errChan
-				select {
-				case progressChan <- progress:
-				case <-ctx.Done():
+			if attempt >= pullMaxAttempts || !isRetryablePullError(err) || ctx.Err() != nil {
+				if ctx.Err() != nil {
+					err = ctx.Err()
 				}
 				errChan <- err
 				s.logAction("pull", "image", imageName, imageName, false, err)
+				log.Printf("Failed to pull image %s after %d attempt(s): %v", imageName, attempt, err)
 				return
 			}
 
+			delay := pullRetryDelay(attempt)
+			log.Printf("Pull of image %s interrupted (attempt %d/%d), retrying in %v: %v", imageName, attempt, pullMaxAttempts, delay, err)
+			retry := PullProgress{
+				Status: fmt.Sprintf("Retrying pull in %v (attempt %d/%d): %v", delay, attempt+1, pullMaxAttempts, err),
+				Retry:  &PullRetry{Attempt: attempt + 1, MaxAttempts: pullMaxAttempts, DelayMs: delay.Milliseconds(), Reason: err.Error()},
+			}
 			select {
-			case progressChan <- progress:
+			case progressChan <- retry:
+			case <-ctx.Done():
+			}
+
+			select {
+			case <-time.After(delay):
 			case <-ctx.Done():
-				errChan <- ctx.Err()
-				return
+			}
+			if ctx.Err() == nil {
+				reader, err = s.startPull(ctx, imageName)
 			}
 		}
 	}()
@@ -271,6 +272,13 @@ func (s *ImageService) PullImage(ctx context.Context, imageName string) (<-chan
 	return progressChan, errChan, nil
 }
 
+// startPull asks the daemon to pull an image, authenticating with the stored registry credentials.
+func (s *ImageService) startPull(ctx context.Context, imageName string) (io.ReadCloser, error) {
+	return s.dockerClient.ImagePull(ctx, imageName, image.PullOptions{
+		RegistryAuth: s.credentials.RegistryAuth(imageName),
+	})
+}
+
 // RemoveImage removes an image by ID or name.
 func (s *ImageService) RemoveImage(ctx context.Context, imageID string, force bool) error {
 	opts := image.RemoveOptions{
@@ -299,7 +307,8 @@ func (s *ImageService) RemoveImage(ctx context.Context, imageID string, force bo
 }
 
 // BulkRemoveImages removes multiple images by their IDs.
-func (s *ImageService) BulkRemoveImages(ctx context.Context, imageIDs []string, force bool) []BulkOperationResult {
+// If progress is non-nil, each result is also sent to it as soon as it completes.
+func (s *ImageService) BulkRemoveImages(ctx context.Context, imageIDs []string, force bool, progress chan<- BulkOperationResult) []BulkOperationResult {
 	results := make([]BulkOperationResult, 0, len(imageIDs))
 
 	for _, imageID := range imageIDs {
@@ -324,26 +333,157 @@ func (s *ImageService) BulkRemoveImages(ctx context.Context, imageIDs []string,
 		}
 
 		results = append(results, result)
+		reportBulkResult(progress, result)
+	}
+
+	return results
+}
+
+// TagPair represents a single source/target pair for a tag operation.
+type TagPair struct {
+	Source string `json:"source" binding:"required"`
+	Target string `json:"target" binding:"required"`
+}
+
+// ErrInvalidImageReference is returned when a tag to add or remove is not a valid image reference.
+var ErrInvalidImageReference = errors.New("invalid image reference")
+
+// TagImage tags an existing image with a new reference.
+// An invalid target matches ErrInvalidImageReference.
+func (s *ImageService) TagImage(ctx context.Context, source, target string) error {
+	if _, err := reference.ParseNormalizedNamed(target); err != nil {
+		err = fmt.Errorf("%w: target %q: %w", ErrInvalidImageReference, target, err)
+		return s.logAction("tag", "image", source, target, false, err)
+	}
+
+	if err := s.dockerClient.ImageTag(ctx, source, target); err != nil {
+		log.Printf("Failed to tag image %s as %s: %v", source, target, err)
+		s.logAction("tag", "image", source, target, false, err)
+		return fmt.Errorf("failed to tag image: %w", err)
+	}
+
+	log.Printf("Successfully tagged image %s as %s", source, target)
+	s.logAction("tag", "image", source, target, true, nil)
+	return nil
+}
+
+// BulkTagImages tags multiple images in parallel.
+// If progress is non-nil, each result is also sent to it as soon as it completes.
+func (s *ImageService) BulkTagImages(ctx context.Context, pairs []TagPair, progress chan<- BulkOperationResult) []BulkOperationResult {
+	results := make([]BulkOperationResult, len(pairs))
+
+	var wg sync.WaitGroup
+	for i, pair := range pairs {
+		wg.Add(1)
+		go func(i int, pair TagPair) {
+			defer wg.Done()
+
+			result := BulkOperationResult{
+				ContainerID:   pair.Source,
+				ContainerName: pair.Target,
+				Success:       true,
+			}
+
+			if err := s.TagImage(ctx, pair.Source, pair.Target); err != nil {
+				result.Success = false
+				result.Error = err.Error()
+			}
+
+			results[i] = result
+			reportBulkResult(progress, result)
+		}(i, pair)
 	}
+	wg.Wait()
+
+	return results
+}
+
+// UntagImage removes a single tag from an image without deleting shared layers.
+// An invalid tag matches ErrInvalidImageReference.
+func (s *ImageService) UntagImage(ctx context.Context, tag string) error {
+	if _, err := reference.ParseNormalizedNamed(tag); err != nil {
+		err = fmt.Errorf("%w: tag %q: %w", ErrInvalidImageReference, tag, err)
+		return s.logAction("untag", "image", tag, tag, false, err)
+	}
+
+	_, err := s.dockerClient.ImageRemove(ctx, tag, image.RemoveOptions{
+		Force:         false,
+		PruneChildren: false,
+	})
+	if err != nil {
+		log.Printf("Failed to untag image %s: %v", tag, err)
+		s.logAction("untag", "image", tag, tag, false, err)
+		return fmt.Errorf("failed to untag image: %w", err)
+	}
+
+	log.Printf("Successfully untagged image: %s", tag)
+	s.logAction("untag", "image", tag, tag, true, nil)
+	return nil
+}
+
+// BulkUntagImages removes multiple tags in parallel.
+// If progress is non-nil, each result is also sent to it as soon as it completes.
+func (s *ImageService) BulkUntagImages(ctx context.Context, tags []string, progress chan<- BulkOperationResult) []BulkOperationResult {
+	results := make([]BulkOperationResult, len(tags))
+
+	var wg sync.WaitGroup
+	for i, tag := range tags {
+		wg.Add(1)
+		go func(i int, tag string) {
+			defer wg.Done()
+
+			result := BulkOperationResult{
+				ContainerID:   tag,
+				ContainerName: tag,
+				Success:       true,
+			}
+
+			if err := s.UntagImage(ctx, tag); err != nil {
+				result.Success = false
+				result.Error = err.Error()
+			}
+
+			results[i] = result
+			reportBulkResult(progress, result)
+		}(i, tag)
+	}
+	wg.Wait()
 
 	return results
 }
 
 // PruneImages removes unused images and their associated stopped containers.
-func (s *ImageService) PruneImages(ctx context.Context, all bool) (uint64, error) {
+// Images covered by the prune allowlist or labelled with ProtectLabel or KeepLabel are kept,
+// along with stopped containers that use them, and are returned as skipped.
+func (s *ImageService) PruneImages(ctx context.Context, all bool) (uint64, []SkippedResource, error) {
+	skipped := []SkippedResource{}
+
 	if all {
 		// Get all images
 		images, err := s.dockerClient.ImageList(ctx, image.ListOptions{})
 		if err != nil {
 			log.Printf("Failed to list images for pruning: %v", err)
-			return 0, fmt.Errorf("failed to list images: %w", err)
+			return 0, nil, fmt.Errorf("failed to list images: %w", err)
 		}
 
 		// Get all containers (including stopped)
 		containers, err := s.dockerClient.ContainerList(ctx, container.ListOptions{All: true})
 		if err != nil {
 			log.Printf("Failed to list containers for pruning: %v", err)
-			return 0, fmt.Errorf("failed to list containers: %w", err)
+			return 0, nil, fmt.Errorf("failed to list containers: %w", err)
+		}
+
+		// Protected images are treated like images in use
+		protected := make(map[string]bool)
+		for _, img := range images {
+			if reason := s.protection.ImageReason(img.ID, img.RepoTags, img.Labels); reason != "" {
+				protected[img.ID] = true
+				name := ""
+				if len(img.RepoTags) > 0 {
+					name = img.RepoTags[0]
+				}
+				skipped = append(skipped, SkippedResource{ID: img.ID, Name: name, Reason: reason})
+			}
 		}
 
 		// Build a map of images that are being used by running containers
@@ -357,13 +497,13 @@ func (s *ImageService) PruneImages(ctx context.Context, all bool) (uint64, error
 		// Remove stopped containers for images not used by running containers
 		removedContainers := 0
 		for _, c := range containers {
-			if c.State != "running" && !usedByRunning[c.ImageID] {
+			if c.State != "running" && !usedByRunning[c.ImageID] && !protected[c.ImageID] && !hasProtectLabel(c.Labels) {
 				// Remove this stopped container
 				if err := s.dockerClient.ContainerRemove(ctx, c.ID, container.RemoveOptions{Force: true}); err != nil {
 					log.Printf("Failed to remove stopped container %s: %v", c.ID, err)
 				} else {
 					removedContainers++
-					log.Printf("Removed stopped container %s using image %s", c.ID[:12], c.ImageID[:12])
+					log.Printf("Removed stopped container %s using image %s", ShortID(c.ID), ShortID(c.ImageID))
 				}
 			}
 		}
@@ -375,46 +515,44 @@ func (s *ImageService) PruneImages(ctx context.Context, all bool) (uint64, error
 		var totalReclaimed uint64 = 0
 
 		for _, img := range images {
-			// Skip images used by running containers
-			if usedByRunning[img.ID] {
+			// Skip images used by running containers or protected from pruning
+			if usedByRunning[img.ID] || protected[img.ID] {
 				continue
 			}
 
 			// Try to remove the image
 			deleteResponse, err := s.dockerClient.ImageRemove(ctx, img.ID, image.RemoveOptions{Force: false, PruneChildren: true})
 			if err != nil {
-				log.Printf("Failed to remove image %s: %v", img.ID[:12], err)
+				log.Printf("Failed to remove image %s: %v", ShortID(img.ID), err)
 			} else {
 				for _, item := range deleteResponse {
 					if item.Deleted != "" {
 						removedImages++
 						totalReclaimed += uint64(img.Size)
-						log.Printf("Removed unused image: %s", img.ID[:12])
+						log.Printf("Removed unused image: %s", ShortID(img.ID))
 						break
 					}
 				}
 			}
 		}
 
-		log.Printf("Pruned %d images, reclaimed space: %d bytes", removedImages, totalReclaimed)
+		log.Printf("Pruned %d images (%d protected), reclaimed space: %d bytes", removedImages, len(skipped), totalReclaimed)
 		s.logAction("prune", "image", "all", "all", true, nil)
-		return totalReclaimed, nil
+		return totalReclaimed, skipped, nil
 	}
 
-	// For non-"all" mode, just remove dangling images
-	pruneFilters := filters.NewArgs()
-	pruneFilters.Add("dangling", "true")
-
-	report, err := s.dockerClient.ImagesPrune(ctx, pruneFilters)
+	// For non-"all" mode, just remove dangling images. Dangling images have no tags,
+	// so only the protect labels can apply.
+	deleted, reclaimed, skipped, err := pruneDanglingImages(ctx, s.dockerClient)
 	if err != nil {
 		log.Printf("Failed to prune images: %v", err)
 		s.logAction("prune", "image", "all", "all", false, err)
-		return 0, fmt.Errorf("failed to prune images: %w", err)
+		return 0, nil, fmt.Errorf("failed to prune images: %w", err)
 	}
 
-	log.Printf("Pruned images, reclaimed space: %d bytes", report.SpaceReclaimed)
+	log.Printf("Pruned %d images (%d protected), reclaimed space: %d bytes", len(deleted), len(skipped), reclaimed)
 	s.logAction("prune", "image", "all", "all", true, nil)
-	return report.SpaceReclaimed, nil
+	return reclaimed, skipped, nil
 }
 
 // SearchImages searches for images in a registry.
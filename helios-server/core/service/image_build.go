@@ -0,0 +1,145 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+)
+
+// ErrBuildContextTooLarge is returned while reading a build context that exceeds the configured limit.
+var ErrBuildContextTooLarge = errors.New("build context exceeds maximum size")
+
+// buildContextReader enforces a maximum size on a streamed build context.
+type buildContextReader struct {
+	reader    io.Reader
+	remaining int64
+	maxBytes  int64
+	exceeded  bool // Set once the limit is exceeded
+}
+
+// LimitBuildContext wraps a build context stream so that it can be passed straight to
+// ImageBuild without buffering it in memory. Once more than maxBytes have been read the
+// reader fails with ErrBuildContextTooLarge, which aborts the build request mid-upload.
+// A maxBytes of 0 or less disables the limit.
+func LimitBuildContext(reader io.Reader, maxBytes int64) io.Reader {
+	if maxBytes <= 0 {
+		return reader
+	}
+	return &buildContextReader{
+		reader:    reader,
+		remaining: maxBytes,
+		maxBytes:  maxBytes,
+	}
+}
+
+func (r *buildContextReader) Read(p []byte) (int, error) {
+	if r.remaining <= 0 {
+		// Probe for one more byte to tell an exact-size context from an oversized one
+		var probe [1]byte
+		n, err := r.reader.Read(probe[:])
+		if n > 0 {
+			r.exceeded = true
+			return 0, fmt.Errorf("%w (%d bytes)", ErrBuildContextTooLarge, r.maxBytes)
+		}
+		return 0, err
+	}
+
+	if int64(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+	n, err := r.reader.Read(p)
+	r.remaining -= int64(n)
+	return n, err
+}
+
+// BuildOptions configures an image build.
+type BuildOptions struct {
+	Tags       []string          // Image references to tag the result with
+	BuildArgs  map[string]string // Values for ARG instructions
+	Target     string            // Build stage to stop at; empty builds the final stage
+	Dockerfile string            // Path of the Dockerfile within the context (default "Dockerfile")
+}
+
+// BuildAux carries the ID of the built image, sent by Docker at the end of a build.
+type BuildAux struct {
+	ID string `json:"ID"`
+}
+
+// BuildImage builds an image from a tar build context, streamed to the daemon without
+// buffering and limited to the configured maximum context size. Build output is sent
+// on the progress channel as it is produced; a failing step is forwarded as a final
+// progress message carrying the error before the error channel reports it.
+func (s *ImageService) BuildImage(ctx context.Context, tarContext io.Reader, opts BuildOptions) (<-chan PullProgress, <-chan error, error) {
+	name := buildName(opts.Tags)
+
+	buildArgs := make(map[string]*string, len(opts.BuildArgs))
+	for key, value := range opts.BuildArgs {
+		buildArgs[key] = &value
+	}
+
+	buildContext := LimitBuildContext(tarContext, s.buildCfg.MaxContextSize)
+	resp, err := s.dockerClient.ImageBuild(ctx, buildContext, types.ImageBuildOptions{
+		Tags:        opts.Tags,
+		BuildArgs:   buildArgs,
+		Target:      opts.Target,
+		Dockerfile:  opts.Dockerfile,
+		Remove:      true,
+		AuthConfigs: s.credentials.AuthConfigs(),
+	})
+	if err != nil {
+		// The Docker client reports a failed upload as a connection error; surface the limit instead
+		if limited, ok := buildContext.(*buildContextReader); ok && limited.exceeded {
+			err = fmt.Errorf("%w (%d bytes)", ErrBuildContextTooLarge, limited.maxBytes)
+		}
+		log.Printf("Failed to start build of image %s: %v", name, err)
+		s.logAction("build", "image", name, name, false, err)
+		return nil, nil, fmt.Errorf("failed to build image: %w", err)
+	}
+
+	log.Printf("Started building image %s, streaming output...", name)
+
+	progressChan := make(chan PullProgress, 10)
+	errChan := make(chan error, 1)
+
+	go func() {
+		defer close(progressChan)
+		defer close(errChan)
+		defer resp.Body.Close()
+
+		failed, err := decodePull(ctx, resp.Body, progressChan)
+		if err == nil && ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		if err != nil {
+			if failed != nil {
+				select {
+				case progressChan <- *failed:
+				case <-ctx.Done():
+				}
+			}
+			errChan <- err
+			s.logAction("build", "image", name, name, false, err)
+			log.Printf("Failed to build image %s: %v", name, err)
+			return
+		}
+
+		s.logAction("build", "image", name, name, true, nil)
+		log.Printf("Successfully built image: %s", name)
+	}()
+
+	return progressChan, errChan, nil
+}
+
+// buildName describes a build by its tags for logs and the action log.
+func buildName(tags []string) string {
+	if len(tags) == 0 {
+		return "<untagged>"
+	}
+	return strings.Join(tags, ", ")
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+// zeroReader is an endless stream of zero bytes, standing in for a large build context.
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+func TestLimitBuildContext(t *testing.T) {
+	const limit = 64 << 20
+
+	tests := []struct {
+		name     string
+		size     int64
+		maxBytes int64
+		wantErr  bool
+	}{
+		{name: "one byte over the limit", size: limit + 1, maxBytes: limit, wantErr: true},
+		{name: "far over the limit", size: 2 * limit, maxBytes: limit, wantErr: true},
+		{name: "exactly at the limit", size: limit, maxBytes: limit},
+		{name: "under the limit", size: limit - 1, maxBytes: limit},
+		{name: "empty context", size: 0, maxBytes: limit},
+		{name: "limit disabled", size: limit + 1, maxBytes: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := LimitBuildContext(io.LimitReader(zeroReader{}, tt.size), tt.maxBytes)
+			n, err := io.Copy(io.Discard, reader)
+
+			if tt.wantErr {
+				if !errors.Is(err, ErrBuildContextTooLarge) {
+					t.Fatalf("read %d bytes, error = %v, want ErrBuildContextTooLarge", n, err)
+				}
+				if n != tt.maxBytes {
+					t.Errorf("read %d bytes before failing, want %d", n, tt.maxBytes)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if n != tt.size {
+				t.Errorf("read %d bytes, want the whole context of %d", n, tt.size)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+)
+
+// ErrCascadeIncomplete is returned when a dependent container could not be removed,
+// in which case the image is left in place.
+var ErrCascadeIncomplete = errors.New("not all dependent containers could be removed")
+
+// ErrCascadeOutOfScope is returned when containers outside the container scope use
+// the image, in which case nothing is removed.
+var ErrCascadeOutOfScope = errors.New("containers outside the container scope use this image")
+
+// ImageDependent is a container created from an image.
+type ImageDependent struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	State string `json:"state"`
+}
+
+// CascadeRemoveReport lists everything removed along with an image.
+type CascadeRemoveReport struct {
+	ImageID           string           `json:"image_id"`
+	ImageName         string           `json:"image_name"`
+	Dependents        []ImageDependent `json:"dependents"`
+	OutOfScope        int              `json:"out_of_scope,omitempty"` // Dependents outside the container scope, which block the removal
+	ContainersStopped []string         `json:"containers_stopped"`
+	ContainersRemoved []string         `json:"containers_removed"`
+	ImagesUntagged    []string         `json:"images_untagged"`
+	ImagesDeleted     []string         `json:"images_deleted"`
+	Errors            []string         `json:"errors,omitempty"`
+}
+
+// ImageDependents returns the image's resolved ID and name and every container in scope,
+// running or stopped, created from it, along with the number of dependents outside the
+// scope. Those are never listed, since their names belong to other tenants.
+func (s *ImageService) ImageDependents(ctx context.Context, imageID string) (string, string, []ImageDependent, int, error) {
+	inspect, _, err := s.dockerClient.ImageInspectWithRaw(ctx, imageID)
+	if err != nil {
+		log.Printf("Failed to inspect image %s: %v", imageID, err)
+		return "", "", nil, 0, fmt.Errorf("failed to inspect image: %w", err)
+	}
+	name := inspect.ID
+	if len(inspect.RepoTags) > 0 {
+		name = inspect.RepoTags[0]
+	}
+
+	containers, err := s.dockerClient.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		log.Printf("Failed to list containers using image %s: %v", name, err)
+		return "", "", nil, 0, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	var users []types.Container
+	for _, c := range containers {
+		if c.ImageID == inspect.ID {
+			users = append(users, c)
+		}
+	}
+	inScope := s.scope.Filter(users)
+
+	dependents := []ImageDependent{}
+	for _, c := range inScope {
+		dependent := ImageDependent{ID: c.ID, State: c.State}
+		if len(c.Names) > 0 {
+			dependent.Name = containerDisplayName(c.Names[0])
+		}
+		dependents = append(dependents, dependent)
+	}
+
+	return inspect.ID, name, dependents, len(users) - len(inScope), nil
+}
+
+// RemoveImageCascade stops and removes every container created from an image and
+// then removes the image. If any container cannot be removed the image is kept and
+// ErrCascadeIncomplete is returned along with the partial report. If containers outside
+// the container scope use the image, nothing is stopped or removed and
+// ErrCascadeOutOfScope is returned along with the report.
+func (s *ImageService) RemoveImageCascade(ctx context.Context, imageID string, force bool) (*CascadeRemoveReport, error) {
+	resolvedID, name, dependents, outOfScope, err := s.ImageDependents(ctx, imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &CascadeRemoveReport{
+		ImageID:           resolvedID,
+		ImageName:         name,
+		Dependents:        dependents,
+		ContainersStopped: []string{},
+		ContainersRemoved: []string{},
+		ImagesUntagged:    []string{},
+		ImagesDeleted:     []string{},
+		OutOfScope:        outOfScope,
+	}
+
+	if outOfScope > 0 {
+		err := fmt.Errorf("%w: %d container(s)", ErrCascadeOutOfScope, outOfScope)
+		log.Printf("Refusing cascade removal of image %s: %v", name, err)
+		return report, s.logAction("remove", "image", resolvedID, name, false, err)
+	}
+
+	for _, dependent := range dependents {
+		if dependent.State == "running" || dependent.State == "restarting" || dependent.State == "paused" {
+			timeout := 10
+			if err := s.dockerClient.ContainerStop(ctx, dependent.ID, container.StopOptions{Timeout: &timeout}); err != nil {
+				log.Printf("Failed to stop container %s using image %s: %v", dependent.Name, name, err)
+				s.logAction("stop", "container", dependent.ID, dependent.Name, false, err)
+				report.Errors = append(report.Errors, fmt.Sprintf("stop %s: %v", dependent.Name, err))
+				continue
+			}
+			s.logAction("stop", "container", dependent.ID, dependent.Name, true, nil)
+			report.ContainersStopped = append(report.ContainersStopped, dependent.Name)
+		}
+
+		if err := s.dockerClient.ContainerRemove(ctx, dependent.ID, container.RemoveOptions{}); err != nil {
+			log.Printf("Failed to remove container %s using image %s: %v", dependent.Name, name, err)
+			s.logAction("remove", "container", dependent.ID, dependent.Name, false, err)
+			report.Errors = append(report.Errors, fmt.Sprintf("remove %s: %v", dependent.Name, err))
+			continue
+		}
+		s.logAction("remove", "container", dependent.ID, dependent.Name, true, nil)
+		report.ContainersRemoved = append(report.ContainersRemoved, dependent.Name)
+	}
+
+	if len(report.Errors) > 0 {
+		log.Printf("Keeping image %s: %d dependent containers could not be removed", name, len(report.Errors))
+		return report, ErrCascadeIncomplete
+	}
+
+	deleted, err := s.dockerClient.ImageRemove(ctx, resolvedID, image.RemoveOptions{Force: force, PruneChildren: true})
+	if err != nil {
+		log.Printf("Failed to remove image %s: %v", name, err)
+		s.logAction("remove", "image", resolvedID, name, false, err)
+		report.Errors = append(report.Errors, fmt.Sprintf("remove image: %v", err))
+		return report, fmt.Errorf("failed to remove image: %w", err)
+	}
+	for _, item := range deleted {
+		if item.Untagged != "" {
+			report.ImagesUntagged = append(report.ImagesUntagged, item.Untagged)
+		}
+		if item.Deleted != "" {
+			report.ImagesDeleted = append(report.ImagesDeleted, item.Deleted)
+		}
+	}
+
+	log.Printf("Removed image %s and %d dependent containers", name, len(report.ContainersRemoved))
+	s.logAction("remove", "image", resolvedID, name, true, nil)
+	return report, nil
+}
@@ -0,0 +1,198 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ImageDiff compares two images, describing what changes when moving from A to B.
+type ImageDiff struct {
+	A         ImageDiffSide    `json:"a"`
+	B         ImageDiffSide    `json:"b"`
+	Identical bool             `json:"identical"`  // Same image ID
+	SizeDelta int64            `json:"size_delta"` // B's size minus A's, in bytes
+	Layers    LayerDiff        `json:"layers"`
+	Env       MapDiff          `json:"env"`
+	Labels    MapDiff          `json:"labels"`
+	Ports     ListDiff         `json:"exposed_ports"`
+	Volumes   ListDiff         `json:"volumes"`
+	Config    []ImageFieldDiff `json:"config"` // Scalar and command settings that differ
+}
+
+// ImageDiffSide identifies one of the compared images.
+type ImageDiffSide struct {
+	Ref     string `json:"ref"`
+	ID      string `json:"id"`
+	Created string `json:"created"`
+	Size    int64  `json:"size"`
+}
+
+// LayerDiff compares the layer stacks of two images position by position.
+// Layers below the first difference are shared; a differing layer present in both
+// stacks is changed, and layers beyond the end of the other stack are added or removed.
+type LayerDiff struct {
+	Shared  int           `json:"shared"`
+	Changed []LayerChange `json:"changed"`
+	Added   []string      `json:"added"`
+	Removed []string      `json:"removed"`
+}
+
+// LayerChange is a layer position whose content differs between the images.
+type LayerChange struct {
+	Index int    `json:"index"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// MapDiff compares two key/value sets such as environment variables or labels.
+type MapDiff struct {
+	Added   map[string]string      `json:"added"`
+	Removed map[string]string      `json:"removed"`
+	Changed map[string]ValueChange `json:"changed"`
+}
+
+// ValueChange is a value that differs between the images.
+type ValueChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ListDiff compares two unordered sets of strings.
+type ListDiff struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+// ImageFieldDiff is a configuration field that differs between the images.
+type ImageFieldDiff struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// DiffImages inspects two images and describes how B differs from A: size, layers,
+// environment, labels, exposed ports, volumes and the remaining runtime configuration.
+func (s *ImageService) DiffImages(ctx context.Context, refA, refB string) (*ImageDiff, error) {
+	a, err := s.InspectImage(ctx, refA)
+	if err != nil {
+		return nil, fmt.Errorf("image %s: %w", refA, err)
+	}
+	b, err := s.InspectImage(ctx, refB)
+	if err != nil {
+		return nil, fmt.Errorf("image %s: %w", refB, err)
+	}
+
+	diff := &ImageDiff{
+		A:         ImageDiffSide{Ref: refA, ID: a.ID, Created: a.Created, Size: a.Size},
+		B:         ImageDiffSide{Ref: refB, ID: b.ID, Created: b.Created, Size: b.Size},
+		Identical: a.ID == b.ID,
+		SizeDelta: b.Size - a.Size,
+		Layers:    diffLayers(rootFSLayers(a), rootFSLayers(b)),
+		Env:       diffMaps(envMap(a.Env), envMap(b.Env)),
+		Labels:    diffMaps(a.Labels, b.Labels),
+		Ports:     diffLists(a.ExposedPorts, b.ExposedPorts),
+		Volumes:   diffLists(a.Volumes, b.Volumes),
+		Config:    []ImageFieldDiff{},
+	}
+
+	fields := []struct {
+		name     string
+		from, to string
+	}{
+		{"cmd", strings.Join(a.Cmd, " "), strings.Join(b.Cmd, " ")},
+		{"entrypoint", strings.Join(a.Entrypoint, " "), strings.Join(b.Entrypoint, " ")},
+		{"working_dir", a.WorkingDir, b.WorkingDir},
+		{"user", a.User, b.User},
+		{"architecture", a.Architecture, b.Architecture},
+		{"os", a.Os, b.Os},
+	}
+	for _, f := range fields {
+		if f.from != f.to {
+			diff.Config = append(diff.Config, ImageFieldDiff{Field: f.name, From: f.from, To: f.to})
+		}
+	}
+
+	return diff, nil
+}
+
+// rootFSLayers returns an image's layer digests, bottom first.
+func rootFSLayers(detail *ImageDetail) []string {
+	if detail.RootFS == nil {
+		return nil
+	}
+	return detail.RootFS.Layers
+}
+
+// diffLayers compares two layer stacks position by position.
+func diffLayers(a, b []string) LayerDiff {
+	diff := LayerDiff{Changed: []LayerChange{}, Added: []string{}, Removed: []string{}}
+
+	for diff.Shared < len(a) && diff.Shared < len(b) && a[diff.Shared] == b[diff.Shared] {
+		diff.Shared++
+	}
+	for i := diff.Shared; i < len(a) || i < len(b); i++ {
+		switch {
+		case i >= len(a):
+			diff.Added = append(diff.Added, b[i])
+		case i >= len(b):
+			diff.Removed = append(diff.Removed, a[i])
+		case a[i] != b[i]:
+			diff.Changed = append(diff.Changed, LayerChange{Index: i, From: a[i], To: b[i]})
+		}
+	}
+	return diff
+}
+
+// diffMaps compares two key/value sets.
+func diffMaps(a, b map[string]string) MapDiff {
+	diff := MapDiff{
+		Added:   map[string]string{},
+		Removed: map[string]string{},
+		Changed: map[string]ValueChange{},
+	}
+	for key, from := range a {
+		to, ok := b[key]
+		switch {
+		case !ok:
+			diff.Removed[key] = from
+		case from != to:
+			diff.Changed[key] = ValueChange{From: from, To: to}
+		}
+	}
+	for key, to := range b {
+		if _, ok := a[key]; !ok {
+			diff.Added[key] = to
+		}
+	}
+	return diff
+}
+
+// diffLists compares two sets of strings, returning sorted additions and removals.
+func diffLists(a, b []string) ListDiff {
+	inA := make(map[string]bool, len(a))
+	for _, item := range a {
+		inA[item] = true
+	}
+	inB := make(map[string]bool, len(b))
+	for _, item := range b {
+		inB[item] = true
+	}
+
+	diff := ListDiff{Added: []string{}, Removed: []string{}}
+	for item := range inB {
+		if !inA[item] {
+			diff.Added = append(diff.Added, item)
+		}
+	}
+	for item := range inA {
+		if !inB[item] {
+			diff.Removed = append(diff.Removed, item)
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	return diff
+}
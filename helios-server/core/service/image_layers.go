@@ -0,0 +1,190 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+
+	"github.com/docker/docker/api/types/image"
+)
+
+// layerInspectConcurrency bounds the number of concurrent image inspections during layer analysis.
+const layerInspectConcurrency = 8
+
+// LayerInfo describes a single image layer and the images that use it.
+type LayerInfo struct {
+	DiffID string   `json:"diff_id"`
+	Size   int64    `json:"size"`
+	Images []string `json:"images"` // IDs of images containing the layer
+	Shared bool     `json:"shared"`
+}
+
+// ImageLayerUsage summarizes how much of an image is stored in layers no other image uses.
+type ImageLayerUsage struct {
+	ID            string   `json:"id"`
+	RepoTags      []string `json:"repo_tags"`
+	Layers        int      `json:"layers"`
+	Size          int64    `json:"size"`
+	ExclusiveSize int64    `json:"exclusive_size"` // Roughly what removing only this image reclaims
+}
+
+// ImageLayerReport describes layer sharing across all images.
+type ImageLayerReport struct {
+	Layers           []LayerInfo       `json:"layers"`
+	Images           []ImageLayerUsage `json:"images"`
+	TotalLayers      int               `json:"total_layers"`
+	SharedLayers     int               `json:"shared_layers"`
+	UniqueLayerBytes int64             `json:"unique_layer_bytes"` // Each distinct layer counted once
+	SharedLayerBytes int64             `json:"shared_layer_bytes"`
+}
+
+// imageLayers holds the layers of one image with their sizes.
+type imageLayers struct {
+	summary image.Summary
+	layers  []string
+	sizes   map[string]int64
+}
+
+// AnalyzeImageLayers reports which layers are shared by which images, built from each
+// image's RootFS layers. The daemon does not expose per-layer sizes directly, so sizes
+// are taken from the image history, whose non-empty entries follow the layer order.
+func (s *ImageService) AnalyzeImageLayers(ctx context.Context) (*ImageLayerReport, error) {
+	images, err := s.dockerClient.ImageList(ctx, image.ListOptions{})
+	if err != nil {
+		log.Printf("Failed to list images for layer analysis: %v", err)
+		return nil, fmt.Errorf("failed to list images: %w", err)
+	}
+
+	analyzed := make([]*imageLayers, len(images))
+	sem := make(chan struct{}, layerInspectConcurrency)
+	var wg sync.WaitGroup
+
+	for i, img := range images {
+		wg.Add(1)
+		go func(i int, img image.Summary) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			layers, err := s.imageLayers(ctx, img)
+			if err != nil {
+				log.Printf("Failed to analyze layers of image %s: %v", img.ID, err)
+				return
+			}
+			analyzed[i] = layers
+		}(i, img)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("layer analysis interrupted: %w", err)
+	}
+
+	layersByID := make(map[string]*LayerInfo)
+	var order []string
+	for _, img := range analyzed {
+		if img == nil {
+			continue
+		}
+		for _, diffID := range img.layers {
+			layer, ok := layersByID[diffID]
+			if !ok {
+				layer = &LayerInfo{DiffID: diffID, Size: img.sizes[diffID]}
+				layersByID[diffID] = layer
+				order = append(order, diffID)
+			}
+			// An image may repeat a layer; count it once
+			if len(layer.Images) == 0 || layer.Images[len(layer.Images)-1] != img.summary.ID {
+				layer.Images = append(layer.Images, img.summary.ID)
+			}
+		}
+	}
+
+	report := &ImageLayerReport{
+		Layers: make([]LayerInfo, 0, len(order)),
+		Images: []ImageLayerUsage{},
+	}
+	for _, diffID := range order {
+		layer := layersByID[diffID]
+		layer.Shared = len(layer.Images) > 1
+		report.UniqueLayerBytes += layer.Size
+		if layer.Shared {
+			report.SharedLayers++
+			report.SharedLayerBytes += layer.Size
+		}
+		report.Layers = append(report.Layers, *layer)
+	}
+	report.TotalLayers = len(report.Layers)
+
+	sort.SliceStable(report.Layers, func(i, j int) bool {
+		return report.Layers[i].Size > report.Layers[j].Size
+	})
+
+	for _, img := range analyzed {
+		if img == nil {
+			continue
+		}
+		usage := ImageLayerUsage{
+			ID:       img.summary.ID,
+			RepoTags: img.summary.RepoTags,
+			Layers:   len(img.layers),
+			Size:     img.summary.Size,
+		}
+		for _, diffID := range img.layers {
+			if layer := layersByID[diffID]; !layer.Shared {
+				usage.ExclusiveSize += layer.Size
+			}
+		}
+		report.Images = append(report.Images, usage)
+	}
+
+	return report, nil
+}
+
+// imageLayers reads an image's layer diff IDs and matches them to history entry sizes.
+func (s *ImageService) imageLayers(ctx context.Context, img image.Summary) (*imageLayers, error) {
+	inspect, _, err := s.dockerClient.ImageInspectWithRaw(ctx, img.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect image: %w", err)
+	}
+
+	history, err := s.dockerClient.ImageHistory(ctx, img.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get image history: %w", err)
+	}
+
+	result := &imageLayers{
+		summary: img,
+		layers:  inspect.RootFS.Layers,
+		sizes:   make(map[string]int64, len(inspect.RootFS.Layers)),
+	}
+
+	// History is newest first; empty-layer entries (ENV, CMD, ...) report size 0.
+	// Layers with no content also report 0, so when there are more layers than
+	// non-empty entries, zero-size entries are taken as layers until the counts match.
+	nonEmpty := 0
+	for _, h := range history {
+		if h.Size > 0 {
+			nonEmpty++
+		}
+	}
+	spareZero := len(result.layers) - nonEmpty
+
+	next := 0
+	for i := len(history) - 1; i >= 0 && next < len(result.layers); i-- {
+		h := history[i]
+		if h.Size == 0 {
+			if spareZero <= 0 {
+				continue
+			}
+			spareZero--
+		}
+		result.sizes[result.layers[next]] = h.Size
+		next++
+	}
+
+	return result, nil
+}
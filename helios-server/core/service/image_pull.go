@@ -0,0 +1,106 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/errdefs"
+)
+
+// Retry policy for image pulls interrupted by transient errors.
+const (
+	pullMaxAttempts    = 4
+	pullRetryBaseDelay = 2 * time.Second
+	pullRetryMaxDelay  = 30 * time.Second
+)
+
+// fatalPullMessages mark registry errors that retrying cannot fix.
+var fatalPullMessages = []string{
+	"unauthorized",
+	"authentication required",
+	"denied",
+	"not found",
+	"manifest unknown",
+	"invalid reference",
+	"no matching manifest",
+}
+
+// PullRetry describes an upcoming retry of an interrupted pull.
+type PullRetry struct {
+	Attempt     int    `json:"attempt"` // Attempt about to start, from 2
+	MaxAttempts int    `json:"max_attempts"`
+	DelayMs     int64  `json:"delay_ms"`
+	Reason      string `json:"reason"`
+}
+
+// decodePull forwards a pull stream's progress until it ends. It returns the error
+// the stream reported, if any, together with the progress message carrying it,
+// which is not forwarded so the caller can decide whether the failure is final.
+func decodePull(ctx context.Context, reader io.Reader, progressChan chan<- PullProgress) (*PullProgress, error) {
+	decoder := json.NewDecoder(reader)
+	for {
+		var progress PullProgress
+		if err := decoder.Decode(&progress); err != nil {
+			if err == io.EOF {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to decode progress: %w", err)
+		}
+
+		// Check for errors in progress
+		if progress.Error != "" || len(progress.ErrorDetail) > 0 {
+			errMsg := progress.Error
+			if len(progress.ErrorDetail) > 0 {
+				if detailMsg, ok := progress.ErrorDetail["message"].(string); ok {
+					errMsg += ": " + detailMsg
+				} else {
+					// Fallback: convert the whole map to string
+					errMsg += fmt.Sprintf(": %v", progress.ErrorDetail)
+				}
+			}
+			return &progress, errors.New(errMsg)
+		}
+		if strings.Contains(strings.ToLower(progress.Status), "error") {
+			return &progress, fmt.Errorf("pull error: %s", progress.Status)
+		}
+
+		select {
+		case progressChan <- progress:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// isRetryablePullError reports whether a pull failure looks transient (a dropped
+// connection, a timeout) rather than a problem with the reference or credentials.
+func isRetryablePullError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errdefs.IsNotFound(err) || errdefs.IsUnauthorized(err) || errdefs.IsForbidden(err) || errdefs.IsInvalidParameter(err) {
+		return false
+	}
+	message := strings.ToLower(err.Error())
+	for _, fatal := range fatalPullMessages {
+		if strings.Contains(message, fatal) {
+			return false
+		}
+	}
+	return true
+}
+
+// pullRetryDelay returns the backoff before retrying after the given failed attempt.
+func pullRetryDelay(attempt int) time.Duration {
+	delay := pullRetryBaseDelay << (attempt - 1)
+	if delay > pullRetryMaxDelay {
+		delay = pullRetryMaxDelay
+	}
+	return delay
+}
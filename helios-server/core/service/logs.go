@@ -3,36 +3,167 @@ package service
 
 import (
 	"archive/zip"
-	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/docker/docker/api/types/container"
+	"nfcunha/helios/core/repository"
+	"nfcunha/helios/utils/config"
 	"nfcunha/helios/utils/docker"
 )
 
 // LogService handles container log operations.
 type LogService struct {
-	dockerClient *docker.Client
+	dockerClient  *docker.Client
+	actionLogRepo *repository.ActionLogRepository
+	eventBus      *EventBus
+	scope         *ContainerScope
+	maxTail       int
 }
 
+// Default number of history lines for each log endpoint, used when no tail is given.
+const (
+	DefaultStreamTail   = "100" // Single container stream
+	DefaultMatchingTail = "10"  // Per container when following several containers
+	DefaultArchiveTail  = "all" // Log downloads
+)
+
+// ErrInvalidTail is returned when a tail value is not "all" or a line count within the configured limit.
+var ErrInvalidTail = errors.New("invalid tail")
+
 // NewLogService creates a new log service.
-func NewLogService(dockerClient *docker.Client) *LogService {
+// The event bus is used to attach to containers as they start when following several containers,
+// and only containers within the given scope are followed.
+func NewLogService(dockerClient *docker.Client, actionLogRepo *repository.ActionLogRepository, eventBus *EventBus, scope *ContainerScope, logsCfg config.LogsConfig) *LogService {
 	return &LogService{
-		dockerClient: dockerClient,
+		dockerClient:  dockerClient,
+		actionLogRepo: actionLogRepo,
+		eventBus:      eventBus,
+		scope:         scope,
+		maxTail:       logsCfg.MaxTail,
 	}
 }
 
+// NormalizeTail validates a requested tail and returns it in the form Docker expects.
+// An empty value yields def; otherwise the value must be "all" (in any case) or a
+// line count between 0 and the configured maximum. "all" is passed through, because
+// downloads exist to fetch a container's whole log; streams use NormalizeStreamTail.
+func (s *LogService) NormalizeTail(raw, def string) (string, error) {
+	tail := strings.TrimSpace(raw)
+	if tail == "" {
+		return def, nil
+	}
+	if strings.EqualFold(tail, "all") {
+		return "all", nil
+	}
+
+	lines, err := strconv.Atoi(tail)
+	if err != nil || lines < 0 {
+		return "", fmt.Errorf("%w: %q must be \"all\" or a number of lines", ErrInvalidTail, raw)
+	}
+	if s.maxTail > 0 && lines > s.maxTail {
+		return "", fmt.Errorf("%w: %d lines exceeds the maximum of %d", ErrInvalidTail, lines, s.maxTail)
+	}
+	return strconv.Itoa(lines), nil
+}
+
+// NormalizeStreamTail is NormalizeTail for live log streams, where "all" is capped at
+// the configured maximum so a client cannot replay an unbounded history into a socket.
+func (s *LogService) NormalizeStreamTail(raw, def string) (string, error) {
+	tail, err := s.NormalizeTail(raw, def)
+	if err != nil || tail != "all" || s.maxTail <= 0 {
+		return tail, err
+	}
+	return strconv.Itoa(s.maxTail), nil
+}
+
 // LogStreamOptions represents options for streaming container logs.
 type LogStreamOptions struct {
 	Follow     bool   // Follow log output
-	Tail       string // Number of lines to show from the end (default "all")
+	Tail       string // Number of lines to show from the end, as returned by NormalizeTail (Docker's default is "all")
 	Since      string // Show logs since timestamp
 	Until      string // Show logs before timestamp
 	Timestamps bool   // Show timestamps
+
+	// Timestamp normalization, only applied when Timestamps is set
+	TimestampFormat   string         // Go time layout for timestamps (default: RFC3339Nano as sent by Docker)
+	TimestampLocation *time.Location // Time zone for timestamps (default: UTC as sent by Docker)
+	TimestampField    bool           // Emit each line as JSON with the timestamp in a separate field
+}
+
+// LogLine is a single log line with its timestamp split out, emitted when
+// LogStreamOptions.TimestampField is set.
+type LogLine struct {
+	Timestamp string `json:"timestamp,omitempty"`
+	Line      string `json:"line"`
+}
+
+// normalizesTimestamps reports whether log lines need to be rewritten.
+func (o LogStreamOptions) normalizesTimestamps() bool {
+	return o.Timestamps && (o.TimestampFormat != "" || o.TimestampLocation != nil || o.TimestampField)
+}
+
+// normalizeLogLine reformats the RFC3339Nano timestamp Docker prefixes to a log line.
+// Lines without a parseable timestamp are passed through unchanged, or wrapped
+// without a timestamp when the JSON field form is requested.
+func normalizeLogLine(line []byte, opts LogStreamOptions) []byte {
+	timestamp := ""
+	rest := line
+
+	if idx := bytes.IndexByte(line, ' '); idx > 0 {
+		if ts, err := time.Parse(time.RFC3339Nano, string(line[:idx])); err == nil {
+			if opts.TimestampLocation != nil {
+				ts = ts.In(opts.TimestampLocation)
+			}
+			layout := opts.TimestampFormat
+			if layout == "" {
+				layout = time.RFC3339Nano
+			}
+			timestamp = ts.Format(layout)
+			rest = line[idx+1:]
+		}
+	}
+
+	if opts.TimestampField {
+		encoded, err := json.Marshal(LogLine{
+			Timestamp: timestamp,
+			Line:      string(bytes.TrimRight(rest, "\r\n")),
+		})
+		if err != nil {
+			return line
+		}
+		return append(encoded, '\n')
+	}
+
+	if timestamp == "" {
+		return line
+	}
+	return append([]byte(timestamp+" "), rest...)
+}
+
+// normalizeLogLines applies normalizeLogLine to every line in data.
+func normalizeLogLines(data []byte, opts LogStreamOptions) []byte {
+	var result bytes.Buffer
+	for len(data) > 0 {
+		end := bytes.IndexByte(data, '\n')
+		if end < 0 {
+			end = len(data) - 1
+		}
+		result.Write(normalizeLogLine(data[:end+1], opts))
+		data = data[end+1:]
+	}
+	return result.Bytes()
 }
 
 // StreamLogs streams container logs to the provided writer.
@@ -111,8 +242,14 @@ func (s *LogService) StreamLogs(ctx context.Context, containerID string, opts Lo
 					return
 				}
 
+				// Each frame carries one line; rewrite its timestamp if requested
+				payload := buf[:n]
+				if opts.normalizesTimestamps() {
+					payload = normalizeLogLine(payload, opts)
+				}
+
 				// Write to output
-				if _, err := writer.Write(buf[:n]); err != nil {
+				if _, err := writer.Write(payload); err != nil {
 					errChan <- fmt.Errorf("failed to write log data: %w", err)
 					return
 				}
@@ -185,56 +322,288 @@ func (s *LogService) GetLogs(ctx context.Context, containerID string, opts LogSt
 			return "", fmt.Errorf("failed to read log payload: %w", err)
 		}
 
-		result = append(result, buf[:n]...)
+		if opts.normalizesTimestamps() {
+			result = append(result, normalizeLogLine(buf[:n], opts)...)
+		} else {
+			result = append(result, buf[:n]...)
+		}
 	}
 
 	return string(result), nil
 }
 
-// CreateLogArchive creates a ZIP archive of container logs.
-func (s *LogService) CreateLogArchive(ctx context.Context, containerID string, writer io.Writer) error {
+// Log archive compression modes.
+const (
+	LogCompressionDeflate = "deflate" // ZIP archive with deflated entries (default)
+	LogCompressionStore   = "store"   // ZIP archive with uncompressed entries
+	LogCompressionGzip    = "gzip"    // Single gzip stream of the merged logs
+)
+
+// LogArchiveOptions represents options for creating a log archive.
+type LogArchiveOptions struct {
+	SplitStreams      bool           // Write stdout and stderr to separate files
+	TimestampFormat   string         // Go time layout for timestamps (default: RFC3339Nano)
+	TimestampLocation *time.Location // Time zone for timestamps (default: UTC)
+	Compression       string         // LogCompressionDeflate, LogCompressionStore or LogCompressionGzip
+	Level             int            // 1 (fastest) to 9 (smallest) for deflate and gzip; 0 uses the default
+	Tail              string         // Number of lines from the end (default DefaultArchiveTail)
+}
+
+// LogArchiveResult reports the size of a written log archive.
+type LogArchiveResult struct {
+	UncompressedSize int64 // Log bytes written into the archive
+	CompressedSize   int64 // Bytes written to the output
+}
+
+// ValidateLogArchiveOptions checks the compression settings of a log archive request.
+func ValidateLogArchiveOptions(opts LogArchiveOptions) error {
+	switch opts.Compression {
+	case "", LogCompressionDeflate, LogCompressionStore:
+	case LogCompressionGzip:
+		if opts.SplitStreams {
+			return errors.New("gzip compression writes a single file and cannot split streams")
+		}
+	default:
+		return fmt.Errorf("invalid compression %q: must be %s, %s or %s",
+			opts.Compression, LogCompressionDeflate, LogCompressionStore, LogCompressionGzip)
+	}
+	if opts.Level < 0 || opts.Level > 9 {
+		return fmt.Errorf("invalid compression level %d: must be between 1 and 9", opts.Level)
+	}
+	return nil
+}
+
+// CreateLogArchive writes a compressed archive of container logs.
+// By default stdout and stderr are merged into a single file in a ZIP archive; with
+// SplitStreams the archive contains separate stdout.log and stderr.log entries, and with
+// LogCompressionGzip the merged logs are written as a gzip stream instead. Logs are
+// streamed from the daemon into the archive without being buffered, so the output
+// must be written as it is produced.
+func (s *LogService) CreateLogArchive(ctx context.Context, containerID string, opts LogArchiveOptions, writer io.Writer) (*LogArchiveResult, error) {
 	// Get container info for filename
 	containerJSON, err := s.dockerClient.ContainerInspect(ctx, containerID)
 	if err != nil {
-		return fmt.Errorf("failed to inspect container: %w", err)
+		return nil, fmt.Errorf("failed to inspect container: %w", err)
 	}
 
-	// Get logs
-	logs, err := s.GetLogs(ctx, containerID, LogStreamOptions{
-		Timestamps: true,
-		Tail:       "all",
-	})
-	if err != nil {
-		return fmt.Errorf("failed to get logs: %w", err)
-	}
+	containerName := containerDisplayName(containerJSON.Name)
 
-	// Create ZIP archive
-	zipWriter := zip.NewWriter(writer)
-	defer zipWriter.Close()
+	if opts.Tail == "" {
+		opts.Tail = DefaultArchiveTail
+	}
+	logOpts := LogStreamOptions{
+		Timestamps:        true,
+		Tail:              opts.Tail,
+		TimestampFormat:   opts.TimestampFormat,
+		TimestampLocation: opts.TimestampLocation,
+		// Read up to the moment the archive was requested
+		Until: time.Now().Format(time.RFC3339Nano),
+	}
 
-	// Create log file in archive
-	containerName := containerJSON.Name
-	if len(containerName) > 0 && containerName[0] == '/' {
-		containerName = containerName[1:]
+	level := flate.DefaultCompression
+	if opts.Level > 0 {
+		level = opts.Level
 	}
 
+	output := &countingWriter{writer: writer}
+	logs := &countingWriter{}
 	timestamp := time.Now().Format("20060102-150405")
 	filename := fmt.Sprintf("%s_%s.log", containerName, timestamp)
 
-	fileWriter, err := zipWriter.Create(filename)
+	if opts.Compression == LogCompressionGzip {
+		gzipWriter, err := gzip.NewWriterLevel(output, level)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gzip writer: %w", err)
+		}
+		gzipWriter.Name = filename
+		logs.writer = gzipWriter
+
+		if err := s.copyLogs(ctx, containerID, logOpts, logs, nil); err != nil {
+			return nil, err
+		}
+		if err := gzipWriter.Close(); err != nil {
+			return nil, fmt.Errorf("failed to finish gzip stream: %w", err)
+		}
+	} else {
+		zipWriter := zip.NewWriter(output)
+		method := zip.Deflate
+		if opts.Compression == LogCompressionStore {
+			method = zip.Store
+		}
+		zipWriter.RegisterCompressor(zip.Deflate, func(w io.Writer) (io.WriteCloser, error) {
+			return flate.NewWriter(w, level)
+		})
+
+		newEntry := func(name string) (io.Writer, error) {
+			fileWriter, err := zipWriter.CreateHeader(&zip.FileHeader{
+				Name:     name,
+				Method:   method,
+				Modified: time.Now(),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to create zip entry: %w", err)
+			}
+			return fileWriter, nil
+		}
+
+		if opts.SplitStreams {
+			if err := s.writeSplitEntries(ctx, containerID, logOpts, newEntry, logs); err != nil {
+				return nil, err
+			}
+		} else {
+			fileWriter, err := newEntry(filename)
+			if err != nil {
+				return nil, err
+			}
+			logs.writer = fileWriter
+			if err := s.copyLogs(ctx, containerID, logOpts, logs, nil); err != nil {
+				return nil, err
+			}
+		}
+		if err := zipWriter.Close(); err != nil {
+			return nil, fmt.Errorf("failed to finish zip archive: %w", err)
+		}
+	}
+
+	result := &LogArchiveResult{UncompressedSize: logs.n, CompressedSize: output.n}
+	log.Printf("Created log archive for container %s (%d bytes, %d compressed)",
+		containerName, result.UncompressedSize, result.CompressedSize)
+	return result, nil
+}
+
+// writeSplitEntries writes stdout.log and stderr.log entries from a single read of the
+// multiplexed logs, so Tail counts lines across both streams as in a merged archive.
+// A zip archive is written one entry at a time, so stdout streams straight into its
+// entry while stderr is spooled to a temporary file and copied in afterwards.
+func (s *LogService) writeSplitEntries(ctx context.Context, containerID string, logOpts LogStreamOptions, newEntry func(name string) (io.Writer, error), logs *countingWriter) error {
+	spool, err := os.CreateTemp("", "helios-stderr-*.log")
+	if err != nil {
+		return fmt.Errorf("failed to create stderr spool file: %w", err)
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	stdoutWriter, err := newEntry("stdout.log")
+	if err != nil {
+		return err
+	}
+	logs.writer = stdoutWriter
+	if err := s.copyLogs(ctx, containerID, logOpts, logs, spool); err != nil {
+		return err
+	}
+
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind stderr spool file: %w", err)
+	}
+	stderrWriter, err := newEntry("stderr.log")
+	if err != nil {
+		return err
+	}
+	logs.writer = stderrWriter
+	if _, err := io.Copy(logs, spool); err != nil {
+		return fmt.Errorf("failed to write logs to archive: %w", err)
+	}
+	return nil
+}
+
+// copyLogs streams the logs of a container to dst, reformatting timestamps as
+// requested, without holding more than one line per stream in memory. When errDst
+// is set, stderr is written there and only stdout goes to dst.
+func (s *LogService) copyLogs(ctx context.Context, containerID string, logOpts LogStreamOptions, dst, errDst io.Writer) error {
+	reader, err := s.dockerClient.ContainerLogs(ctx, containerID, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Timestamps: logOpts.Timestamps,
+		Tail:       logOpts.Tail,
+		Until:      logOpts.Until,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to create zip entry: %w", err)
+		return fmt.Errorf("failed to get container logs: %w", err)
 	}
+	defer reader.Close()
 
-	// Write logs to zip
-	if _, err := fileWriter.Write([]byte(logs)); err != nil {
-		return fmt.Errorf("failed to write logs to zip: %w", err)
+	var out, errOut *normalizingWriter
+	if logOpts.normalizesTimestamps() {
+		out = &normalizingWriter{dst: dst, opts: logOpts}
+		dst = out
+		if errDst != nil {
+			errOut = &normalizingWriter{dst: errDst, opts: logOpts}
+			errDst = errOut
+		}
 	}
 
-	log.Printf("Created log archive for container %s (%d bytes)", containerName, len(logs))
+	if errDst == nil {
+		err = copyMultiplexed(dst, reader)
+	} else {
+		err = demultiplex(dst, errDst, reader)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write logs to archive: %w", err)
+	}
+
+	for _, w := range []*normalizingWriter{out, errOut} {
+		if w == nil {
+			continue
+		}
+		if err := w.Flush(); err != nil {
+			return fmt.Errorf("failed to write logs to archive: %w", err)
+		}
+	}
 	return nil
 }
 
+// normalizingWriter reformats the timestamp of each line written through it.
+// A trailing partial line is held until it is completed or flushed.
+type normalizingWriter struct {
+	dst     io.Writer
+	opts    LogStreamOptions
+	partial []byte
+}
+
+func (w *normalizingWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		end := bytes.IndexByte(p, '\n')
+		if end < 0 {
+			w.partial = append(w.partial, p...)
+			break
+		}
+
+		line := p[:end+1]
+		if len(w.partial) > 0 {
+			line = append(w.partial, line...)
+		}
+		if _, err := w.dst.Write(normalizeLogLine(line, w.opts)); err != nil {
+			return 0, err
+		}
+		w.partial = w.partial[:0]
+		p = p[end+1:]
+	}
+	return n, nil
+}
+
+// Flush writes the held partial line, if any.
+func (w *normalizingWriter) Flush() error {
+	if len(w.partial) == 0 {
+		return nil
+	}
+	_, err := w.dst.Write(normalizeLogLine(w.partial, w.opts))
+	w.partial = w.partial[:0]
+	return err
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	writer io.Writer
+	n      int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
 // StreamLogsWithWriter is a convenience method that handles the writer lifecycle.
 type LogWriter struct {
 	writer io.Writer
@@ -251,30 +620,52 @@ func (lw *LogWriter) Flush() error {
 	return nil
 }
 
-// StdoutStderr splits Docker multiplexed stream into stdout and stderr.
-func StdoutStderr(reader io.Reader) (stdout, stderr io.Reader) {
-	stdoutReader, stdoutWriter := io.Pipe()
-	stderrReader, stderrWriter := io.Pipe()
+// copyMultiplexed writes the stdout and stderr payloads of a Docker multiplexed
+// stream to dst in the order they were written, dropping the 8-byte frame headers.
+func copyMultiplexed(dst io.Writer, reader io.Reader) error {
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		size := uint32(header[4])<<24 | uint32(header[5])<<16 | uint32(header[6])<<8 | uint32(header[7])
+		if _, err := io.CopyN(dst, reader, int64(size)); err != nil {
+			return err
+		}
+	}
+}
 
-	go func() {
-		defer stdoutWriter.Close()
-		defer stderrWriter.Close()
-
-		scanner := bufio.NewScanner(reader)
-		for scanner.Scan() {
-			line := scanner.Bytes()
-			if len(line) > 0 {
-				// First byte indicates stream type: 1=stdout, 2=stderr
-				if line[0] == 1 {
-					stdoutWriter.Write(line[8:])
-					stdoutWriter.Write([]byte("\n"))
-				} else if line[0] == 2 {
-					stderrWriter.Write(line[8:])
-					stderrWriter.Write([]byte("\n"))
-				}
+// demultiplex splits a Docker multiplexed stream into stdout and stderr.
+// Each frame carries an 8-byte header whose first byte identifies the stream
+// (1=stdout, 2=stderr) and whose last four bytes hold the payload size.
+func demultiplex(stdout, stderr io.Writer, reader io.Reader) error {
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			if err == io.EOF {
+				return nil
 			}
+			return err
 		}
-	}()
 
-	return stdoutReader, stderrReader
+		size := uint32(header[4])<<24 | uint32(header[5])<<16 | uint32(header[6])<<8 | uint32(header[7])
+
+		var dst io.Writer
+		switch header[0] {
+		case 1:
+			dst = stdout
+		case 2:
+			dst = stderr
+		default:
+			dst = io.Discard
+		}
+
+		if _, err := io.CopyN(dst, reader, int64(size)); err != nil {
+			return err
+		}
+	}
 }
@@ -0,0 +1,109 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"nfcunha/helios/core/models"
+)
+
+// ErrLogClearUnsupported is returned when a container's logging driver does not keep
+// a local file that Helios can truncate.
+var ErrLogClearUnsupported = errors.New("clearing logs is only supported for the json-file logging driver")
+
+// ErrLogFileInaccessible is returned when the container's log file cannot be reached
+// from the Helios process (e.g. /var/lib/docker/containers is not mounted).
+var ErrLogFileInaccessible = errors.New("container log file is not accessible to Helios")
+
+// rotatedLogPattern matches json-file rotations of a log file ("<path>.1", "<path>.2.gz").
+var rotatedLogPattern = regexp.MustCompile(`\.\d+(\.gz)?$`)
+
+// ClearLogs truncates a json-file container's log file in place and deletes its rotated
+// files, without recreating the container. Returns the number of bytes reclaimed.
+// The log path reported by the daemon must be visible to Helios at the same location.
+func (s *LogService) ClearLogs(ctx context.Context, containerID string) (int64, error) {
+	containerJSON, err := s.dockerClient.ContainerInspect(ctx, containerID)
+	if err != nil {
+		log.Printf("Failed to inspect container %s: %v", containerID, err)
+		return 0, fmt.Errorf("failed to inspect container: %w", err)
+	}
+
+	containerName := containerDisplayName(containerJSON.Name)
+
+	driver := ""
+	if containerJSON.HostConfig != nil {
+		driver = containerJSON.HostConfig.LogConfig.Type
+	}
+	if driver != "json-file" {
+		err := fmt.Errorf("%w (container %s uses %q)", ErrLogClearUnsupported, containerName, driver)
+		return 0, s.logAction("clear_logs", "container", containerJSON.ID, containerName, false, err)
+	}
+
+	if containerJSON.LogPath == "" {
+		err := fmt.Errorf("%w: the daemon reported no log path", ErrLogFileInaccessible)
+		return 0, s.logAction("clear_logs", "container", containerJSON.ID, containerName, false, err)
+	}
+
+	info, err := os.Stat(containerJSON.LogPath)
+	if err != nil {
+		err = fmt.Errorf("%w: %v", ErrLogFileInaccessible, err)
+		return 0, s.logAction("clear_logs", "container", containerJSON.ID, containerName, false, err)
+	}
+
+	// The daemon keeps the file open in append mode, so truncating in place is safe
+	if err := os.Truncate(containerJSON.LogPath, 0); err != nil {
+		log.Printf("Failed to truncate log file for container %s: %v", containerName, err)
+		err = fmt.Errorf("failed to truncate log file: %w", err)
+		return 0, s.logAction("clear_logs", "container", containerJSON.ID, containerName, false, err)
+	}
+	reclaimed := info.Size()
+
+	rotated, _ := filepath.Glob(containerJSON.LogPath + ".*")
+	for _, path := range rotated {
+		if !rotatedLogPattern.MatchString(path) {
+			continue
+		}
+		rotatedInfo, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			log.Printf("Failed to remove rotated log file %s: %v", path, err)
+			continue
+		}
+		reclaimed += rotatedInfo.Size()
+	}
+
+	log.Printf("Cleared logs for container %s (%d bytes reclaimed)", containerName, reclaimed)
+	s.logAction("clear_logs", "container", containerJSON.ID, containerName, true, nil)
+	return reclaimed, nil
+}
+
+// logAction logs an action to the database.
+func (s *LogService) logAction(actionType, resourceType, resourceID, resourceName string, success bool, err error) error {
+	actionLog := &models.ActionLog{
+		ActionType:   actionType,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		ResourceName: resourceName,
+		Success:      success,
+		ExecutedAt:   time.Now(),
+	}
+
+	if err != nil {
+		actionLog.ErrorMessage = err.Error()
+	}
+
+	if logErr := s.actionLogRepo.Create(actionLog); logErr != nil {
+		log.Printf("Failed to log action: %v", logErr)
+	}
+
+	return err
+}
@@ -0,0 +1,125 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// ErrLogDriverUnsupported is returned when a container's logging driver cannot be read back.
+var ErrLogDriverUnsupported = errors.New("logging driver does not support reading logs")
+
+// LogDriverInfo describes a container's logging driver.
+type LogDriverInfo struct {
+	Driver   string            `json:"driver"`
+	Options  map[string]string `json:"options,omitempty"`
+	Readable bool              `json:"readable"` // Whether logs can be streamed and downloaded
+	Rotation LogRotation       `json:"rotation"`
+}
+
+// Log rotation states reported in LogRotation.Status.
+const (
+	LogRotationConfigured    = "configured"     // max-size is set
+	LogRotationDriverDefault = "driver_default" // The driver rotates by default (local)
+	LogRotationMissing       = "missing"        // Logs grow without bound on the host
+	LogRotationExternal      = "external"       // Logs are shipped elsewhere; rotation is managed there
+	LogRotationNoLogs        = "no_logs"        // The none driver keeps no logs
+)
+
+// LogRotation describes how a container's log files are rotated on the host.
+type LogRotation struct {
+	Status  string `json:"status"`
+	MaxSize string `json:"max_size,omitempty"`
+	MaxFile string `json:"max_file,omitempty"`
+	Missing bool   `json:"missing"` // No rotation; the logs can fill the disk
+}
+
+// readableLogDrivers lists drivers that store logs the daemon can read back natively.
+var readableLogDrivers = map[string]bool{
+	"json-file": true,
+	"local":     true,
+	"journald":  true,
+}
+
+// logDriverReadable reports whether the daemon can serve logs for a logging configuration.
+// Remote drivers (syslog, fluentd, ...) are readable through the daemon's dual logging
+// cache unless it is disabled for the container; "none" keeps no logs at all.
+func logDriverReadable(cfg container.LogConfig) bool {
+	if cfg.Type == "none" {
+		return false
+	}
+	if readableLogDrivers[cfg.Type] || cfg.Type == "" {
+		return true
+	}
+	return cfg.Config["cache-disabled"] != "true"
+}
+
+// logDriverFromHostConfig reports a container's logging driver.
+func logDriverFromHostConfig(hostConfig *container.HostConfig) *LogDriverInfo {
+	if hostConfig == nil {
+		return nil
+	}
+	return &LogDriverInfo{
+		Driver:   hostConfig.LogConfig.Type,
+		Options:  hostConfig.LogConfig.Config,
+		Readable: logDriverReadable(hostConfig.LogConfig),
+		Rotation: logRotation(hostConfig.LogConfig),
+	}
+}
+
+// logRotation reports the rotation of a logging configuration. Daemon-wide log-opts
+// are merged into the container's configuration at creation, so inspect data is
+// authoritative. json-file only rotates when max-size is set (max-file alone has no
+// effect); local rotates at 20m x 5 files unless told otherwise.
+func logRotation(cfg container.LogConfig) LogRotation {
+	rotation := LogRotation{
+		MaxSize: cfg.Config["max-size"],
+		MaxFile: cfg.Config["max-file"],
+	}
+
+	switch cfg.Type {
+	case "json-file", "":
+		if rotation.MaxSize != "" && rotation.MaxSize != "-1" {
+			rotation.Status = LogRotationConfigured
+		} else {
+			rotation.Status = LogRotationMissing
+			rotation.Missing = true
+		}
+	case "local":
+		if rotation.MaxSize == "-1" {
+			rotation.Status = LogRotationMissing
+			rotation.Missing = true
+		} else if rotation.MaxSize != "" {
+			rotation.Status = LogRotationConfigured
+		} else {
+			rotation.Status = LogRotationDriverDefault
+		}
+	case "none":
+		rotation.Status = LogRotationNoLogs
+	default:
+		rotation.Status = LogRotationExternal
+	}
+
+	return rotation
+}
+
+// CheckLogsReadable verifies that a container's logs can be read before streaming starts,
+// so callers can report an unsupported driver instead of an empty stream.
+func (s *LogService) CheckLogsReadable(ctx context.Context, containerID string) error {
+	containerJSON, err := s.dockerClient.ContainerInspect(ctx, containerID)
+	if err != nil {
+		log.Printf("Failed to inspect container %s: %v", containerID, err)
+		return fmt.Errorf("failed to inspect container: %w", err)
+	}
+
+	driver := logDriverFromHostConfig(containerJSON.HostConfig)
+	if driver != nil && !driver.Readable {
+		return fmt.Errorf("%w: container %s uses the %q logging driver",
+			ErrLogDriverUnsupported, containerDisplayName(containerJSON.Name), driver.Driver)
+	}
+	return nil
+}
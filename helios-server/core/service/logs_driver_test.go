@@ -0,0 +1,31 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+func TestLogDriverReadable(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  container.LogConfig
+		want bool
+	}{
+		{name: "json-file", cfg: container.LogConfig{Type: "json-file"}, want: true},
+		{name: "local", cfg: container.LogConfig{Type: "local"}, want: true},
+		{name: "journald", cfg: container.LogConfig{Type: "journald"}, want: true},
+		{name: "daemon default", cfg: container.LogConfig{}, want: true},
+		{name: "none", cfg: container.LogConfig{Type: "none"}, want: false},
+		{name: "remote with dual logging", cfg: container.LogConfig{Type: "syslog"}, want: true},
+		{name: "remote without dual logging", cfg: container.LogConfig{Type: "fluentd", Config: map[string]string{"cache-disabled": "true"}}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := logDriverReadable(tt.cfg); got != tt.want {
+				t.Errorf("logDriverReadable(%+v) = %v, want %v", tt.cfg, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+)
+
+// LogSelector selects the containers whose logs are followed together.
+type LogSelector struct {
+	Label string // Label as "key" or "key=value"
+	Name  string // Container name substring
+}
+
+// filterArgs converts the selector to Docker list filters.
+func (sel LogSelector) filterArgs() filters.Args {
+	args := filters.NewArgs()
+	if sel.Label != "" {
+		args.Add("label", sel.Label)
+	}
+	if sel.Name != "" {
+		args.Add("name", sel.Name)
+	}
+	return args
+}
+
+// multiLogStream follows the logs of a changing set of containers into a shared writer.
+type multiLogStream struct {
+	service  *LogService
+	opts     LogStreamOptions
+	writer   io.Writer
+	writeMu  sync.Mutex
+	attached map[string]context.CancelFunc
+	mu       sync.Mutex
+	wg       sync.WaitGroup
+}
+
+// FollowMatchingLogs follows the logs of all running containers matching the selector,
+// like a compose-style "logs -f". Each line is prefixed with the container name.
+// Containers that start while streaming are attached automatically and containers that
+// stop are detached. Returns a channel that is closed when the stream ends.
+func (s *LogService) FollowMatchingLogs(ctx context.Context, sel LogSelector, opts LogStreamOptions, writer io.Writer) (<-chan error, error) {
+	if s.eventBus == nil {
+		return nil, fmt.Errorf("container events are not available")
+	}
+
+	// Subscribe before listing so containers starting in between are not missed
+	containerEvents, unsubscribe := s.eventBus.Subscribe(64)
+
+	containers, err := s.dockerClient.ContainerList(ctx, container.ListOptions{Filters: sel.filterArgs()})
+	if err != nil {
+		unsubscribe()
+		log.Printf("Failed to list containers for log stream: %v", err)
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	containers = s.scope.Filter(containers)
+
+	stream := &multiLogStream{
+		service:  s,
+		opts:     opts,
+		writer:   writer,
+		attached: make(map[string]context.CancelFunc),
+	}
+
+	for _, c := range containers {
+		name := ""
+		if len(c.Names) > 0 {
+			name = containerDisplayName(c.Names[0])
+		}
+		stream.attach(ctx, c.ID, name)
+	}
+
+	errChan := make(chan error, 1)
+
+	go func() {
+		defer close(errChan)
+		defer stream.wg.Wait()
+		defer unsubscribe()
+
+		for {
+			select {
+			case <-ctx.Done():
+				errChan <- ctx.Err()
+				return
+			case msg, ok := <-containerEvents:
+				if !ok {
+					return
+				}
+				if msg.Type != events.ContainerEventType {
+					continue
+				}
+				switch msg.Action {
+				case events.ActionStart:
+					stream.attachIfMatching(ctx, sel, msg.Actor.ID)
+				case events.ActionDie:
+					stream.detach(msg.Actor.ID)
+				}
+			}
+		}
+	}()
+
+	return errChan, nil
+}
+
+// attachIfMatching attaches to a newly started container if it matches the selector.
+func (m *multiLogStream) attachIfMatching(ctx context.Context, sel LogSelector, containerID string) {
+	args := sel.filterArgs()
+	args.Add("id", containerID)
+
+	containers, err := m.service.dockerClient.ContainerList(ctx, container.ListOptions{Filters: args})
+	if err != nil {
+		log.Printf("Failed to look up container %s for log stream: %v", containerID, err)
+		return
+	}
+	containers = m.service.scope.Filter(containers)
+
+	for _, c := range containers {
+		name := ""
+		if len(c.Names) > 0 {
+			name = containerDisplayName(c.Names[0])
+		}
+		m.attach(ctx, c.ID, name)
+	}
+}
+
+// attach starts following a container's logs unless it is already attached.
+func (m *multiLogStream) attach(ctx context.Context, containerID, name string) {
+	m.mu.Lock()
+	if _, ok := m.attached[containerID]; ok {
+		m.mu.Unlock()
+		return
+	}
+	containerCtx, cancel := context.WithCancel(ctx)
+	m.attached[containerID] = cancel
+	m.mu.Unlock()
+
+	opts := m.opts
+	opts.Follow = true
+	prefixed := &prefixWriter{prefix: []byte("[" + name + "] "), writer: m.writer, mu: &m.writeMu}
+
+	prefixed.Write([]byte("--- attached ---\n"))
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer m.detach(containerID)
+
+		errChan, err := m.service.StreamLogs(containerCtx, containerID, opts, prefixed)
+		if err != nil {
+			log.Printf("Failed to follow logs for container %s: %v", name, err)
+			return
+		}
+		if err := <-errChan; err != nil && err != context.Canceled {
+			log.Printf("Log stream for container %s ended: %v", name, err)
+		}
+		prefixed.Write([]byte("--- detached ---\n"))
+	}()
+}
+
+// detach stops following a container's logs.
+func (m *multiLogStream) detach(containerID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if cancel, ok := m.attached[containerID]; ok {
+		cancel()
+		delete(m.attached, containerID)
+	}
+}
+
+// prefixWriter prefixes every write with a container name and serializes writes
+// to a writer shared by several log streams.
+type prefixWriter struct {
+	prefix []byte
+	writer io.Writer
+	mu     *sync.Mutex
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	line := make([]byte, 0, len(w.prefix)+len(p))
+	line = append(line, w.prefix...)
+	line = append(line, p...)
+	if _, err := w.writer.Write(line); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
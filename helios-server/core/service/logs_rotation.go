@@ -0,0 +1,89 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// UnrotatedContainer is a container whose logs are not rotated.
+type UnrotatedContainer struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	State   string `json:"state"`
+	Driver  string `json:"driver"`
+	LogPath string `json:"log_path,omitempty"`
+}
+
+// LogRotationReport lists the containers at risk of filling the disk with logs.
+type LogRotationReport struct {
+	Checked   int                  `json:"checked"`
+	Unrotated []UnrotatedContainer `json:"unrotated"`
+	Errors    []string             `json:"errors,omitempty"` // Containers that could not be inspected
+}
+
+// LogRotationReport inspects every container, running or stopped, and reports those
+// without log rotation. Stopped containers are included since their logs stay on disk.
+func (s *ContainerService) LogRotationReport(ctx context.Context) (*LogRotationReport, error) {
+	containers, err := s.dockerClient.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		log.Printf("Failed to list containers for log rotation report: %v", err)
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	containers = s.scope.Filter(containers)
+
+	report := &LogRotationReport{
+		Checked:   len(containers),
+		Unrotated: []UnrotatedContainer{},
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, c := range containers {
+		wg.Add(1)
+		go func(containerID string) {
+			defer wg.Done()
+
+			containerJSON, err := s.dockerClient.ContainerInspect(ctx, containerID)
+			if err != nil {
+				log.Printf("Failed to inspect container %s for log rotation: %v", containerID, err)
+				mu.Lock()
+				report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", containerID, err))
+				mu.Unlock()
+				return
+			}
+
+			driver := logDriverFromHostConfig(containerJSON.HostConfig)
+			if driver == nil || !driver.Rotation.Missing {
+				return
+			}
+
+			unrotated := UnrotatedContainer{
+				ID:      containerJSON.ID,
+				Name:    containerDisplayName(containerJSON.Name),
+				Driver:  driver.Driver,
+				LogPath: containerJSON.LogPath,
+			}
+			if containerJSON.State != nil {
+				unrotated.State = containerJSON.State.Status
+			}
+
+			mu.Lock()
+			report.Unrotated = append(report.Unrotated, unrotated)
+			mu.Unlock()
+		}(c.ID)
+	}
+	wg.Wait()
+
+	sort.Slice(report.Unrotated, func(i, j int) bool {
+		return report.Unrotated[i].Name < report.Unrotated[j].Name
+	})
+
+	return report, nil
+}
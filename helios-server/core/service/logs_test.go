@@ -0,0 +1,150 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNormalizeTail(t *testing.T) {
+	s := &LogService{maxTail: 500}
+
+	tests := []struct {
+		name       string
+		raw        string
+		def        string
+		want       string
+		wantStream string
+		wantErr    bool
+	}{
+		{name: "default", raw: "", def: "100", want: "100", wantStream: "100"},
+		{name: "download default", raw: "", def: DefaultArchiveTail, want: "all", wantStream: "500"},
+		{name: "all", raw: "all", def: "100", want: "all", wantStream: "500"},
+		{name: "all in any case", raw: " ALL ", def: "100", want: "all", wantStream: "500"},
+		{name: "line count", raw: "250", def: "100", want: "250", wantStream: "250"},
+		{name: "zero", raw: "0", def: "100", want: "0", wantStream: "0"},
+		{name: "maximum", raw: "500", def: "100", want: "500", wantStream: "500"},
+		{name: "above maximum", raw: "501", def: "100", wantErr: true},
+		{name: "negative", raw: "-1", def: "100", wantErr: true},
+		{name: "not a number", raw: "lots", def: "100", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.NormalizeTail(tt.raw, tt.def)
+			gotStream, streamErr := s.NormalizeStreamTail(tt.raw, tt.def)
+
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidTail) || !errors.Is(streamErr, ErrInvalidTail) {
+					t.Fatalf("errors = %v, %v, want ErrInvalidTail", err, streamErr)
+				}
+				return
+			}
+			if err != nil || streamErr != nil {
+				t.Fatalf("errors = %v, %v", err, streamErr)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeTail(%q, %q) = %q, want %q", tt.raw, tt.def, got, tt.want)
+			}
+			if gotStream != tt.wantStream {
+				t.Errorf("NormalizeStreamTail(%q, %q) = %q, want %q", tt.raw, tt.def, gotStream, tt.wantStream)
+			}
+		})
+	}
+}
+
+// frame builds one Docker multiplexed stream frame.
+func frame(stream byte, payload string) []byte {
+	n := len(payload)
+	header := []byte{stream, 0, 0, 0, byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n)}
+	return append(header, payload...)
+}
+
+func TestDemultiplex(t *testing.T) {
+	tests := []struct {
+		name       string
+		frames     [][]byte
+		wantStdout string
+		wantStderr string
+		wantErr    bool
+	}{
+		{name: "empty"},
+		{
+			name:       "interleaved",
+			frames:     [][]byte{frame(1, "out 1\n"), frame(2, "err 1\n"), frame(1, "out 2\n")},
+			wantStdout: "out 1\nout 2\n",
+			wantStderr: "err 1\n",
+		},
+		{
+			name:       "stdin frames dropped",
+			frames:     [][]byte{frame(0, "in\n"), frame(2, "err\n")},
+			wantStderr: "err\n",
+		},
+		{
+			name:    "truncated frame",
+			frames:  [][]byte{frame(1, "out\n")[:10]},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			err := demultiplex(&stdout, &stderr, bytes.NewReader(bytes.Join(tt.frames, nil)))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("demultiplex() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if stdout.String() != tt.wantStdout || stderr.String() != tt.wantStderr {
+				t.Errorf("demultiplex() = %q, %q, want %q, %q", stdout.String(), stderr.String(), tt.wantStdout, tt.wantStderr)
+			}
+		})
+	}
+}
+
+func TestNormalizingWriter(t *testing.T) {
+	opts := LogStreamOptions{Timestamps: true, TimestampFormat: time.TimeOnly, TimestampLocation: time.UTC}
+
+	tests := []struct {
+		name   string
+		writes []string
+		want   string
+	}{
+		{
+			name:   "whole lines",
+			writes: []string{"2024-01-01T10:00:00Z a\n2024-01-01T10:00:01Z b\n"},
+			want:   "10:00:00 a\n10:00:01 b\n",
+		},
+		{
+			name:   "line split across writes",
+			writes: []string{"2024-01-01T10:0", "0:00Z a\n"},
+			want:   "10:00:00 a\n",
+		},
+		{
+			name:   "partial line flushed",
+			writes: []string{"2024-01-01T10:00:00Z a\n2024-01-01T10:00:01Z b"},
+			want:   "10:00:00 a\n10:00:01 b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			w := &normalizingWriter{dst: &out, opts: opts}
+			for _, p := range tt.writes {
+				if n, err := w.Write([]byte(p)); err != nil || n != len(p) {
+					t.Fatalf("Write(%q) = %d, %v", p, n, err)
+				}
+			}
+			if err := w.Flush(); err != nil {
+				t.Fatalf("Flush() error = %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("output = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
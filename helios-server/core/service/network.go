@@ -5,10 +5,13 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strconv"
+	"sync"
 	"time"
 
 	"nfcunha/helios/core/models"
 	"nfcunha/helios/core/repository"
+	"nfcunha/helios/utils/config"
 	"nfcunha/helios/utils/docker"
 
 	"github.com/docker/docker/api/types/filters"
@@ -19,13 +22,24 @@ import (
 type NetworkService struct {
 	dockerClient  *docker.Client
 	actionLogRepo *repository.ActionLogRepository
+	protection    *PruneProtection
+	scope         *ContainerScope
+	routeCheck    string
+	labels        *ResourceLabels
 }
 
 // NewNetworkService creates a new network service.
-func NewNetworkService(dockerClient *docker.Client, actionLogRepo *repository.ActionLogRepository) *NetworkService {
+// Container membership views only include containers within the given scope.
+// routeCheck controls how subnets colliding with host routes are handled on creation.
+// Created networks are stamped with the given resource labels.
+func NewNetworkService(dockerClient *docker.Client, actionLogRepo *repository.ActionLogRepository, protection *PruneProtection, scope *ContainerScope, labels *ResourceLabels, networkCfg config.NetworkConfig) *NetworkService {
 	return &NetworkService{
 		dockerClient:  dockerClient,
 		actionLogRepo: actionLogRepo,
+		protection:    protection,
+		scope:         scope,
+		routeCheck:    networkCfg.RouteCheck,
+		labels:        labels,
 	}
 }
 
@@ -62,6 +76,7 @@ type NetworkDetail struct {
 	ConfigFrom network.ConfigReference             `json:"config_from,omitempty"`
 	ConfigOnly bool                                `json:"config_only"`
 	Created    string                              `json:"created"`
+	Warnings   []string                            `json:"warnings,omitempty"` // Set on creation only
 }
 
 // CreateNetworkRequest represents the request to create a network.
@@ -162,6 +177,16 @@ func (s *NetworkService) InspectNetwork(ctx context.Context, networkID string) (
 
 // CreateNetwork creates a new network.
 func (s *NetworkService) CreateNetwork(ctx context.Context, req *CreateNetworkRequest) (*NetworkDetail, error) {
+	warnings, err := checkHostRoutes(req.IPAM, s.routeCheck)
+	if err != nil {
+		log.Printf("Refusing to create network %s: %v", req.Name, err)
+		s.logAction("create", "network", "", req.Name, false, err)
+		return nil, err
+	}
+	for _, warning := range warnings {
+		log.Printf("Warning while creating network %s: %s", req.Name, warning)
+	}
+
 	// Set default driver if not specified
 	driver := req.Driver
 	if driver == "" {
@@ -176,7 +201,7 @@ func (s *NetworkService) CreateNetwork(ctx context.Context, req *CreateNetworkRe
 		Ingress:    req.Ingress,
 		EnableIPv6: &req.EnableIPv6,
 		Options:    req.Options,
-		Labels:     req.Labels,
+		Labels:     s.labels.Apply(req.Labels),
 	}
 
 	// Add IPAM configuration if provided
@@ -193,6 +218,7 @@ func (s *NetworkService) CreateNetwork(ctx context.Context, req *CreateNetworkRe
 
 	if response.Warning != "" {
 		log.Printf("Warning while creating network %s: %s", req.Name, response.Warning)
+		warnings = append(warnings, response.Warning)
 	}
 
 	log.Printf("Successfully created network: %s (ID: %s)", req.Name, response.ID)
@@ -204,12 +230,14 @@ func (s *NetworkService) CreateNetwork(ctx context.Context, req *CreateNetworkRe
 		// Still return success, but log the error
 		log.Printf("Warning: Created network but failed to inspect: %v", err)
 		return &NetworkDetail{
-			ID:     response.ID,
-			Name:   req.Name,
-			Driver: driver,
+			ID:       response.ID,
+			Name:     req.Name,
+			Driver:   driver,
+			Warnings: warnings,
 		}, nil
 	}
 
+	detail.Warnings = warnings
 	return detail, nil
 }
 
@@ -233,8 +261,52 @@ func (s *NetworkService) RemoveNetwork(ctx context.Context, networkID string) er
 	return nil
 }
 
+// predefinedNetworks are created by the daemon and cannot be removed.
+var predefinedNetworks = map[string]bool{"bridge": true, "host": true, "none": true}
+
+// BulkRemoveNetworks removes multiple networks in parallel. Predefined networks
+// (bridge, host, none) are reported as failed without being sent to the daemon.
+// If progress is non-nil, each result is also sent to it as soon as it completes.
+func (s *NetworkService) BulkRemoveNetworks(ctx context.Context, networkIDs []string, progress chan<- BulkOperationResult) []BulkOperationResult {
+	results := make([]BulkOperationResult, len(networkIDs))
+
+	var wg sync.WaitGroup
+	for i, networkID := range networkIDs {
+		wg.Add(1)
+		go func(i int, networkID string) {
+			defer wg.Done()
+
+			result := BulkOperationResult{
+				ContainerID:   networkID,
+				ContainerName: networkID,
+				Success:       true,
+			}
+			if net, err := s.dockerClient.NetworkInspect(ctx, networkID, network.InspectOptions{}); err == nil {
+				result.ContainerName = net.Name
+			}
+
+			if predefinedNetworks[result.ContainerName] {
+				result.Success = false
+				result.Error = fmt.Sprintf("%s is a predefined network and cannot be removed", result.ContainerName)
+			} else if err := s.RemoveNetwork(ctx, networkID); err != nil {
+				result.Success = false
+				result.Error = err.Error()
+			}
+
+			results[i] = result
+			reportBulkResult(progress, result)
+		}(i, networkID)
+	}
+	wg.Wait()
+
+	return results
+}
+
 // PruneNetworks removes unused networks.
-func (s *NetworkService) PruneNetworks(ctx context.Context, pruneFilters map[string][]string) (uint64, []string, error) {
+// The daemon's prune endpoint cannot exclude networks by name, so unused networks are
+// listed and removed one by one, skipping those covered by the prune allowlist or
+// labelled with ProtectLabel or KeepLabel. Supported filters are "label", "label!" and "until".
+func (s *NetworkService) PruneNetworks(ctx context.Context, pruneFilters map[string][]string) (uint64, []string, []SkippedResource, error) {
 	// Convert filter map to filters.Args
 	filterArgs := filters.NewArgs()
 	for key, values := range pruneFilters {
@@ -243,21 +315,70 @@ func (s *NetworkService) PruneNetworks(ctx context.Context, pruneFilters map[str
 		}
 	}
 
-	report, err := s.dockerClient.NetworksPrune(ctx, filterArgs)
+	until, err := parsePruneUntil(filterArgs.Get("until"))
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("invalid until filter: %w", err)
+	}
+
+	// Unused networks; the dangling filter already excludes predefined networks
+	listFilters := filters.NewArgs(filters.Arg("dangling", "true"))
+	for _, label := range filterArgs.Get("label") {
+		listFilters.Add("label", label)
+	}
+
+	networks, err := s.dockerClient.NetworkList(ctx, network.ListOptions{Filters: listFilters})
 	if err != nil {
-		log.Printf("Failed to prune networks: %v", err)
+		log.Printf("Failed to list networks for pruning: %v", err)
 		s.logAction("prune", "network", "all", "all", false, err)
-		return 0, nil, fmt.Errorf("failed to prune networks: %w", err)
+		return 0, nil, nil, fmt.Errorf("failed to prune networks: %w", err)
 	}
 
 	networkNames := make([]string, 0)
-	for _, net := range report.NetworksDeleted {
-		networkNames = append(networkNames, net)
+	skipped := []SkippedResource{}
+	for _, net := range networks {
+		if !until.IsZero() && !net.Created.Before(until) {
+			continue
+		}
+		if len(filterArgs.Get("label!")) > 0 && filterArgs.MatchKVList("label!", net.Labels) {
+			continue
+		}
+
+		if reason := s.protection.NetworkReason(net.ID, net.Name, net.Labels); reason != "" {
+			skipped = append(skipped, SkippedResource{ID: net.ID, Name: net.Name, Reason: reason})
+			continue
+		}
+
+		if err := s.dockerClient.NetworkRemove(ctx, net.ID); err != nil {
+			log.Printf("Failed to remove network %s: %v", net.Name, err)
+			continue
+		}
+		networkNames = append(networkNames, net.Name)
 	}
 
-	log.Printf("Pruned networks, removed: %v", networkNames)
+	log.Printf("Pruned networks, removed: %v (%d protected)", networkNames, len(skipped))
 	s.logAction("prune", "network", "all", "all", true, nil)
-	return 0, networkNames, nil
+	return 0, networkNames, skipped, nil
+}
+
+// parsePruneUntil parses the "until" prune filter, which accepts a Go duration
+// relative to now, an RFC3339 timestamp or a Unix timestamp in seconds.
+// Returns the zero time if no value is given.
+func parsePruneUntil(values []string) (time.Time, error) {
+	if len(values) == 0 {
+		return time.Time{}, nil
+	}
+	value := values[0]
+
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if secs, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(secs, 0), nil
+	}
+	return time.Time{}, fmt.Errorf("unrecognized timestamp %q", value)
 }
 
 // logAction logs an action to the database.
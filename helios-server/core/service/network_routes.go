@@ -0,0 +1,148 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strings"
+
+	"nfcunha/helios/utils/config"
+
+	"github.com/docker/docker/api/types/network"
+)
+
+// HostRoute is a destination the host already reaches through one of its interfaces.
+type HostRoute struct {
+	Interface   string `json:"interface"`
+	Destination string `json:"destination"`
+	Source      string `json:"source"` // "route" (routing table) or "address" (interface address)
+}
+
+// SubnetConflictError is returned when a requested network subnet overlaps a host route.
+type SubnetConflictError struct {
+	Subnet string
+	Route  HostRoute
+}
+
+func (e *SubnetConflictError) Error() string {
+	return fmt.Sprintf("subnet %s collides with host route %s via %s", e.Subnet, e.Route.Destination, e.Route.Interface)
+}
+
+// procNetRoute is the Linux IPv4 routing table.
+const procNetRoute = "/proc/net/route"
+
+// checkHostRoutes compares the subnets requested in ipam against the host's routes
+// according to mode. In reject mode the first collision is returned as a
+// *SubnetConflictError; in warn mode collisions are returned as warnings.
+func checkHostRoutes(ipam *network.IPAM, mode string) ([]string, error) {
+	if mode == config.RouteCheckOff || ipam == nil || len(ipam.Config) == 0 {
+		return nil, nil
+	}
+
+	routes := hostRoutes()
+	var warnings []string
+	for _, pool := range ipam.Config {
+		if pool.Subnet == "" {
+			continue
+		}
+		_, subnet, err := net.ParseCIDR(pool.Subnet)
+		if err != nil {
+			// Let the daemon report the malformed subnet
+			continue
+		}
+		for _, route := range routes {
+			_, dest, err := net.ParseCIDR(route.Destination)
+			if err != nil || !subnetsOverlap(subnet, dest) {
+				continue
+			}
+			conflict := &SubnetConflictError{Subnet: pool.Subnet, Route: route}
+			if mode == config.RouteCheckReject {
+				return nil, conflict
+			}
+			warnings = append(warnings, conflict.Error())
+		}
+	}
+	return warnings, nil
+}
+
+// hostRoutes lists the routes and interface subnets of the network namespace Helios
+// runs in, skipping default routes and Docker's own bridges (overlap between Docker
+// networks is already rejected by the daemon).
+func hostRoutes() []HostRoute {
+	var routes []HostRoute
+
+	if file, err := os.Open(procNetRoute); err == nil {
+		defer file.Close()
+		scanner := bufio.NewScanner(file)
+		scanner.Scan() // Header
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) < 8 || dockerInterface(fields[0]) {
+				continue
+			}
+			dest, errDest := parseProcRouteAddr(fields[1])
+			mask, errMask := parseProcRouteAddr(fields[7])
+			if errDest != nil || errMask != nil {
+				continue
+			}
+			ipNet := net.IPNet{IP: dest, Mask: net.IPMask(mask)}
+			if ones, _ := ipNet.Mask.Size(); ones == 0 {
+				continue
+			}
+			routes = append(routes, HostRoute{Interface: fields[0], Destination: ipNet.String(), Source: "route"})
+		}
+	}
+
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		log.Printf("Failed to list host interfaces: %v", err)
+		return routes
+	}
+	for _, iface := range interfaces {
+		if dockerInterface(iface.Name) || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || ipNet.IP.IsLinkLocalUnicast() {
+				continue
+			}
+			subnet := net.IPNet{IP: ipNet.IP.Mask(ipNet.Mask), Mask: ipNet.Mask}
+			routes = append(routes, HostRoute{Interface: iface.Name, Destination: subnet.String(), Source: "address"})
+		}
+	}
+
+	return routes
+}
+
+// parseProcRouteAddr decodes an address from /proc/net/route, which is written as
+// hex in host (little-endian) byte order.
+func parseProcRouteAddr(s string) (net.IP, error) {
+	raw, err := hex.DecodeString(s)
+	if err != nil || len(raw) != 4 {
+		return nil, fmt.Errorf("invalid route address %q", s)
+	}
+	ip := make(net.IP, 4)
+	binary.BigEndian.PutUint32(ip, binary.LittleEndian.Uint32(raw))
+	return ip, nil
+}
+
+// dockerInterface reports whether an interface is managed by Docker.
+func dockerInterface(name string) bool {
+	return name == "docker0" || name == "docker_gwbridge" ||
+		strings.HasPrefix(name, "br-") || strings.HasPrefix(name, "veth")
+}
+
+// subnetsOverlap reports whether two subnets share any address.
+func subnetsOverlap(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
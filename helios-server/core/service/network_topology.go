@@ -0,0 +1,135 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+)
+
+// NetworkTopology describes every network with its attached containers, and every
+// container with the networks it is attached to.
+type NetworkTopology struct {
+	Networks   []TopologyNetwork   `json:"networks"`
+	Containers []TopologyContainer `json:"containers"`
+}
+
+// TopologyNetwork is a network and the container endpoints attached to it.
+type TopologyNetwork struct {
+	ID        string             `json:"id"`
+	Name      string             `json:"name"`
+	Driver    string             `json:"driver"`
+	Scope     string             `json:"scope"`
+	Internal  bool               `json:"internal"`
+	Subnets   []string           `json:"subnets,omitempty"`
+	Endpoints []TopologyEndpoint `json:"endpoints"`
+}
+
+// TopologyEndpoint is a container's attachment to a network.
+type TopologyEndpoint struct {
+	ContainerID   string `json:"container_id"`
+	ContainerName string `json:"container_name"`
+	IPv4Address   string `json:"ipv4_address,omitempty"`
+	IPv6Address   string `json:"ipv6_address,omitempty"`
+	MacAddress    string `json:"mac_address,omitempty"`
+}
+
+// TopologyContainer is a container and the IDs of the networks it is attached to.
+type TopologyContainer struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	State      string   `json:"state"`
+	NetworkIDs []string `json:"network_ids"`
+}
+
+// GetTopology builds the network topology from one network list and one container list.
+// Container list entries already carry their network endpoints, so no per-network or
+// per-container inspect is needed. Stopped containers are included; they keep their
+// attachments but have no addresses.
+func (s *NetworkService) GetTopology(ctx context.Context) (*NetworkTopology, error) {
+	networks, err := s.dockerClient.NetworkList(ctx, network.ListOptions{})
+	if err != nil {
+		log.Printf("Failed to list networks for topology: %v", err)
+		return nil, fmt.Errorf("failed to list networks: %w", err)
+	}
+
+	containers, err := s.dockerClient.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		log.Printf("Failed to list containers for topology: %v", err)
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	containers = s.scope.Filter(containers)
+
+	topology := &NetworkTopology{
+		Networks:   make([]TopologyNetwork, 0, len(networks)),
+		Containers: make([]TopologyContainer, 0, len(containers)),
+	}
+
+	byID := make(map[string]int, len(networks))
+	for _, n := range networks {
+		var subnets []string
+		for _, cfg := range n.IPAM.Config {
+			if cfg.Subnet != "" {
+				subnets = append(subnets, cfg.Subnet)
+			}
+		}
+		byID[n.ID] = len(topology.Networks)
+		topology.Networks = append(topology.Networks, TopologyNetwork{
+			ID:        n.ID,
+			Name:      n.Name,
+			Driver:    n.Driver,
+			Scope:     n.Scope,
+			Internal:  n.Internal,
+			Subnets:   subnets,
+			Endpoints: []TopologyEndpoint{},
+		})
+	}
+
+	for _, c := range containers {
+		name := ""
+		if len(c.Names) > 0 {
+			name = containerDisplayName(c.Names[0])
+		}
+
+		entry := TopologyContainer{
+			ID:         c.ID,
+			Name:       name,
+			State:      c.State,
+			NetworkIDs: []string{},
+		}
+
+		if c.NetworkSettings != nil {
+			for _, endpoint := range c.NetworkSettings.Networks {
+				if endpoint == nil {
+					continue
+				}
+				idx, ok := byID[endpoint.NetworkID]
+				if !ok {
+					continue
+				}
+				entry.NetworkIDs = append(entry.NetworkIDs, endpoint.NetworkID)
+				topology.Networks[idx].Endpoints = append(topology.Networks[idx].Endpoints, TopologyEndpoint{
+					ContainerID:   c.ID,
+					ContainerName: name,
+					IPv4Address:   endpoint.IPAddress,
+					IPv6Address:   endpoint.GlobalIPv6Address,
+					MacAddress:    endpoint.MacAddress,
+				})
+			}
+		}
+		sort.Strings(entry.NetworkIDs)
+
+		topology.Containers = append(topology.Containers, entry)
+	}
+
+	for i := range topology.Networks {
+		endpoints := topology.Networks[i].Endpoints
+		sort.Slice(endpoints, func(a, b int) bool { return endpoints[a].ContainerName < endpoints[b].ContainerName })
+	}
+
+	return topology, nil
+}
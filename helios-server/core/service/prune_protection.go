@@ -0,0 +1,247 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"nfcunha/helios/utils/config"
+	"nfcunha/helios/utils/docker"
+
+	"github.com/distribution/reference"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/errdefs"
+)
+
+// ProtectLabel marks resources that no prune operation, manual or automatic, may remove.
+const ProtectLabel = "helios.protect"
+
+// KeepLabel is the label the auto-prune scheduler honoured before ProtectLabel was added.
+// It still protects resources exactly as ProtectLabel does.
+const KeepLabel = "helios.keep"
+
+// protectLabels are the labels that protect a resource from pruning, in reporting order.
+var protectLabels = []string{ProtectLabel, KeepLabel}
+
+// SkippedResource describes a resource a prune operation left in place because it is protected.
+type SkippedResource struct {
+	ID     string `json:"id"`
+	Name   string `json:"name,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// PruneProtection decides whether a resource is covered by the prune allowlist
+// or carries ProtectLabel or KeepLabel. The allowlist can be replaced at runtime.
+type PruneProtection struct {
+	mu       sync.RWMutex
+	images   map[string]bool
+	volumes  map[string]bool
+	networks map[string]bool
+}
+
+// NewPruneProtection creates a prune protection policy from configuration.
+// Image references are normalized, so "nginx" also protects "docker.io/library/nginx:latest".
+func NewPruneProtection(cfg config.PruneProtectConfig) *PruneProtection {
+	p := &PruneProtection{}
+	p.Replace(cfg)
+	return p
+}
+
+// Replace swaps the allowlist for the one in cfg.
+func (p *PruneProtection) Replace(cfg config.PruneProtectConfig) {
+	images := make(map[string]bool)
+	volumes := make(map[string]bool)
+	networks := make(map[string]bool)
+	for _, ref := range cfg.Images {
+		images[normalizeImageRef(ref)] = true
+	}
+	for _, name := range cfg.Volumes {
+		volumes[name] = true
+	}
+	for _, name := range cfg.Networks {
+		networks[name] = true
+	}
+
+	p.mu.Lock()
+	p.images, p.volumes, p.networks = images, volumes, networks
+	p.mu.Unlock()
+}
+
+// Config returns the active allowlist with image references in normalized form.
+func (p *PruneProtection) Config() config.PruneProtectConfig {
+	if p == nil {
+		return config.PruneProtectConfig{}
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return config.PruneProtectConfig{
+		Images:   sortedKeys(p.images),
+		Volumes:  sortedKeys(p.volumes),
+		Networks: sortedKeys(p.networks),
+	}
+}
+
+// ImageReason returns why an image is protected, or "" if it is not.
+func (p *PruneProtection) ImageReason(id string, repoTags []string, labels map[string]string) string {
+	if label := protectLabel(labels); label != "" {
+		return "label " + label
+	}
+	if p == nil {
+		return ""
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.images[id] || p.images[strings.TrimPrefix(id, "sha256:")] {
+		return "allowlisted"
+	}
+	for _, tag := range repoTags {
+		if p.images[normalizeImageRef(tag)] {
+			return "allowlisted"
+		}
+	}
+	return ""
+}
+
+// VolumeReason returns why a volume is protected, or "" if it is not.
+func (p *PruneProtection) VolumeReason(name string, labels map[string]string) string {
+	if label := protectLabel(labels); label != "" {
+		return "label " + label
+	}
+	if p == nil {
+		return ""
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.volumes[name] {
+		return "allowlisted"
+	}
+	return ""
+}
+
+// NetworkReason returns why a network is protected, or "" if it is not.
+func (p *PruneProtection) NetworkReason(id, name string, labels map[string]string) string {
+	if label := protectLabel(labels); label != "" {
+		return "label " + label
+	}
+	if p == nil {
+		return ""
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.networks[name] || p.networks[id] {
+		return "allowlisted"
+	}
+	return ""
+}
+
+// protectLabel returns the protect label a resource carries, regardless of its value,
+// or "" if it carries none.
+func protectLabel(labels map[string]string) string {
+	for _, label := range protectLabels {
+		if _, ok := labels[label]; ok {
+			return label
+		}
+	}
+	return ""
+}
+
+// hasProtectLabel reports whether a resource carries ProtectLabel or KeepLabel.
+func hasProtectLabel(labels map[string]string) bool {
+	return protectLabel(labels) != ""
+}
+
+// pruneStoppedContainers removes stopped containers that carry no protect label and
+// returns their IDs and the space reclaimed. The daemon's "label!" prune filter only
+// skips resources carrying every listed label, so it cannot honour either of two
+// labels; containers are listed and removed here instead.
+func pruneStoppedContainers(ctx context.Context, dockerClient *docker.Client) ([]string, uint64, error) {
+	containers, err := dockerClient.ContainerList(ctx, container.ListOptions{
+		All:  true,
+		Size: true,
+		Filters: filters.NewArgs(
+			filters.Arg("status", "created"),
+			filters.Arg("status", "exited"),
+			filters.Arg("status", "dead"),
+		),
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	deleted := []string{}
+	var reclaimed uint64
+	for _, c := range containers {
+		if hasProtectLabel(c.Labels) {
+			continue
+		}
+		if err := dockerClient.ContainerRemove(ctx, c.ID, container.RemoveOptions{}); err != nil {
+			log.Printf("Failed to remove stopped container %s: %v", ShortID(c.ID), err)
+			continue
+		}
+		deleted = append(deleted, c.ID)
+		reclaimed += uint64(c.SizeRw)
+	}
+	return deleted, reclaimed, nil
+}
+
+// pruneDanglingImages removes dangling images that carry no protect label and returns
+// the deleted image IDs, the space reclaimed and the images kept for their label.
+// Images still used by a container are left in place, as the daemon's prune does.
+func pruneDanglingImages(ctx context.Context, dockerClient *docker.Client) ([]string, uint64, []SkippedResource, error) {
+	images, err := dockerClient.ImageList(ctx, image.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("dangling", "true")),
+	})
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("failed to list images: %w", err)
+	}
+
+	deleted := []string{}
+	skipped := []SkippedResource{}
+	var reclaimed uint64
+	for _, img := range images {
+		if label := protectLabel(img.Labels); label != "" {
+			skipped = append(skipped, SkippedResource{ID: img.ID, Reason: "label " + label})
+			continue
+		}
+		response, err := dockerClient.ImageRemove(ctx, img.ID, image.RemoveOptions{PruneChildren: true})
+		if err != nil {
+			if !errdefs.IsConflict(err) {
+				log.Printf("Failed to remove dangling image %s: %v", ShortID(img.ID), err)
+			}
+			continue
+		}
+		for _, item := range response {
+			if item.Deleted != "" {
+				deleted = append(deleted, item.Deleted)
+			}
+		}
+		reclaimed += uint64(img.Size)
+	}
+	return deleted, reclaimed, skipped, nil
+}
+
+// sortedKeys returns the keys of a set in sorted order; an empty set yields an empty slice.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// normalizeImageRef converts an image reference to its fully-qualified tagged form.
+// References that cannot be parsed are returned unchanged.
+func normalizeImageRef(ref string) string {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return ref
+	}
+	return reference.TagNameOnly(named).String()
+}
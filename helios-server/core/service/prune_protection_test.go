@@ -0,0 +1,58 @@
+package service
+
+import (
+	"testing"
+
+	"nfcunha/helios/utils/config"
+)
+
+func TestPruneProtectionVolumeReason(t *testing.T) {
+	protection := NewPruneProtection(config.PruneProtectConfig{Volumes: []string{"db-data"}})
+
+	tests := []struct {
+		name   string
+		volume string
+		labels map[string]string
+		want   string
+	}{
+		{name: "unprotected", volume: "cache", want: ""},
+		{name: "protect label", volume: "cache", labels: map[string]string{ProtectLabel: ""}, want: "label " + ProtectLabel},
+		{name: "keep label", volume: "cache", labels: map[string]string{KeepLabel: "true"}, want: "label " + KeepLabel},
+		{name: "both labels", volume: "cache", labels: map[string]string{ProtectLabel: "", KeepLabel: ""}, want: "label " + ProtectLabel},
+		{name: "other label", volume: "cache", labels: map[string]string{"helios.other": ""}, want: ""},
+		{name: "allowlisted", volume: "db-data", want: "allowlisted"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := protection.VolumeReason(tt.volume, tt.labels); got != tt.want {
+				t.Errorf("VolumeReason(%q, %v) = %q, want %q", tt.volume, tt.labels, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPruneProtectionImageReason(t *testing.T) {
+	protection := NewPruneProtection(config.PruneProtectConfig{Images: []string{"nginx"}})
+
+	tests := []struct {
+		name     string
+		id       string
+		repoTags []string
+		labels   map[string]string
+		want     string
+	}{
+		{name: "unprotected", id: "sha256:abc", repoTags: []string{"redis:7"}, want: ""},
+		{name: "keep label", id: "sha256:abc", labels: map[string]string{KeepLabel: ""}, want: "label " + KeepLabel},
+		{name: "normalized tag", id: "sha256:abc", repoTags: []string{"docker.io/library/nginx:latest"}, want: "allowlisted"},
+		{name: "other tag", id: "sha256:abc", repoTags: []string{"nginx:1.27"}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := protection.ImageReason(tt.id, tt.repoTags, tt.labels); got != tt.want {
+				t.Errorf("ImageReason(%q, %v, %v) = %q, want %q", tt.id, tt.repoTags, tt.labels, got, tt.want)
+			}
+		})
+	}
+}
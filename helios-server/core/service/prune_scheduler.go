@@ -0,0 +1,216 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"syscall"
+	"time"
+
+	"nfcunha/helios/core/repository"
+	"nfcunha/helios/utils/config"
+	"nfcunha/helios/utils/docker"
+)
+
+// PruneScheduler periodically prunes stopped containers and dangling images.
+type PruneScheduler struct {
+	dockerClient *docker.Client
+	diskService  *DiskService
+	eventLogRepo *repository.EventLogRepository
+	cfg          config.AutoPruneConfig
+	ctx          context.Context
+	cancel       context.CancelFunc
+}
+
+// PruneRunReport summarizes a single automatic prune run.
+type PruneRunReport struct {
+	ContainersDeleted []string `json:"containers_deleted"`
+	ImagesDeleted     []string `json:"images_deleted"`
+	SpaceReclaimed    uint64   `json:"space_reclaimed"`
+	Errors            []string `json:"errors,omitempty"`
+	Skipped           bool     `json:"skipped,omitempty"`
+	SkipReason        string   `json:"skip_reason,omitempty"`
+}
+
+// NewPruneScheduler creates a new prune scheduler and starts the background loop.
+func NewPruneScheduler(dockerClient *docker.Client, diskService *DiskService, eventLogRepo *repository.EventLogRepository, cfg config.AutoPruneConfig) *PruneScheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	scheduler := &PruneScheduler{
+		dockerClient: dockerClient,
+		diskService:  diskService,
+		eventLogRepo: eventLogRepo,
+		cfg:          cfg,
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+
+	go scheduler.loop()
+
+	return scheduler
+}
+
+// loop runs a prune pass at the configured interval until stopped.
+func (p *PruneScheduler) loop() {
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	log.Printf("Auto prune scheduler started (interval: %v, targets: %v)", p.cfg.Interval, p.cfg.Targets)
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+			p.Run(p.ctx)
+		}
+	}
+}
+
+// Run performs a single prune pass over the configured targets.
+// When thresholds are configured the pass is skipped unless one of them is exceeded.
+// Stopped containers are pruned before images so that images they referenced
+// can be reclaimed in the same pass. Resources labelled with ProtectLabel or KeepLabel
+// are skipped, and images still referenced by a container are never removed.
+func (p *PruneScheduler) Run(ctx context.Context) *PruneRunReport {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	report := &PruneRunReport{
+		ContainersDeleted: []string{},
+		ImagesDeleted:     []string{},
+	}
+
+	if reason, skip := p.belowThreshold(ctx); skip {
+		report.Skipped = true
+		report.SkipReason = reason
+		log.Printf("Auto prune skipped: below threshold (%s)", reason)
+		p.logRun(report)
+		return report
+	}
+
+	if p.hasTarget("containers") {
+		deleted, reclaimed, err := pruneStoppedContainers(ctx, p.dockerClient)
+		if err != nil {
+			log.Printf("Auto prune failed for containers: %v", err)
+			report.Errors = append(report.Errors, fmt.Sprintf("containers: %v", err))
+		} else {
+			report.ContainersDeleted = append(report.ContainersDeleted, deleted...)
+			report.SpaceReclaimed += reclaimed
+		}
+	}
+
+	if p.hasTarget("images") {
+		deleted, reclaimed, _, err := pruneDanglingImages(ctx, p.dockerClient)
+		if err != nil {
+			log.Printf("Auto prune failed for images: %v", err)
+			report.Errors = append(report.Errors, fmt.Sprintf("images: %v", err))
+		} else {
+			report.ImagesDeleted = append(report.ImagesDeleted, deleted...)
+			report.SpaceReclaimed += reclaimed
+		}
+	}
+
+	log.Printf("Auto prune completed: %d containers, %d images removed, reclaimed %d bytes",
+		len(report.ContainersDeleted), len(report.ImagesDeleted), report.SpaceReclaimed)
+	p.logRun(report)
+
+	return report
+}
+
+// Stop stops the background prune loop.
+func (p *PruneScheduler) Stop() {
+	p.cancel()
+}
+
+// belowThreshold reports whether neither configured threshold is exceeded, with a
+// description of the measured values. Reclaimable space comes from the same data as
+// the disk usage endpoint. Measurements that fail are logged and treated as exceeded,
+// so a broken check never stops pruning.
+func (p *PruneScheduler) belowThreshold(ctx context.Context) (string, bool) {
+	if p.cfg.MinReclaimable <= 0 && p.cfg.MinDiskPercent <= 0 {
+		return "", false
+	}
+
+	var reasons []string
+
+	if p.cfg.MinReclaimable > 0 {
+		usage, err := p.diskService.GetDiskUsage(ctx)
+		if err != nil {
+			log.Printf("Auto prune threshold check failed, pruning anyway: %v", err)
+			return "", false
+		}
+		var reclaimable int64
+		if p.hasTarget("containers") {
+			reclaimable += usage.Containers.Reclaimable
+		}
+		if p.hasTarget("images") {
+			reclaimable += usage.Images.Reclaimable
+		}
+		if reclaimable >= p.cfg.MinReclaimable {
+			return "", false
+		}
+		reasons = append(reasons, fmt.Sprintf("reclaimable %d of %d bytes", reclaimable, p.cfg.MinReclaimable))
+	}
+
+	if p.cfg.MinDiskPercent > 0 {
+		percent, err := p.dockerDiskPercent(ctx)
+		if err != nil {
+			log.Printf("Auto prune threshold check failed, pruning anyway: %v", err)
+			return "", false
+		}
+		if percent >= p.cfg.MinDiskPercent {
+			return "", false
+		}
+		reasons = append(reasons, fmt.Sprintf("disk usage %.1f%% of %.0f%%", percent, p.cfg.MinDiskPercent))
+	}
+
+	return strings.Join(reasons, ", "), true
+}
+
+// dockerDiskPercent returns how full the filesystem holding the daemon's data root is.
+// The path must be visible to Helios, e.g. by mounting it when running in a container.
+func (p *PruneScheduler) dockerDiskPercent(ctx context.Context) (float64, error) {
+	info, err := p.dockerClient.Info(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get docker info: %w", err)
+	}
+
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(info.DockerRootDir, &stat); err != nil {
+		return 0, fmt.Errorf("failed to stat %s: %w", info.DockerRootDir, err)
+	}
+	if stat.Blocks == 0 {
+		return 0, fmt.Errorf("filesystem at %s reports no blocks", info.DockerRootDir)
+	}
+
+	used := stat.Blocks - stat.Bfree
+	return float64(used) / float64(used+stat.Bavail) * 100, nil
+}
+
+// hasTarget reports whether the given resource type is configured for pruning.
+func (p *PruneScheduler) hasTarget(target string) bool {
+	for _, t := range p.cfg.Targets {
+		if t == target {
+			return true
+		}
+	}
+	return false
+}
+
+// logRun records the prune run in the event log.
+func (p *PruneScheduler) logRun(report *PruneRunReport) {
+	level := "info"
+	if len(report.Errors) > 0 {
+		level = "warning"
+	}
+
+	message := fmt.Sprintf("Auto prune removed %d containers and %d images (%d bytes reclaimed)",
+		len(report.ContainersDeleted), len(report.ImagesDeleted), report.SpaceReclaimed)
+	if report.Skipped {
+		message = fmt.Sprintf("Auto prune skipped: below threshold (%s)", report.SkipReason)
+	}
+
+	p.eventLogRepo.EmitEvent("system", level, message, report)
+}
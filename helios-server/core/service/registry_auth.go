@@ -0,0 +1,148 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"nfcunha/helios/utils/config"
+
+	"github.com/distribution/reference"
+	"github.com/docker/docker/api/types/registry"
+)
+
+// dockerHubHost is the registry host of unqualified image references.
+const dockerHubHost = "docker.io"
+
+// dockerHubServerAddress is the server address Docker Hub credentials are issued for.
+const dockerHubServerAddress = "https://index.docker.io/v1/"
+
+// RegistryCredential holds the auth for one registry. Either Username/Password or
+// Token (a registry bearer token) must be set.
+type RegistryCredential struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Token    string `json:"token,omitempty"`
+}
+
+// RegistryCredentials maps registry hosts to credentials so pulls from known private
+// registries are authenticated automatically. Credentials are never logged.
+type RegistryCredentials struct {
+	byHost map[string]RegistryCredential
+}
+
+// NewRegistryCredentials loads registry credentials from the configured file and inline JSON.
+// Both use the form {"registry.example.com": {"username": "...", "password": "..."}};
+// inline entries override file entries for the same host.
+func NewRegistryCredentials(cfg config.RegistryConfig) (*RegistryCredentials, error) {
+	creds := &RegistryCredentials{byHost: make(map[string]RegistryCredential)}
+
+	if cfg.CredentialsFile != "" {
+		data, err := os.ReadFile(cfg.CredentialsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read registry credentials file: %w", err)
+		}
+		if err := creds.add(data); err != nil {
+			return nil, fmt.Errorf("invalid registry credentials file %s: %w", cfg.CredentialsFile, err)
+		}
+	}
+
+	if cfg.Credentials != "" {
+		if err := creds.add([]byte(cfg.Credentials)); err != nil {
+			return nil, fmt.Errorf("invalid inline registry credentials: %w", err)
+		}
+	}
+
+	if len(creds.byHost) > 0 {
+		log.Printf("Loaded credentials for %d registries", len(creds.byHost))
+	}
+	return creds, nil
+}
+
+// add parses and merges a JSON host-to-credential map.
+func (r *RegistryCredentials) add(data []byte) error {
+	var entries map[string]RegistryCredential
+	if err := json.Unmarshal(data, &entries); err != nil {
+		// Deliberately not wrapping: the decoder error may quote credential values
+		return fmt.Errorf("expected a JSON object mapping registry hosts to credentials")
+	}
+
+	for host, cred := range entries {
+		hasPassword := cred.Username != "" && cred.Password != ""
+		if hasPassword == (cred.Token != "") {
+			return fmt.Errorf("registry %s: set either username and password, or token", host)
+		}
+		r.byHost[normalizeRegistryHost(host)] = cred
+	}
+	return nil
+}
+
+// RegistryAuth returns the encoded auth header value for an image reference's registry,
+// or "" if no credentials are configured for it (or the reference cannot be parsed).
+func (r *RegistryCredentials) RegistryAuth(imageRef string) string {
+	if r == nil || len(r.byHost) == 0 {
+		return ""
+	}
+
+	named, err := reference.ParseNormalizedNamed(imageRef)
+	if err != nil {
+		return ""
+	}
+	host := reference.Domain(named)
+
+	cred, ok := r.byHost[host]
+	if !ok {
+		return ""
+	}
+
+	encoded, err := registry.EncodeAuthConfig(cred.authConfig(host))
+	if err != nil {
+		log.Printf("Failed to encode credentials for registry %s", host)
+		return ""
+	}
+	return encoded
+}
+
+// AuthConfigs returns the auth of every configured registry keyed by server address,
+// as image builds expect, so base images are pulled from private registries.
+func (r *RegistryCredentials) AuthConfigs() map[string]registry.AuthConfig {
+	if r == nil || len(r.byHost) == 0 {
+		return nil
+	}
+
+	configs := make(map[string]registry.AuthConfig, len(r.byHost))
+	for host, cred := range r.byHost {
+		auth := cred.authConfig(host)
+		configs[auth.ServerAddress] = auth
+	}
+	return configs
+}
+
+// authConfig builds the Docker auth for a registry host.
+func (cred RegistryCredential) authConfig(host string) registry.AuthConfig {
+	serverAddress := host
+	if host == dockerHubHost {
+		serverAddress = dockerHubServerAddress
+	}
+	return registry.AuthConfig{
+		Username:      cred.Username,
+		Password:      cred.Password,
+		RegistryToken: cred.Token,
+		ServerAddress: serverAddress,
+	}
+}
+
+// normalizeRegistryHost reduces a configured registry key to the host form used by
+// image references, e.g. "https://index.docker.io/v1/" becomes "docker.io".
+func normalizeRegistryHost(host string) string {
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+	host = strings.ToLower(strings.SplitN(host, "/", 2)[0])
+	switch host {
+	case "index.docker.io", "registry-1.docker.io", "registry.hub.docker.com":
+		return dockerHubHost
+	}
+	return host
+}
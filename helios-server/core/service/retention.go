@@ -0,0 +1,123 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"nfcunha/helios/core/repository"
+	"nfcunha/helios/utils/config"
+)
+
+// RetentionCleaner periodically deletes logs older than the retention period and,
+// if enabled, vacuums the database afterwards so the file shrinks.
+type RetentionCleaner struct {
+	healthCheckRepo *repository.HealthCheckLogRepository
+	actionLogRepo   *repository.ActionLogRepository
+	eventLogRepo    *repository.EventLogRepository
+	vacuum          func() (int64, error)
+	cfg             config.LogRetentionConfig
+	ctx             context.Context
+	cancel          context.CancelFunc
+}
+
+// RetentionReport summarizes a single retention cleanup run.
+type RetentionReport struct {
+	HealthChecksDeleted int64    `json:"health_checks_deleted"`
+	ActionsDeleted      int64    `json:"actions_deleted"`
+	EventsDeleted       int64    `json:"events_deleted"`
+	Vacuumed            bool     `json:"vacuumed"`
+	BytesReclaimed      int64    `json:"bytes_reclaimed"`
+	Errors              []string `json:"errors,omitempty"`
+}
+
+// NewRetentionCleaner creates a retention cleaner and starts the background loop.
+// The vacuum function is called after each run that deleted rows when cfg.Vacuum is set.
+func NewRetentionCleaner(healthCheckRepo *repository.HealthCheckLogRepository, actionLogRepo *repository.ActionLogRepository, eventLogRepo *repository.EventLogRepository, vacuum func() (int64, error), cfg config.LogRetentionConfig) *RetentionCleaner {
+	ctx, cancel := context.WithCancel(context.Background())
+	cleaner := &RetentionCleaner{
+		healthCheckRepo: healthCheckRepo,
+		actionLogRepo:   actionLogRepo,
+		eventLogRepo:    eventLogRepo,
+		vacuum:          vacuum,
+		cfg:             cfg,
+		ctx:             ctx,
+		cancel:          cancel,
+	}
+
+	go cleaner.loop()
+
+	return cleaner
+}
+
+// loop runs a cleanup at startup and then at the configured interval until stopped.
+func (r *RetentionCleaner) loop() {
+	log.Printf("Retention cleaner started (retention: %d days, interval: %v, vacuum: %v)",
+		r.cfg.Days, r.cfg.Interval, r.cfg.Vacuum)
+
+	r.Run()
+
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+			r.Run()
+		}
+	}
+}
+
+// Run deletes expired logs and vacuums the database if configured.
+func (r *RetentionCleaner) Run() *RetentionReport {
+	report := &RetentionReport{}
+	var err error
+
+	if report.HealthChecksDeleted, err = r.healthCheckRepo.DeleteOlderThan(r.cfg.Days); err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("health checks: %v", err))
+	}
+	if report.ActionsDeleted, err = r.actionLogRepo.DeleteOlderThan(r.cfg.Days); err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("actions: %v", err))
+	}
+	if report.EventsDeleted, err = r.eventLogRepo.DeleteOlderThan(r.cfg.Days); err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("events: %v", err))
+	}
+
+	deleted := report.HealthChecksDeleted + report.ActionsDeleted + report.EventsDeleted
+	if r.cfg.Vacuum && deleted > 0 {
+		reclaimed, err := r.vacuum()
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("vacuum: %v", err))
+		} else {
+			report.Vacuumed = true
+			report.BytesReclaimed = reclaimed
+		}
+	}
+
+	log.Printf("Retention cleanup completed: %d health checks, %d actions, %d events deleted, %d bytes reclaimed",
+		report.HealthChecksDeleted, report.ActionsDeleted, report.EventsDeleted, report.BytesReclaimed)
+	r.logRun(report, deleted)
+
+	return report
+}
+
+// Stop stops the background loop.
+func (r *RetentionCleaner) Stop() {
+	r.cancel()
+}
+
+// logRun records the cleanup in the event log.
+func (r *RetentionCleaner) logRun(report *RetentionReport, deleted int64) {
+	level := "info"
+	if len(report.Errors) > 0 {
+		level = "warning"
+	}
+
+	r.eventLogRepo.EmitEvent("system", level,
+		fmt.Sprintf("Retention cleanup deleted %d log entries (%d bytes reclaimed)", deleted, report.BytesReclaimed),
+		report)
+}
@@ -0,0 +1,178 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"time"
+
+	"nfcunha/helios/utils/config"
+)
+
+// SettingsVersion is the format version written by ExportSettings.
+const SettingsVersion = 1
+
+// ErrInvalidSettings indicates that imported settings failed validation.
+var ErrInvalidSettings = errors.New("invalid settings")
+
+// Settings is the portable form of the Helios state that can be changed at runtime.
+// Sections left out of an import are not touched.
+type Settings struct {
+	Version      int                   `json:"version"`
+	ExportedAt   time.Time             `json:"exported_at,omitempty"`
+	HealthCheck  *HealthCheckSettings  `json:"health_check,omitempty"`
+	PruneProtect *PruneProtectSettings `json:"prune_protect,omitempty"`
+}
+
+// HealthCheckSettings mirrors config.HealthCheckConfig with a readable interval.
+type HealthCheckSettings struct {
+	Enabled         bool    `json:"enabled"`
+	Interval        string  `json:"interval"` // Go duration, e.g. "30s"
+	CPUThreshold    float64 `json:"cpu_threshold"`
+	MemoryThreshold float64 `json:"memory_threshold"`
+	BreachCount     int     `json:"breach_count"`
+	RecoveryCount   int     `json:"recovery_count"`
+}
+
+// PruneProtectSettings mirrors config.PruneProtectConfig.
+type PruneProtectSettings struct {
+	Images   []string `json:"images"`
+	Volumes  []string `json:"volumes"`
+	Networks []string `json:"networks"`
+}
+
+// SettingsConflict describes an imported value that differs from the active one.
+type SettingsConflict struct {
+	Field    string      `json:"field"`
+	Current  interface{} `json:"current"`
+	Imported interface{} `json:"imported"`
+}
+
+// SettingsImportResult reports what an import changed, or would change on a dry run.
+type SettingsImportResult struct {
+	Applied   bool               `json:"applied"`
+	Conflicts []SettingsConflict `json:"conflicts"`
+}
+
+// SettingsService exports and imports runtime settings so a monitoring
+// configuration can be copied to another Helios instance.
+// Imported settings are applied in memory; environment variables win again on restart.
+type SettingsService struct {
+	healthChecker *HealthChecker
+	protection    *PruneProtection
+}
+
+// NewSettingsService creates a new settings service.
+func NewSettingsService(healthChecker *HealthChecker, protection *PruneProtection) *SettingsService {
+	return &SettingsService{
+		healthChecker: healthChecker,
+		protection:    protection,
+	}
+}
+
+// ExportSettings returns the active settings.
+func (s *SettingsService) ExportSettings() *Settings {
+	health := s.healthChecker.Config()
+	protect := s.protection.Config()
+
+	return &Settings{
+		Version:    SettingsVersion,
+		ExportedAt: time.Now(),
+		HealthCheck: &HealthCheckSettings{
+			Enabled:         health.Enabled,
+			Interval:        health.Interval.String(),
+			CPUThreshold:    health.CPUThreshold,
+			MemoryThreshold: health.MemoryThreshold,
+			BreachCount:     health.BreachCount,
+			RecoveryCount:   health.RecoveryCount,
+		},
+		PruneProtect: &PruneProtectSettings{
+			Images:   protect.Images,
+			Volumes:  protect.Volumes,
+			Networks: protect.Networks,
+		},
+	}
+}
+
+// ImportSettings validates settings and applies them unless dryRun is set.
+// Every value that differs from the active one is reported as a conflict; the
+// imported value wins when applied. Nothing is applied if any section is invalid.
+func (s *SettingsService) ImportSettings(settings *Settings, dryRun bool) (*SettingsImportResult, error) {
+	if settings.Version != SettingsVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d (expected %d)", ErrInvalidSettings, settings.Version, SettingsVersion)
+	}
+
+	result := &SettingsImportResult{Conflicts: []SettingsConflict{}}
+
+	var health *config.HealthCheckConfig
+	if settings.HealthCheck != nil {
+		interval, err := time.ParseDuration(settings.HealthCheck.Interval)
+		if err != nil {
+			return nil, fmt.Errorf("%w: health_check.interval: %v", ErrInvalidSettings, err)
+		}
+		health = &config.HealthCheckConfig{
+			Enabled:         settings.HealthCheck.Enabled,
+			Interval:        interval,
+			CPUThreshold:    settings.HealthCheck.CPUThreshold,
+			MemoryThreshold: settings.HealthCheck.MemoryThreshold,
+			BreachCount:     settings.HealthCheck.BreachCount,
+			RecoveryCount:   settings.HealthCheck.RecoveryCount,
+		}
+		if err := config.ValidateHealthCheck(*health); err != nil {
+			return nil, fmt.Errorf("%w: health_check: %v", ErrInvalidSettings, err)
+		}
+
+		current := s.healthChecker.Config()
+		result.addConflict("health_check.enabled", current.Enabled, health.Enabled)
+		result.addConflict("health_check.interval", current.Interval.String(), health.Interval.String())
+		result.addConflict("health_check.cpu_threshold", current.CPUThreshold, health.CPUThreshold)
+		result.addConflict("health_check.memory_threshold", current.MemoryThreshold, health.MemoryThreshold)
+		result.addConflict("health_check.breach_count", current.BreachCount, health.BreachCount)
+		result.addConflict("health_check.recovery_count", current.RecoveryCount, health.RecoveryCount)
+	}
+
+	var protect *config.PruneProtectConfig
+	if settings.PruneProtect != nil {
+		protect = &config.PruneProtectConfig{
+			Images:   settings.PruneProtect.Images,
+			Volumes:  settings.PruneProtect.Volumes,
+			Networks: settings.PruneProtect.Networks,
+		}
+
+		// Compare in normalized form so "nginx" matches "docker.io/library/nginx:latest"
+		current := s.protection.Config()
+		imported := NewPruneProtection(*protect).Config()
+		result.addConflict("prune_protect.images", current.Images, imported.Images)
+		result.addConflict("prune_protect.volumes", current.Volumes, imported.Volumes)
+		result.addConflict("prune_protect.networks", current.Networks, imported.Networks)
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	if health != nil {
+		s.healthChecker.Reconfigure(*health)
+	}
+	if protect != nil {
+		s.protection.Replace(*protect)
+	}
+	result.Applied = true
+
+	log.Printf("Settings imported (%d changed values)", len(result.Conflicts))
+	return result, nil
+}
+
+// addConflict records a conflict when the imported value differs from the current one.
+func (r *SettingsImportResult) addConflict(field string, current, imported interface{}) {
+	if reflect.DeepEqual(current, imported) {
+		return
+	}
+	r.Conflicts = append(r.Conflicts, SettingsConflict{
+		Field:    field,
+		Current:  current,
+		Imported: imported,
+	})
+}
@@ -3,29 +3,51 @@ package service
 
 import (
 	"context"
+	"hash/fnv"
 	"log"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"nfcunha/helios/utils/metrics"
+
 	"github.com/docker/docker/api/types/container"
 )
 
+// statsAverageWindow is the number of refresh cycles averaged into StatsAverage (30s at 3s per cycle).
+const statsAverageWindow = 10
+
+// StatsSampleLabel sets how many refresh cycles pass between stats samples of a
+// container; "1" samples it every cycle. Between samples its last stats are served.
+const StatsSampleLabel = "helios.stats.every"
+
 // StatsCache manages cached container statistics with background refresh.
 type StatsCache struct {
 	containerService *ContainerService
 	containerStats   map[string]*ContainerStats // containerID -> stats
+	containerNames   map[string]string          // containerID -> name, for sampled containers
+	history          map[string]*statsHistory   // containerID -> recent readings; only touched by refresh
+	sampleEvery      int                        // Default cycles between samples
+	cycle            int                        // Refresh cycle counter; only touched by refresh
 	dashboardSummary *DashboardSummary
+	warm             bool        // Set once the first refresh has completed
+	refreshing       atomic.Bool // Set while a refresh is in progress
 	mu               sync.RWMutex
 	ctx              context.Context
 	cancel           context.CancelFunc
 }
 
 // NewStatsCache creates a new stats cache and starts background refresh.
-func NewStatsCache(containerService *ContainerService) *StatsCache {
+// Containers are sampled every sampleEvery refresh cycles unless labelled with StatsSampleLabel.
+func NewStatsCache(containerService *ContainerService, sampleEvery int) *StatsCache {
 	ctx, cancel := context.WithCancel(context.Background())
 	cache := &StatsCache{
 		containerService: containerService,
 		containerStats:   make(map[string]*ContainerStats),
+		containerNames:   make(map[string]string),
+		history:          make(map[string]*statsHistory),
+		sampleEvery:      max(sampleEvery, 1),
 		ctx:              ctx,
 		cancel:           cancel,
 	}
@@ -43,6 +65,13 @@ func (c *StatsCache) GetContainerStats(containerID string) *ContainerStats {
 	return c.containerStats[containerID]
 }
 
+// IsWarm reports whether the cache has completed at least one refresh.
+func (c *StatsCache) IsWarm() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.warm
+}
+
 // GetAllContainerStats returns all cached container stats.
 func (c *StatsCache) GetAllContainerStats() map[string]*ContainerStats {
 	c.mu.RLock()
@@ -56,6 +85,18 @@ func (c *StatsCache) GetAllContainerStats() map[string]*ContainerStats {
 	return result
 }
 
+// GetContainerNames returns the names of all containers with cached stats.
+func (c *StatsCache) GetContainerNames() map[string]string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make(map[string]string, len(c.containerNames))
+	for id, name := range c.containerNames {
+		result[id] = name
+	}
+	return result
+}
+
 // GetDashboardSummary returns cached dashboard summary.
 func (c *StatsCache) GetDashboardSummary() *DashboardSummary {
 	c.mu.RLock()
@@ -71,9 +112,11 @@ func (c *StatsCache) GetDashboardSummary() *DashboardSummary {
 }
 
 // refreshLoop continuously refreshes stats in the background.
+// Refreshes run off the ticker goroutine; a tick that fires while the previous
+// refresh is still running (e.g. because the daemon is slow) is skipped.
 func (c *StatsCache) refreshLoop() {
 	// Initial refresh
-	c.refresh()
+	c.tryRefresh()
 
 	ticker := time.NewTicker(3 * time.Second)
 	defer ticker.Stop()
@@ -83,13 +126,28 @@ func (c *StatsCache) refreshLoop() {
 		case <-c.ctx.Done():
 			return
 		case <-ticker.C:
-			c.refresh()
+			go c.tryRefresh()
 		}
 	}
 }
 
+// tryRefresh runs a refresh unless one is already in progress.
+func (c *StatsCache) tryRefresh() {
+	if !c.refreshing.CompareAndSwap(false, true) {
+		log.Println("Skipping stats refresh: previous refresh still in progress")
+		metrics.SkipStatsRefresh()
+		return
+	}
+	defer c.refreshing.Store(false)
+
+	c.refresh()
+}
+
 // refresh fetches fresh stats and updates the cache.
 func (c *StatsCache) refresh() {
+	start := time.Now()
+	defer func() { metrics.ObserveStatsRefresh(time.Since(start)) }()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -101,26 +159,53 @@ func (c *StatsCache) refresh() {
 		log.Printf("Failed to list containers for stats cache: %v", err)
 		return
 	}
+	containers = c.containerService.scope.Filter(containers)
 
 	if len(containers) == 0 {
+		c.history = make(map[string]*statsHistory)
 		c.mu.Lock()
 		c.containerStats = make(map[string]*ContainerStats)
+		c.containerNames = make(map[string]string)
 		c.dashboardSummary = &DashboardSummary{}
+		c.warm = true
 		c.mu.Unlock()
 		return
 	}
 
-	// Fetch stats for all containers in parallel
+	names := make(map[string]string, len(containers))
+	for _, container := range containers {
+		if len(container.Names) > 0 {
+			names[container.ID] = containerDisplayName(container.Names[0])
+		}
+	}
+
+	// Containers not due for a sample this cycle keep their previous stats
+	c.cycle++
+	c.mu.RLock()
+	previous := c.containerStats
+	c.mu.RUnlock()
+
+	newStats := make(map[string]*ContainerStats)
+	var due []string
+	for _, container := range containers {
+		if stats, ok := previous[container.ID]; ok && !c.sampleDue(container.ID, container.Labels) {
+			newStats[container.ID] = stats
+			continue
+		}
+		due = append(due, container.ID)
+	}
+
+	// Fetch stats for due containers in parallel
 	type statsResult struct {
 		containerID string
 		stats       *ContainerStats
 		err         error
 	}
 
-	statsChan := make(chan statsResult, len(containers))
+	statsChan := make(chan statsResult, len(due))
 	var wg sync.WaitGroup
 
-	for _, container := range containers {
+	for _, containerID := range due {
 		wg.Add(1)
 		go func(containerID string) {
 			defer wg.Done()
@@ -130,7 +215,7 @@ func (c *StatsCache) refresh() {
 				stats:       stats,
 				err:         err,
 			}
-		}(container.ID)
+		}(containerID)
 	}
 
 	// Wait and close channel
@@ -140,9 +225,6 @@ func (c *StatsCache) refresh() {
 	}()
 
 	// Collect results
-	newStats := make(map[string]*ContainerStats)
-	summary := &DashboardSummary{}
-
 	for result := range statsChan {
 		if result.err != nil {
 			log.Printf("Failed to get stats for container %s: %v", result.containerID, result.err)
@@ -150,30 +232,103 @@ func (c *StatsCache) refresh() {
 		}
 
 		if result.stats != nil {
+			result.stats.Average = c.average(result.containerID, result.stats)
 			newStats[result.containerID] = result.stats
-
-			// Aggregate for dashboard
-			summary.TotalCPUPercent += result.stats.CPUPercent
-			summary.TotalMemoryUsage += result.stats.MemoryUsage
-			summary.TotalMemoryLimit += result.stats.MemoryLimit
-			summary.TotalNetworkRx += result.stats.NetworkRx
-			summary.TotalNetworkTx += result.stats.NetworkTx
-			summary.ContainerCount++
 		}
 	}
 
+	// Aggregate for dashboard
+	summary := &DashboardSummary{}
+	for _, stats := range newStats {
+		summary.TotalCPUPercent += stats.CPUPercent
+		summary.TotalMemoryUsage += stats.MemoryUsage
+		summary.TotalMemoryLimit += stats.MemoryLimit
+		summary.TotalNetworkRx += stats.NetworkRx
+		summary.TotalNetworkTx += stats.NetworkTx
+		summary.ContainerCount++
+	}
+
 	// Calculate average memory percentage
 	if summary.TotalMemoryLimit > 0 {
 		summary.TotalMemoryPercent = (float64(summary.TotalMemoryUsage) / float64(summary.TotalMemoryLimit)) * 100.0
 	}
 
+	// Forget readings of containers that stopped; a failed fetch keeps the history
+	for id := range c.history {
+		if _, running := names[id]; !running {
+			delete(c.history, id)
+		}
+	}
+
 	// Update cache
 	c.mu.Lock()
 	c.containerStats = newStats
+	c.containerNames = names
 	c.dashboardSummary = summary
+	c.warm = true
 	c.mu.Unlock()
 }
 
+// sampleDue reports whether a container is due for a stats sample this cycle.
+// Containers are spread over the cycles by ID so that they are not all sampled together.
+func (c *StatsCache) sampleDue(containerID string, labels map[string]string) bool {
+	every := c.sampleEvery
+	if raw, ok := labels[StatsSampleLabel]; ok {
+		if n, err := strconv.Atoi(raw); err == nil && n >= 1 {
+			every = n
+		}
+	}
+	if every == 1 {
+		return true
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(containerID))
+	return (c.cycle+int(h.Sum32()%uint32(every)))%every == 0
+}
+
+// statsHistory holds the most recent CPU and memory readings of a container.
+type statsHistory struct {
+	cpu    []float64
+	memory []*float64 // Nil for readings without a memory limit
+}
+
+// average records a reading and returns the moving average over the last
+// statsAverageWindow readings, including this one.
+func (c *StatsCache) average(containerID string, stats *ContainerStats) *StatsAverage {
+	h, ok := c.history[containerID]
+	if !ok {
+		h = &statsHistory{}
+		c.history[containerID] = h
+	}
+
+	// Readings without a memory limit carry no memory percentage; they still count as
+	// samples, and the memory average covers those that have one
+	h.cpu = append(h.cpu, stats.CPUPercent)
+	h.memory = append(h.memory, stats.MemoryPercent)
+	if len(h.cpu) > statsAverageWindow {
+		h.cpu = h.cpu[len(h.cpu)-statsAverageWindow:]
+		h.memory = h.memory[len(h.memory)-statsAverageWindow:]
+	}
+
+	avg := &StatsAverage{Samples: len(h.cpu)}
+	var memoryTotal float64
+	memorySamples := 0
+	for i := range h.cpu {
+		avg.CPUPercent += h.cpu[i]
+		if h.memory[i] != nil {
+			memoryTotal += *h.memory[i]
+			memorySamples++
+		}
+	}
+	avg.CPUPercent /= float64(avg.Samples)
+	if memorySamples > 0 {
+		memoryAverage := memoryTotal / float64(memorySamples)
+		avg.MemoryPercent = &memoryAverage
+	}
+	return avg
+}
+
 // Stop stops the background refresh loop.
 func (c *StatsCache) Stop() {
 	c.cancel()
@@ -5,6 +5,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
+	"sync"
 	"time"
 
 	"nfcunha/helios/core/models"
@@ -19,13 +21,20 @@ import (
 type VolumeService struct {
 	dockerClient  *docker.Client
 	actionLogRepo *repository.ActionLogRepository
+	usageRepo     *repository.VolumeUsageRepository
+	protection    *PruneProtection
+	labels        *ResourceLabels
 }
 
 // NewVolumeService creates a new volume service.
-func NewVolumeService(dockerClient *docker.Client, actionLogRepo *repository.ActionLogRepository) *VolumeService {
+// Created volumes are stamped with the given resource labels.
+func NewVolumeService(dockerClient *docker.Client, actionLogRepo *repository.ActionLogRepository, usageRepo *repository.VolumeUsageRepository, protection *PruneProtection, labels *ResourceLabels) *VolumeService {
 	return &VolumeService{
 		dockerClient:  dockerClient,
 		actionLogRepo: actionLogRepo,
+		usageRepo:     usageRepo,
+		protection:    protection,
+		labels:        labels,
 	}
 }
 
@@ -144,7 +153,7 @@ func (s *VolumeService) CreateVolume(ctx context.Context, req *CreateVolumeReque
 		Name:       req.Name,
 		Driver:     driver,
 		DriverOpts: req.DriverOpts,
-		Labels:     req.Labels,
+		Labels:     s.labels.Apply(req.Labels),
 	}
 
 	vol, err := s.dockerClient.VolumeCreate(ctx, createOptions)
@@ -186,23 +195,92 @@ func (s *VolumeService) RemoveVolume(ctx context.Context, volumeName string, for
 
 	log.Printf("Successfully removed volume: %s", volumeName)
 	s.logAction("remove", "volume", volumeName, volumeName, true, nil)
+	if err := s.usageRepo.Delete(volumeName); err != nil {
+		log.Printf("Failed to delete cached usage of volume %s: %v", volumeName, err)
+	}
 	return nil
 }
 
+// BulkRemoveVolumes removes multiple volumes in parallel. Volumes mounted by a
+// running container are reported as failed, naming the containers, even with force.
+// If progress is non-nil, each result is also sent to it as soon as it completes.
+func (s *VolumeService) BulkRemoveVolumes(ctx context.Context, volumeNames []string, force bool, progress chan<- BulkOperationResult) []BulkOperationResult {
+	results := make([]BulkOperationResult, len(volumeNames))
+
+	// Map each volume to the running containers mounting it
+	usedBy := make(map[string][]string)
+	containers, err := s.dockerClient.ContainerList(ctx, container.ListOptions{})
+	if err != nil {
+		// The daemon still refuses to remove volumes in use
+		log.Printf("Failed to list running containers for bulk volume removal: %v", err)
+	}
+	for _, c := range containers {
+		name := ShortID(c.ID)
+		if len(c.Names) > 0 {
+			name = containerDisplayName(c.Names[0])
+		}
+		for _, mount := range c.Mounts {
+			if mount.Type == "volume" && mount.Name != "" {
+				usedBy[mount.Name] = append(usedBy[mount.Name], name)
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i, volumeName := range volumeNames {
+		wg.Add(1)
+		go func(i int, volumeName string) {
+			defer wg.Done()
+
+			result := BulkOperationResult{
+				ContainerID:   volumeName,
+				ContainerName: volumeName,
+				Success:       true,
+			}
+
+			if users := usedBy[volumeName]; len(users) > 0 {
+				result.Success = false
+				result.Error = fmt.Sprintf("volume is in use by running containers: %s", strings.Join(users, ", "))
+			} else if err := s.RemoveVolume(ctx, volumeName, force); err != nil {
+				result.Success = false
+				result.Error = err.Error()
+			}
+
+			results[i] = result
+			reportBulkResult(progress, result)
+		}(i, volumeName)
+	}
+	wg.Wait()
+
+	return results
+}
+
 // PruneVolumes removes unused volumes and their associated stopped containers.
-func (s *VolumeService) PruneVolumes(ctx context.Context, pruneFilters map[string][]string) (uint64, []string, error) {
+// Volumes covered by the prune allowlist or labelled with ProtectLabel or KeepLabel are kept
+// and returned as skipped.
+func (s *VolumeService) PruneVolumes(ctx context.Context, pruneFilters map[string][]string) (uint64, []string, []SkippedResource, error) {
 	// Get all volumes
 	volumeList, err := s.dockerClient.VolumeList(ctx, volume.ListOptions{})
 	if err != nil {
 		log.Printf("Failed to list volumes for pruning: %v", err)
-		return 0, nil, fmt.Errorf("failed to list volumes: %w", err)
+		return 0, nil, nil, fmt.Errorf("failed to list volumes: %w", err)
 	}
 
 	// Get all containers (including stopped)
 	containers, err := s.dockerClient.ContainerList(ctx, container.ListOptions{All: true})
 	if err != nil {
 		log.Printf("Failed to list containers for volume pruning: %v", err)
-		return 0, nil, fmt.Errorf("failed to list containers: %w", err)
+		return 0, nil, nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	// Protected volumes are treated like volumes in use
+	skipped := []SkippedResource{}
+	protected := make(map[string]bool)
+	for _, vol := range volumeList.Volumes {
+		if reason := s.protection.VolumeReason(vol.Name, vol.Labels); reason != "" {
+			protected[vol.Name] = true
+			skipped = append(skipped, SkippedResource{ID: vol.Name, Name: vol.Name, Reason: reason})
+		}
 	}
 
 	// Build a map of volumes used by running containers
@@ -220,10 +298,10 @@ func (s *VolumeService) PruneVolumes(ctx context.Context, pruneFilters map[strin
 	// Remove stopped containers that use volumes not used by running containers
 	removedContainers := 0
 	for _, c := range containers {
-		if c.State != "running" {
+		if c.State != "running" && !hasProtectLabel(c.Labels) {
 			shouldRemove := false
 			for _, mount := range c.Mounts {
-				if mount.Type == "volume" && mount.Name != "" && !usedByRunning[mount.Name] {
+				if mount.Type == "volume" && mount.Name != "" && !usedByRunning[mount.Name] && !protected[mount.Name] {
 					shouldRemove = true
 					break
 				}
@@ -234,7 +312,7 @@ func (s *VolumeService) PruneVolumes(ctx context.Context, pruneFilters map[strin
 					log.Printf("Failed to remove stopped container %s: %v", c.ID, err)
 				} else {
 					removedContainers++
-					log.Printf("Removed stopped container %s for volume cleanup", c.ID[:12])
+					log.Printf("Removed stopped container %s for volume cleanup", ShortID(c.ID))
 				}
 			}
 		}
@@ -247,8 +325,8 @@ func (s *VolumeService) PruneVolumes(ctx context.Context, pruneFilters map[strin
 	var totalReclaimed uint64 = 0
 
 	for _, vol := range volumeList.Volumes {
-		// Skip volumes used by running containers
-		if usedByRunning[vol.Name] {
+		// Skip volumes used by running containers or protected from pruning
+		if usedByRunning[vol.Name] || protected[vol.Name] {
 			continue
 		}
 
@@ -265,9 +343,9 @@ func (s *VolumeService) PruneVolumes(ctx context.Context, pruneFilters map[strin
 		}
 	}
 
-	log.Printf("Pruned %d volumes, reclaimed space: %d bytes", len(removedVolumes), totalReclaimed)
+	log.Printf("Pruned %d volumes (%d protected), reclaimed space: %d bytes", len(removedVolumes), len(skipped), totalReclaimed)
 	s.logAction("prune", "volume", "all", "all", true, nil)
-	return totalReclaimed, removedVolumes, nil
+	return totalReclaimed, removedVolumes, skipped, nil
 }
 
 // logAction logs an action to the database.
@@ -0,0 +1,71 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"nfcunha/helios/core/models"
+
+	"github.com/docker/docker/api/types"
+)
+
+// ErrVolumeUsageNotComputed is returned when a volume's usage has not been computed yet.
+var ErrVolumeUsageNotComputed = errors.New("volume usage has not been computed yet")
+
+// ErrVolumeNotFound is returned when a volume does not exist.
+var ErrVolumeNotFound = errors.New("volume not found")
+
+// RefreshVolumeUsage computes the size of a volume, like `docker system df -v`, and
+// caches it with the time of computation. The daemon walks every volume to answer,
+// so this is kept out of the list endpoint.
+func (s *VolumeService) RefreshVolumeUsage(ctx context.Context, volumeName string) (*models.VolumeUsage, error) {
+	usage, err := s.dockerClient.DiskUsage(ctx, types.DiskUsageOptions{
+		Types: []types.DiskUsageObject{types.VolumeObject},
+	})
+	if err != nil {
+		log.Printf("Failed to compute usage of volume %s: %v", volumeName, err)
+		return nil, fmt.Errorf("failed to compute volume usage: %w", err)
+	}
+
+	for _, vol := range usage.Volumes {
+		if vol.Name != volumeName {
+			continue
+		}
+		result := &models.VolumeUsage{
+			VolumeName: volumeName,
+			Size:       -1,
+			RefCount:   -1,
+			ComputedAt: time.Now(),
+		}
+		if vol.UsageData != nil {
+			result.Size = vol.UsageData.Size
+			result.RefCount = vol.UsageData.RefCount
+		}
+		if err := s.usageRepo.Save(result); err != nil {
+			log.Printf("Failed to store usage of volume %s: %v", volumeName, err)
+			return nil, fmt.Errorf("failed to store volume usage: %w", err)
+		}
+		log.Printf("Computed usage of volume %s: %d bytes", volumeName, result.Size)
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrVolumeNotFound, volumeName)
+}
+
+// GetVolumeUsage returns the last computed usage of a volume, or
+// ErrVolumeUsageNotComputed if it has never been refreshed.
+func (s *VolumeService) GetVolumeUsage(volumeName string) (*models.VolumeUsage, error) {
+	usage, ok, err := s.usageRepo.Get(volumeName)
+	if err != nil {
+		log.Printf("Failed to get usage of volume %s: %v", volumeName, err)
+		return nil, fmt.Errorf("failed to get volume usage: %w", err)
+	}
+	if !ok {
+		return nil, ErrVolumeUsageNotComputed
+	}
+	return usage, nil
+}
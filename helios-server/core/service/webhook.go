@@ -0,0 +1,76 @@
+// Package service provides business logic for Docker resource management.
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"nfcunha/helios/utils/config"
+)
+
+// WebhookEvent is the JSON body posted to the configured webhook.
+type WebhookEvent struct {
+	Event     string      `json:"event"`
+	Message   string      `json:"message"`
+	Data      interface{} `json:"data,omitempty"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+// WebhookNotifier posts notable events to an external HTTP endpoint.
+// A notifier without a URL (or a nil notifier) silently drops events.
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a webhook notifier from configuration.
+func NewWebhookNotifier(cfg config.WebhookConfig) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    cfg.URL,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// Notify posts an event to the webhook in the background.
+// Delivery failures are logged and never block the caller.
+func (w *WebhookNotifier) Notify(event, message string, data interface{}) {
+	if w == nil || w.url == "" {
+		return
+	}
+
+	payload := WebhookEvent{
+		Event:     event,
+		Message:   message,
+		Data:      data,
+		Timestamp: time.Now(),
+	}
+
+	go func() {
+		if err := w.post(payload); err != nil {
+			log.Printf("Failed to deliver %s webhook: %v", event, err)
+		}
+	}()
+}
+
+// post sends a single webhook payload.
+func (w *WebhookNotifier) post(payload WebhookEvent) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
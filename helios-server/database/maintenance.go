@@ -0,0 +1,46 @@
+// Package database provides maintenance operations for the Helios database.
+package database
+
+import (
+	"errors"
+	"log"
+)
+
+// Size returns the size of the database file in bytes, as reported by SQLite.
+func Size() (int64, error) {
+	if db == nil {
+		return 0, errors.New("database not initialized")
+	}
+
+	var pageCount, pageSize int64
+	if err := db.QueryRow("PRAGMA page_count").Scan(&pageCount); err != nil {
+		return 0, err
+	}
+	if err := db.QueryRow("PRAGMA page_size").Scan(&pageSize); err != nil {
+		return 0, err
+	}
+	return pageCount * pageSize, nil
+}
+
+// Vacuum rebuilds the database file to release space freed by deleted rows.
+// VACUUM holds an exclusive lock for its duration, so writes block until it completes.
+// Returns the number of bytes reclaimed.
+func Vacuum() (int64, error) {
+	before, err := Size()
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := db.Exec("VACUUM"); err != nil {
+		log.Printf("Failed to vacuum database: %v", err)
+		return 0, err
+	}
+
+	after, err := Size()
+	if err != nil {
+		return 0, err
+	}
+
+	log.Printf("Database vacuumed: %d -> %d bytes", before, after)
+	return before - after, nil
+}
@@ -2,11 +2,14 @@
 package database
 
 import (
+	"database/sql"
+	"fmt"
 	"log"
 )
 
 // migrate runs all database migrations to create the schema.
-// Creates tables for health check logs, action logs, and event logs.
+// Creates tables for health check logs, action logs, event logs, drained containers,
+// cached volume usage and container templates.
 //
 // Returns an error if any migration fails.
 func migrate() error {
@@ -73,6 +76,46 @@ CREATE INDEX IF NOT EXISTS idx_event_logs_level ON event_logs(level);
 CREATE INDEX IF NOT EXISTS idx_event_logs_created_at ON event_logs(created_at);
 			`,
 		},
+		{
+			name: "create_drained_containers_table",
+			sql: `
+CREATE TABLE IF NOT EXISTS drained_containers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    drain_id TEXT NOT NULL,
+    container_id TEXT NOT NULL,
+    container_name TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    drained_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    restored_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_drained_containers_drain ON drained_containers(drain_id);
+CREATE INDEX IF NOT EXISTS idx_drained_containers_restored_at ON drained_containers(restored_at);
+			`,
+		},
+		{
+			name: "create_volume_usage_table",
+			sql: `
+CREATE TABLE IF NOT EXISTS volume_usage (
+    volume_name TEXT PRIMARY KEY,
+    size INTEGER NOT NULL,
+    ref_count INTEGER NOT NULL,
+    computed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+			`,
+		},
+		{
+			name: "create_templates_table",
+			sql: `
+CREATE TABLE IF NOT EXISTS templates (
+    name TEXT PRIMARY KEY,
+    description TEXT,
+    spec TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+			`,
+		},
 	}
 
 	for _, migration := range migrations {
@@ -84,5 +127,63 @@ CREATE INDEX IF NOT EXISTS idx_event_logs_created_at ON event_logs(created_at);
 		log.Printf("Migration completed: %s", migration.name)
 	}
 
+	// Columns added after a table was first created
+	columns := []struct {
+		table      string
+		column     string
+		definition string
+	}{
+		{table: "health_check_logs", column: "published_ports", definition: "TEXT"},
+		{table: "health_check_logs", column: "trigger", definition: "TEXT"},
+		{table: "health_check_logs", column: "cpu_threshold", definition: "REAL"},
+		{table: "health_check_logs", column: "memory_threshold", definition: "REAL"},
+	}
+
+	for _, c := range columns {
+		if err := addColumnIfMissing(c.table, c.column, c.definition); err != nil {
+			log.Printf("Migration failed for %s.%s: %v", c.table, c.column, err)
+			return err
+		}
+	}
+
 	return nil
 }
+
+// addColumnIfMissing adds a column to an existing table unless it is already present.
+func addColumnIfMissing(table, column, definition string) error {
+	exists, err := hasColumn(table, column)
+	if err != nil || exists {
+		return err
+	}
+
+	log.Printf("Running migration: add %s.%s", table, column)
+	_, err = db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	return err
+}
+
+// hasColumn reports whether a table has a column with the given name.
+func hasColumn(table, column string) (bool, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid       int
+			name      string
+			colType   string
+			notNull   int
+			dfltValue sql.NullString
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk); err != nil {
+			return false, err
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
@@ -0,0 +1,205 @@
+// Package handler provides HTTP request handlers.
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"nfcunha/helios/core/models"
+	"nfcunha/helios/core/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActionLogHandler handles action log HTTP requests.
+type ActionLogHandler struct {
+	actionLogRepo *repository.ActionLogRepository
+}
+
+// NewActionLogHandler creates a new action log handler.
+func NewActionLogHandler(actionLogRepo *repository.ActionLogRepository) *ActionLogHandler {
+	return &ActionLogHandler{
+		actionLogRepo: actionLogRepo,
+	}
+}
+
+var errInvalidRange = errors.New("'to' must not be before 'from'")
+
+// ListActionLogs handles GET /logs/actions
+// Query parameters:
+//   - from: RFC3339 timestamp, only include actions executed at or after this time
+//   - to: RFC3339 timestamp, only include actions executed at or before this time (default: now)
+//   - action_type: string (e.g. "remove")
+//   - resource_type: string (container, image, volume, network)
+//   - resource_id: string
+//   - success: boolean (only successful or only failed actions)
+//   - limit: maximum number of entries (default: 100, max: 1000)
+//   - offset: number of entries to skip (default: 0)
+func (h *ActionLogHandler) ListActionLogs(c *gin.Context) {
+	filter, ok := actionLogFilter(c)
+	if !ok {
+		return
+	}
+	filter.ResourceType = c.Query("resource_type")
+	filter.ResourceID = c.Query("resource_id")
+
+	h.queryActionLogs(c, filter)
+}
+
+// ListResourceActionLogs handles GET /logs/actions/:resourceType/:resourceId
+// Lists the actions executed on a single resource, newest first.
+// Query parameters:
+//   - from, to, action_type, success, limit, offset: as for ListActionLogs
+func (h *ActionLogHandler) ListResourceActionLogs(c *gin.Context) {
+	filter, ok := actionLogFilter(c)
+	if !ok {
+		return
+	}
+	filter.ResourceType = c.Param("resourceType")
+	filter.ResourceID = c.Param("resourceId")
+
+	h.queryActionLogs(c, filter)
+}
+
+// queryActionLogs runs the filter and writes a page of action logs with the total count.
+func (h *ActionLogHandler) queryActionLogs(c *gin.Context, filter repository.ActionLogFilter) {
+	logs, total, err := h.actionLogRepo.Query(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":  "Failed to query action logs",
+			"detail": err.Error(),
+		})
+		return
+	}
+	if logs == nil {
+		logs = []*models.ActionLog{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"logs":   logs,
+		"count":  len(logs),
+		"total":  total,
+		"limit":  filter.Limit,
+		"offset": filter.Offset,
+	})
+}
+
+// actionLogFilter builds a filter from the from, to, action_type, success, limit and
+// offset query parameters. On invalid input it writes a 400 response and returns false.
+func actionLogFilter(c *gin.Context) (repository.ActionLogFilter, bool) {
+	from, to, err := parseTimeRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Invalid time range",
+			"detail": err.Error(),
+		})
+		return repository.ActionLogFilter{}, false
+	}
+
+	filter := repository.ActionLogFilter{
+		ActionType: c.Query("action_type"),
+		From:       from,
+		To:         to,
+	}
+
+	if v := c.Query("success"); v != "" {
+		success, err := strconv.ParseBool(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":  "Invalid success filter",
+				"detail": "Query parameter 'success' must be true or false",
+			})
+			return repository.ActionLogFilter{}, false
+		}
+		filter.Success = &success
+	}
+
+	filter.Limit, filter.Offset = parsePagination(c, 100, 1000)
+	return filter, true
+}
+
+// SummarizeActionLogs handles GET /logs/actions/summary
+// Returns action counts grouped by resource type and action type.
+// Query parameters:
+//   - from: RFC3339 timestamp, only count actions executed at or after this time
+//   - to: RFC3339 timestamp, only count actions executed at or before this time (default: now)
+func (h *ActionLogHandler) SummarizeActionLogs(c *gin.Context) {
+	from, to, err := parseTimeRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Invalid time range",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	counts, err := h.actionLogRepo.CountByResource(from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":  "Failed to summarize action logs",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	total := 0
+	for _, count := range counts {
+		total += count.Count
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"summary": counts,
+		"total":   total,
+		"from":    from,
+		"to":      to,
+	})
+}
+
+// parseTimeRange reads the from/to query parameters as RFC3339 timestamps.
+// A missing from means the beginning of time and a missing to means now.
+func parseTimeRange(c *gin.Context) (time.Time, time.Time, error) {
+	var from time.Time
+	to := time.Now()
+
+	if v := c.Query("from"); v != "" {
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return from, to, err
+		}
+		from = parsed
+	}
+
+	if v := c.Query("to"); v != "" {
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return from, to, err
+		}
+		to = parsed
+	}
+
+	if to.Before(from) {
+		return from, to, errInvalidRange
+	}
+
+	return from, to, nil
+}
+
+// parsePagination reads the limit/offset query parameters, applying defaults and bounds.
+func parsePagination(c *gin.Context, defaultLimit, maxLimit int) (int, int) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultLimit)))
+	if err != nil || limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	return limit, offset
+}
@@ -0,0 +1,76 @@
+// Package handler provides HTTP request handlers.
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"nfcunha/helios/core/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bulkRunner runs a bulk operation, optionally reporting each result on progress.
+type bulkRunner func(progress chan<- service.BulkOperationResult) []service.BulkOperationResult
+
+// bulkSummary builds the aggregate response body for a finished bulk operation.
+type bulkSummary func(results []service.BulkOperationResult) gin.H
+
+// respondBulk runs a bulk operation and writes its results.
+// By default a single aggregate JSON response is sent once all items finish. With
+// ?stream=true or "Accept: application/x-ndjson", each result is written as an NDJSON
+// line as soon as it finishes, followed by a final summary line with "done": true.
+func respondBulk(c *gin.Context, run bulkRunner, summary bulkSummary) {
+	if c.Query("stream") != "true" && !strings.Contains(c.GetHeader("Accept"), "application/x-ndjson") {
+		c.JSON(http.StatusOK, summary(run(nil)))
+		return
+	}
+
+	progress := make(chan service.BulkOperationResult)
+	done := make(chan []service.BulkOperationResult, 1)
+	go func() {
+		results := run(progress)
+		close(progress)
+		done <- results
+	}()
+
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Header("Cache-Control", "no-cache")
+	c.Status(http.StatusOK)
+
+	// Keep draining after a write error so the operation is never blocked
+	encoder := json.NewEncoder(c.Writer)
+	for result := range progress {
+		if err := encoder.Encode(result); err == nil {
+			c.Writer.Flush()
+		}
+	}
+
+	final := summary(<-done)
+	final["done"] = true
+	delete(final, "results")
+	if err := encoder.Encode(final); err == nil {
+		c.Writer.Flush()
+	}
+}
+
+// containerBulkSummary builds the response body for bulk container operations.
+func containerBulkSummary(results []service.BulkOperationResult) gin.H {
+	return gin.H{
+		"results": results,
+		"total":   len(results),
+		"success": countSuccessful(results),
+		"failed":  countFailed(results),
+	}
+}
+
+// resourceBulkSummary builds the response body for bulk image, network and volume operations.
+func resourceBulkSummary(results []service.BulkOperationResult) gin.H {
+	return gin.H{
+		"results":    results,
+		"total":      len(results),
+		"successful": countSuccessful(results),
+		"failed":     countFailed(results),
+	}
+}
@@ -2,8 +2,12 @@
 package handler
 
 import (
+	"errors"
+	"io"
 	"net/http"
+	"regexp"
 	"strconv"
+	"time"
 
 	"nfcunha/helios/core/service"
 
@@ -22,12 +26,58 @@ func NewContainerHandler(containerService *service.ContainerService) *ContainerH
 	}
 }
 
+// ResolveContainer is middleware for /containers/:id routes that resolves the :id
+// parameter, which may be a container name, full ID or partial ID, to a full ID.
+// Ambiguous partial IDs are rejected with 409 Conflict listing the candidates.
+func (h *ContainerHandler) ResolveContainer(c *gin.Context) {
+	ref := c.Param("id")
+
+	ctx, cancel := requestContext(c, timeouts.Default)
+	defer cancel()
+
+	containerID, err := h.containerService.ResolveContainer(ctx, ref)
+	if err != nil {
+		var ambiguous *service.AmbiguousContainerError
+		switch {
+		case errors.As(err, &ambiguous):
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+				"error":      "Ambiguous container reference",
+				"detail":     err.Error(),
+				"candidates": ambiguous.Candidates,
+			})
+		case errors.Is(err, service.ErrContainerNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error":  "Container not found",
+				"detail": err.Error(),
+			})
+		default:
+			c.AbortWithStatusJSON(errorStatus(err, http.StatusInternalServerError), gin.H{
+				"error":  "Failed to resolve container",
+				"detail": err.Error(),
+			})
+		}
+		return
+	}
+
+	for i := range c.Params {
+		if c.Params[i].Key == "id" {
+			c.Params[i].Value = containerID
+		}
+	}
+	c.Next()
+}
+
 // ListContainers handles GET /helios/containers
 // Query parameters:
 //   - all: boolean (include stopped containers)
 //   - limit: integer (max number of results)
 //   - filter: string (filter by name)
 //   - stats: boolean (include resource stats - default true)
+//   - fetch_stats: boolean (fetch stats directly while the stats cache is still warming up)
+//   - stats_mode: string ("instant" - default, or "average" to add moving averages of CPU and memory)
+//   - exited: string ("failed" lists stopped containers with a non-zero exit code, most recently finished first)
+//   - created_since: duration or RFC3339 time (only containers created since, e.g. 10m)
+//   - started_since: duration or RFC3339 time (only containers started since, most recently started first)
 func (h *ContainerHandler) ListContainers(c *gin.Context) {
 	// Default to including stats, but allow disabling for performance
 	includeStats := c.Query("stats") != "false"
@@ -35,6 +85,19 @@ func (h *ContainerHandler) ListContainers(c *gin.Context) {
 	opts := service.ContainerListOptions{
 		All:          c.Query("all") == "true",
 		IncludeStats: includeStats,
+		FetchStats:   c.Query("fetch_stats") == "true",
+	}
+
+	switch mode := c.DefaultQuery("stats_mode", "instant"); mode {
+	case "instant":
+	case "average":
+		opts.StatsAverage = true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Invalid stats mode",
+			"detail": "Query parameter 'stats_mode' must be 'instant' or 'average'",
+		})
+		return
 	}
 
 	if limitStr := c.Query("limit"); limitStr != "" {
@@ -47,9 +110,40 @@ func (h *ContainerHandler) ListContainers(c *gin.Context) {
 		opts.Filter = filter
 	}
 
+	for param, target := range map[string]*time.Time{
+		"created_since": &opts.CreatedSince,
+		"started_since": &opts.StartedSince,
+	} {
+		raw := c.Query(param)
+		if raw == "" {
+			continue
+		}
+		since, err := parseSince(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":  "Invalid " + param,
+				"detail": err.Error(),
+			})
+			return
+		}
+		*target = since
+	}
+
+	if exited := c.Query("exited"); exited != "" {
+		if exited != "failed" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":  "Invalid exited filter",
+				"detail": "Query parameter 'exited' must be 'failed'",
+			})
+			return
+		}
+		h.listFailedContainers(c, opts)
+		return
+	}
+
 	containers, err := h.containerService.ListContainers(c.Request.Context(), opts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
 			"error":  "Failed to list containers",
 			"detail": err.Error(),
 		})
@@ -62,6 +156,84 @@ func (h *ContainerHandler) ListContainers(c *gin.Context) {
 	})
 }
 
+// parseSince converts a relative duration ("10m" meaning ten minutes ago) or an
+// RFC3339 timestamp to an absolute time.
+func parseSince(raw string) (time.Time, error) {
+	if d, err := time.ParseDuration(raw); err == nil {
+		if d < 0 {
+			return time.Time{}, errors.New("duration must not be negative")
+		}
+		return time.Now().Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, errors.New("must be a duration such as 10m or an RFC3339 timestamp")
+	}
+	return t, nil
+}
+
+// listFailedContainers responds with stopped containers that exited non-zero.
+func (h *ContainerHandler) listFailedContainers(c *gin.Context, opts service.ContainerListOptions) {
+	ctx, cancel := requestContext(c, timeouts.Default)
+	defer cancel()
+
+	containers, err := h.containerService.ListFailedContainers(ctx, opts)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
+			"error":  "Failed to list failed containers",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"containers": containers,
+		"count":      len(containers),
+	})
+}
+
+// SearchContainers handles GET /helios/containers/search
+// Query parameters:
+//   - env: string (environment variable as KEY or KEY=value)
+//   - label: string (label as key or key=value)
+//   - all: boolean (include stopped containers)
+//   - limit: integer (max number of results, default 50, max 200)
+func (h *ContainerHandler) SearchContainers(c *gin.Context) {
+	opts := service.ContainerSearchOptions{
+		Env:   c.Query("env"),
+		Label: c.Query("label"),
+		All:   c.Query("all") == "true",
+	}
+
+	if opts.Env == "" && opts.Label == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Missing search criteria",
+			"detail": "Query parameter 'env' or 'label' is required",
+		})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	opts.Limit = limit
+
+	containers, err := h.containerService.SearchContainers(c.Request.Context(), opts)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
+			"error":  "Failed to search containers",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"containers": containers,
+		"count":      len(containers),
+	})
+}
+
 // GetContainer handles GET /helios/containers/:id
 func (h *ContainerHandler) GetContainer(c *gin.Context) {
 	containerID := c.Param("id")
@@ -74,7 +246,7 @@ func (h *ContainerHandler) GetContainer(c *gin.Context) {
 
 	container, err := h.containerService.GetContainer(c.Request.Context(), containerID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
+		c.JSON(errorStatus(err, http.StatusNotFound), gin.H{
 			"error":  "Container not found",
 			"detail": err.Error(),
 		})
@@ -84,6 +256,318 @@ func (h *ContainerHandler) GetContainer(c *gin.Context) {
 	c.JSON(http.StatusOK, container)
 }
 
+// ContainerReady handles GET /helios/containers/:id/ready
+// Reports whether the container is running, passing its Docker healthcheck (if defined)
+// and, optionally, has logged a line matching a pattern since it started.
+// Query parameters:
+//   - log_pattern: string (regular expression a log line must match)
+//   - timeout: duration (wait up to this long for readiness, e.g. "30s"; capped at the bulk timeout)
+func (h *ContainerHandler) ContainerReady(c *gin.Context) {
+	containerID := c.Param("id")
+
+	var pattern *regexp.Regexp
+	if raw := c.Query("log_pattern"); raw != "" {
+		compiled, err := regexp.Compile(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":  "Invalid log_pattern",
+				"detail": err.Error(),
+			})
+			return
+		}
+		pattern = compiled
+	}
+
+	var wait time.Duration
+	if raw := c.Query("timeout"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil || parsed < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":  "Invalid timeout",
+				"detail": "timeout must be a non-negative duration such as 30s",
+			})
+			return
+		}
+		wait = min(parsed, timeouts.Bulk)
+	}
+
+	ctx, cancel := requestContext(c, wait+timeouts.Default)
+	defer cancel()
+	extendWriteDeadline(c, wait+timeouts.Default)
+
+	readiness, err := h.containerService.CheckReady(ctx, containerID, pattern, wait)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
+			"error":  "Failed to check container readiness",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, readiness)
+}
+
+// WaitHealthy handles GET /helios/containers/:id/wait-healthy
+// Long-polls until the container's Docker healthcheck reports healthy or the timeout
+// elapses, and returns the final status. Fails with 400 when no healthcheck is defined.
+// Query parameters:
+//   - timeout: duration (default: "60s"; capped at the bulk timeout)
+func (h *ContainerHandler) WaitHealthy(c *gin.Context) {
+	containerID := c.Param("id")
+
+	wait := 60 * time.Second
+	if raw := c.Query("timeout"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil || parsed < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":  "Invalid timeout",
+				"detail": "timeout must be a non-negative duration such as 60s",
+			})
+			return
+		}
+		wait = parsed
+	}
+	wait = min(wait, timeouts.Bulk)
+
+	ctx, cancel := requestContext(c, wait+timeouts.Default)
+	defer cancel()
+	extendWriteDeadline(c, wait+timeouts.Default)
+
+	result, err := h.containerService.WaitHealthy(ctx, containerID, wait)
+	if err != nil {
+		if errors.Is(err, service.ErrNoHealthcheck) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":  "Container has no healthcheck",
+				"detail": err.Error(),
+			})
+			return
+		}
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
+			"error":  "Failed to wait for container health",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// DiffContainerEnv handles GET /helios/containers/:id/env/diff
+// Splits the container's environment into added, overridden and inherited variables
+// relative to its image. Sensitive values are redacted.
+func (h *ContainerHandler) DiffContainerEnv(c *gin.Context) {
+	ctx, cancel := requestContext(c, timeouts.Default)
+	defer cancel()
+
+	diff, err := h.containerService.DiffContainerEnv(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
+			"error":  "Failed to diff container environment",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// CreateContainer handles POST /helios/containers
+// Creates a container from a local image, starting it when "start" is true.
+// A name held by another container is rejected with 409 Conflict identifying it,
+// unless on_conflict says otherwise or auto_name is set, in which case the next free
+// name (web-2, web-3, ...) is used and returned as the container's name.
+func (h *ContainerHandler) CreateContainer(c *gin.Context) {
+	var req service.CreateContainerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Invalid request body",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := requestContext(c, timeouts.Default)
+	defer cancel()
+
+	info, err := h.containerService.CreateContainer(ctx, req)
+	if err != nil {
+		respondCreateError(c, info, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, info)
+}
+
+// RenameContainer handles POST /helios/containers/:id/rename
+// Request body: {"name": "new-name"}
+func (h *ContainerHandler) RenameContainer(c *gin.Context) {
+	containerID := c.Param("id")
+
+	var req struct {
+		Name string `json:"name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Invalid request body",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := requestContext(c, timeouts.Default)
+	defer cancel()
+
+	info, err := h.containerService.RenameContainer(ctx, containerID, req.Name)
+	if err != nil {
+		var conflict *service.NameConflictError
+		switch {
+		case errors.Is(err, service.ErrInvalidContainerName):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":  "Invalid container name",
+				"detail": err.Error(),
+			})
+		case errors.As(err, &conflict):
+			c.JSON(http.StatusConflict, gin.H{
+				"error":    "Container name already in use",
+				"detail":   err.Error(),
+				"conflict": conflict,
+			})
+		default:
+			c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
+				"error":  "Failed to rename container",
+				"detail": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, info)
+}
+
+// UpdateContainerResources handles PATCH /helios/containers/:id/resources
+// Changes memory, CPU and restart policy settings in place, without recreating the
+// container. Fields left out keep their current value.
+// Request body: {"memory": 536870912, "memory_swap": -1, "nano_cpus": 1500000000, "cpu_shares": 512, "restart_policy": "unless-stopped"}
+func (h *ContainerHandler) UpdateContainerResources(c *gin.Context) {
+	containerID := c.Param("id")
+
+	var req service.UpdateResourcesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Invalid request body",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := requestContext(c, timeouts.Default)
+	defer cancel()
+
+	resources, err := h.containerService.UpdateContainerResources(ctx, containerID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidResources) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":  "Invalid resource limits",
+				"detail": err.Error(),
+			})
+			return
+		}
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
+			"error":  "Failed to update container resources",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resources)
+}
+
+// ExecContainer handles POST /helios/containers/:id/exec
+// Runs a one-off command and returns its combined output and exit code once it exits.
+// Output beyond HELIOS_EXEC_MAX_OUTPUT_KB is dropped and the result marked truncated.
+// Request body: {"cmd": ["ls", "-la"], "tty": false, "user": "", "working_dir": ""}
+func (h *ContainerHandler) ExecContainer(c *gin.Context) {
+	containerID := c.Param("id")
+
+	var req struct {
+		Cmd        []string `json:"cmd" binding:"required,min=1"`
+		Tty        bool     `json:"tty"`
+		User       string   `json:"user"`
+		WorkingDir string   `json:"working_dir"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Invalid request body",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := requestContext(c, timeouts.Bulk)
+	defer cancel()
+
+	result, err := h.containerService.ExecContainer(ctx, containerID, req.Cmd, service.ExecOptions{
+		Tty:        req.Tty,
+		User:       req.User,
+		WorkingDir: req.WorkingDir,
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrContainerNotRunning) {
+			status = http.StatusConflict
+		}
+		c.JSON(errorStatus(err, status), gin.H{
+			"error":  "Failed to run command in container",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// respondCreateError writes the response for a failed container creation.
+func respondCreateError(c *gin.Context, info *service.ContainerInfo, err error) {
+	var conflict *service.NameConflictError
+	var mounts service.MountValidationErrors
+	switch {
+	case errors.As(err, &conflict):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":    "Container name already in use",
+			"detail":   err.Error(),
+			"conflict": conflict,
+		})
+	case errors.As(err, &mounts):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Invalid mounts",
+			"detail": err.Error(),
+			"mounts": mounts,
+		})
+	case errors.Is(err, service.ErrInvalidContainerSpec), errors.Is(err, service.ErrReplaceNotConfirmed):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Invalid container specification",
+			"detail": err.Error(),
+		})
+	case errors.Is(err, service.ErrImageNotPresent):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":  "Image not found locally",
+			"detail": err.Error(),
+		})
+	case info != nil:
+		// Created, but the start failed
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
+			"error":     "Failed to start container",
+			"detail":    err.Error(),
+			"container": info,
+		})
+	default:
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
+			"error":  "Failed to create container",
+			"detail": err.Error(),
+		})
+	}
+}
+
 // StartContainer handles POST /helios/containers/:id/start
 func (h *ContainerHandler) StartContainer(c *gin.Context) {
 	containerID := c.Param("id")
@@ -96,7 +580,7 @@ func (h *ContainerHandler) StartContainer(c *gin.Context) {
 
 	err := h.containerService.StartContainer(c.Request.Context(), containerID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
 			"error":  "Failed to start container",
 			"detail": err.Error(),
 		})
@@ -110,6 +594,8 @@ func (h *ContainerHandler) StartContainer(c *gin.Context) {
 }
 
 // StopContainer handles POST /helios/containers/:id/stop
+// Query parameters:
+//   - disable_restart: boolean (set the restart policy to "no" before stopping)
 func (h *ContainerHandler) StopContainer(c *gin.Context) {
 	containerID := c.Param("id")
 	if containerID == "" {
@@ -119,19 +605,79 @@ func (h *ContainerHandler) StopContainer(c *gin.Context) {
 		return
 	}
 
-	err := h.containerService.StopContainer(c.Request.Context(), containerID)
+	opts := service.StopOptions{
+		DisableRestart: c.Query("disable_restart") == "true",
+	}
+
+	result, err := h.containerService.StopContainerWithOptions(c.Request.Context(), containerID, opts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
 			"error":  "Failed to stop container",
 			"detail": err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Container stopped successfully",
-		"id":      containerID,
-	})
+	response := gin.H{
+		"message":        "Container stopped successfully",
+		"id":             containerID,
+		"restart_policy": result.RestartPolicy,
+	}
+	if result.RestartPolicyDisabled {
+		response["previous_restart_policy"] = result.PreviousRestartPolicy
+	}
+	if result.Warning != "" {
+		response["warning"] = result.Warning
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetContainerStats handles GET /helios/containers/:id/stats
+// Returns the container's cached stats with their sample time; stats are only fetched
+// live when the cache has no entry yet.
+func (h *ContainerHandler) GetContainerStats(c *gin.Context) {
+	containerID := c.Param("id")
+
+	ctx, cancel := requestContext(c, timeouts.Default)
+	defer cancel()
+
+	snapshot, err := h.containerService.GetContainerStats(ctx, containerID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrContainerNotRunning) {
+			status = http.StatusNotFound
+		}
+		c.JSON(errorStatus(err, status), gin.H{
+			"error":  "Failed to get container stats",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, snapshot)
+}
+
+// BreakRestartLoop handles POST /helios/containers/:id/break-loop
+// Sets the restart policy to "no" and stops the container.
+func (h *ContainerHandler) BreakRestartLoop(c *gin.Context) {
+	containerID := c.Param("id")
+	if containerID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Container ID is required",
+		})
+		return
+	}
+
+	result, err := h.containerService.BreakRestartLoop(c.Request.Context(), containerID)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
+			"error":  "Failed to break restart loop",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
 }
 
 // RestartContainer handles POST /helios/containers/:id/restart
@@ -146,7 +692,7 @@ func (h *ContainerHandler) RestartContainer(c *gin.Context) {
 
 	err := h.containerService.RestartContainer(c.Request.Context(), containerID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
 			"error":  "Failed to restart container",
 			"detail": err.Error(),
 		})
@@ -159,6 +705,89 @@ func (h *ContainerHandler) RestartContainer(c *gin.Context) {
 	})
 }
 
+// UpdateContainer handles POST /helios/containers/:id/update
+// Pulls the container's image and recreates it if a newer image was fetched.
+// Progress is streamed as Server-Sent Events, followed by a final "complete" or "error" event.
+func (h *ContainerHandler) UpdateContainer(c *gin.Context) {
+	containerID := c.Param("id")
+	if containerID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Container ID is required",
+		})
+		return
+	}
+
+	ctx, cancel := requestContext(c, timeouts.Pull)
+	defer cancel()
+	op := startOperation(operationUpdate, containerID, cancel)
+	defer op.done()
+
+	progressChan, resultChan, errChan, err := h.containerService.UpdateContainer(ctx, containerID)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
+			"error":  "Failed to start container update",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+	c.Writer.Header().Set("Transfer-Encoding", "chunked")
+	shutdown, done := trackSSEStream()
+	defer done()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case progress, ok := <-progressChan:
+			if !ok {
+				// Pull finished, wait for the recreate outcome
+				progressChan = nil
+				op.setProgress("recreating container")
+				return true
+			}
+			op.setProgress(pullProgressSummary(progress))
+			c.SSEvent("progress", progress)
+			return true
+
+		case result, ok := <-resultChan:
+			if ok && result != nil {
+				c.SSEvent("complete", result)
+				return false
+			}
+			resultChan = nil
+			return true
+
+		case err, ok := <-errChan:
+			if ok && err != nil {
+				c.SSEvent("error", gin.H{
+					"error": err.Error(),
+				})
+				return false
+			}
+			errChan = nil
+			return progressChan != nil || resultChan != nil
+
+		case <-shutdown:
+			c.SSEvent("shutdown", gin.H{
+				"message": shutdownMessage,
+			})
+			return false
+
+		case <-ctx.Done():
+			message := "Update operation timed out"
+			if op.cancelled() {
+				message = "Update operation cancelled"
+			}
+			c.SSEvent("error", gin.H{
+				"error": message,
+			})
+			return false
+		}
+	})
+}
+
 // RemoveContainer handles DELETE /helios/containers/:id
 // Query parameters:
 //   - force: boolean (force removal of running container)
@@ -175,7 +804,7 @@ func (h *ContainerHandler) RemoveContainer(c *gin.Context) {
 
 	err := h.containerService.RemoveContainer(c.Request.Context(), containerID, force)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
 			"error":  "Failed to remove container",
 			"detail": err.Error(),
 		})
@@ -189,9 +818,16 @@ func (h *ContainerHandler) RemoveContainer(c *gin.Context) {
 }
 
 // BulkStartContainers handles POST /helios/containers/bulk/start
+// Containers start in dependency order, read from Compose depends_on labels and the
+// optional "dependencies" map (container -> containers it depends on); the response
+// includes the resolved order as levels of container names.
+// Query parameters:
+//   - stream: boolean (emit each result as an NDJSON line as it finishes)
+//   - wait_timeout: duration (how long to wait for each dependency to be running and healthy - default 60s)
 func (h *ContainerHandler) BulkStartContainers(c *gin.Context) {
 	var req struct {
-		ContainerIDs []string `json:"container_ids" binding:"required"`
+		ContainerIDs []string            `json:"container_ids" binding:"required"`
+		Dependencies map[string][]string `json:"dependencies"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -202,17 +838,37 @@ func (h *ContainerHandler) BulkStartContainers(c *gin.Context) {
 		return
 	}
 
-	results := h.containerService.BulkStartContainers(c.Request.Context(), req.ContainerIDs)
+	opts := service.BulkStartOptions{
+		Dependencies: req.Dependencies,
+		WaitTimeout:  time.Minute,
+	}
+	if raw := c.Query("wait_timeout"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil || parsed < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":  "Invalid wait_timeout",
+				"detail": "wait_timeout must be a non-negative duration such as 60s",
+			})
+			return
+		}
+		opts.WaitTimeout = parsed
+	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"results": results,
-		"total":   len(req.ContainerIDs),
-		"success": countSuccessful(results),
-		"failed":  countFailed(results),
+	var order [][]string
+	respondBulk(c, func(progress chan<- service.BulkOperationResult) []service.BulkOperationResult {
+		var results []service.BulkOperationResult
+		results, order = h.containerService.BulkStartContainers(c.Request.Context(), req.ContainerIDs, opts, progress)
+		return results
+	}, func(results []service.BulkOperationResult) gin.H {
+		summary := containerBulkSummary(results)
+		summary["order"] = order
+		return summary
 	})
 }
 
 // BulkStopContainers handles POST /helios/containers/bulk/stop
+// Query parameters:
+//   - stream: boolean (emit each result as an NDJSON line as it finishes)
 func (h *ContainerHandler) BulkStopContainers(c *gin.Context) {
 	var req struct {
 		ContainerIDs []string `json:"container_ids" binding:"required"`
@@ -226,17 +882,14 @@ func (h *ContainerHandler) BulkStopContainers(c *gin.Context) {
 		return
 	}
 
-	results := h.containerService.BulkStopContainers(c.Request.Context(), req.ContainerIDs)
-
-	c.JSON(http.StatusOK, gin.H{
-		"results": results,
-		"total":   len(req.ContainerIDs),
-		"success": countSuccessful(results),
-		"failed":  countFailed(results),
-	})
+	respondBulk(c, func(progress chan<- service.BulkOperationResult) []service.BulkOperationResult {
+		return h.containerService.BulkStopContainers(c.Request.Context(), req.ContainerIDs, progress)
+	}, containerBulkSummary)
 }
 
 // BulkRemoveContainers handles POST /helios/containers/bulk/remove
+// Query parameters:
+//   - stream: boolean (emit each result as an NDJSON line as it finishes)
 func (h *ContainerHandler) BulkRemoveContainers(c *gin.Context) {
 	var req struct {
 		ContainerIDs []string `json:"container_ids" binding:"required"`
@@ -251,14 +904,9 @@ func (h *ContainerHandler) BulkRemoveContainers(c *gin.Context) {
 		return
 	}
 
-	results := h.containerService.BulkRemoveContainers(c.Request.Context(), req.ContainerIDs, req.Force)
-
-	c.JSON(http.StatusOK, gin.H{
-		"results": results,
-		"total":   len(req.ContainerIDs),
-		"success": countSuccessful(results),
-		"failed":  countFailed(results),
-	})
+	respondBulk(c, func(progress chan<- service.BulkOperationResult) []service.BulkOperationResult {
+		return h.containerService.BulkRemoveContainers(c.Request.Context(), req.ContainerIDs, req.Force, progress)
+	}, containerBulkSummary)
 }
 
 func countSuccessful(results []service.BulkOperationResult) int {
@@ -281,11 +929,50 @@ func countFailed(results []service.BulkOperationResult) int {
 	return count
 }
 
+// TopContainers handles GET /helios/containers/top
+// Ranks running containers by resource usage from the stats cache.
+// Query parameters:
+//   - by: string (cpu, memory or network, default cpu)
+//   - order: string (desc or asc, default desc)
+//   - limit: integer (max number of results, default 10, max 100)
+func (h *ContainerHandler) TopContainers(c *gin.Context) {
+	order := c.DefaultQuery("order", "desc")
+	if order != "desc" && order != "asc" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Invalid order",
+			"detail": "Query parameter 'order' must be 'asc' or 'desc'",
+		})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	by := c.DefaultQuery("by", service.TopByCPU)
+	containers, err := h.containerService.TopContainers(by, order == "asc", limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Invalid metric",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"containers": containers,
+		"count":      len(containers),
+		"by":         by,
+		"order":      order,
+	})
+}
+
 // GetDashboardSummary handles GET /helios/dashboard/summary
 func (h *ContainerHandler) GetDashboardSummary(c *gin.Context) {
 	summary, err := h.containerService.GetDashboardSummary(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
 			"error":  "Failed to get dashboard summary",
 			"detail": err.Error(),
 		})
@@ -0,0 +1,54 @@
+// Package handler provides HTTP request handlers.
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"nfcunha/helios/core/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CostHandler handles cost estimate HTTP requests.
+type CostHandler struct {
+	costEstimator *service.CostEstimator
+}
+
+// NewCostHandler creates a new cost handler.
+func NewCostHandler(costEstimator *service.CostEstimator) *CostHandler {
+	return &CostHandler{
+		costEstimator: costEstimator,
+	}
+}
+
+// GetCosts handles GET /helios/costs
+// Estimates per-container cost from recorded health check readings using the
+// configured cost model.
+// Query parameters:
+//   - from: RFC3339 timestamp (default: 24 hours before to)
+//   - to: RFC3339 timestamp (default: now)
+func (h *CostHandler) GetCosts(c *gin.Context) {
+	from, to, err := parseTimeRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Invalid time range",
+			"detail": err.Error(),
+		})
+		return
+	}
+	if c.Query("from") == "" {
+		from = to.Add(-24 * time.Hour)
+	}
+
+	report, err := h.costEstimator.EstimateCosts(from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":  "Failed to estimate costs",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
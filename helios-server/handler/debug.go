@@ -0,0 +1,77 @@
+// Package handler provides HTTP handlers for the Helios API.
+package handler
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"log"
+	"net/http"
+	"strings"
+
+	"nfcunha/helios/utils/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireAdminToken only lets requests through that carry "Authorization: Bearer <token>".
+// With no token configured, admin endpoints are disabled.
+func RequireAdminToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":  "Admin endpoints are disabled",
+				"detail": "Set HELIOS_ADMIN_TOKEN to enable them",
+			})
+			return
+		}
+
+		provided, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid or missing admin token",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// DebugHandler exposes Helios internals for self-monitoring.
+type DebugHandler struct {
+	db *sql.DB
+}
+
+// NewDebugHandler creates a new debug handler.
+func NewDebugHandler(db *sql.DB) *DebugHandler {
+	return &DebugHandler{db: db}
+}
+
+// GetStats handles GET /helios/debug/stats
+// Returns goroutine and memory usage, active streaming connections, stats cache refresh
+// timings, database query latencies and connection pool counters.
+func (h *DebugHandler) GetStats(c *gin.Context) {
+	dbStats := h.db.Stats()
+
+	c.JSON(http.StatusOK, gin.H{
+		"helios": metrics.Collect(),
+		"db_pool": gin.H{
+			"open_connections": dbStats.OpenConnections,
+			"in_use":           dbStats.InUse,
+			"idle":             dbStats.Idle,
+			"wait_count":       dbStats.WaitCount,
+			"wait_duration_ms": dbStats.WaitDuration.Milliseconds(),
+		},
+	})
+}
+
+// Metrics handles GET /helios/debug/metrics
+// Serves Helios internals and the latest health check result of every container in the
+// Prometheus text exposition format, so Prometheus can alert on Helios's own assessments.
+func (h *DebugHandler) Metrics(c *gin.Context) {
+	c.Header("Content-Type", metrics.PrometheusContentType)
+	c.Status(http.StatusOK)
+	if err := metrics.WritePrometheus(c.Writer); err != nil {
+		log.Printf("Failed to write metrics: %v", err)
+	}
+}
@@ -0,0 +1,200 @@
+// Package handler provides HTTP handlers for the Helios API.
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"nfcunha/helios/core/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gorilla/websocket"
+)
+
+// Deploy phases reported to the client, in order.
+const (
+	deployPhasePulling = "pulling" // Image pull progress, only if the image was missing
+	deployPhaseCreated = "created"
+	deployPhaseStarted = "started"
+	deployPhaseLog     = "log"
+	deployPhaseExited  = "exited"
+	deployPhaseError   = "error"
+)
+
+// deployRequestTimeout is how long the client has to send the create request after connecting.
+const deployRequestTimeout = 30 * time.Second
+
+// deployMessage is a message sent to the client during a deploy.
+type deployMessage struct {
+	Phase         string      `json:"phase"`
+	ContainerID   string      `json:"container_id,omitempty"`
+	ContainerName string      `json:"container_name,omitempty"`
+	Data          interface{} `json:"data,omitempty"`
+	Error         string      `json:"error,omitempty"`
+}
+
+// DeployHandler creates, starts and follows a container over a single WebSocket.
+type DeployHandler struct {
+	containerService *service.ContainerService
+	logService       *service.LogService
+	upgrader         websocket.Upgrader
+}
+
+// NewDeployHandler creates a new deploy handler.
+func NewDeployHandler(containerService *service.ContainerService, logService *service.LogService) *DeployHandler {
+	return &DeployHandler{
+		containerService: containerService,
+		logService:       logService,
+		upgrader:         newUpgrader(),
+	}
+}
+
+// Deploy handles GET /helios/containers/deploy (WebSocket)
+// The client sends a create request as its first message, e.g.
+//
+//	{"image":"nginx:alpine","name":"web","env":["KEY=value"]}
+//
+// and receives phase messages: "pulling" progress if the image is not present
+// locally, "created" with the container ID, "started", then one "log" message per
+// line until the container exits ("exited") or the client disconnects. Failures are
+// reported as an "error" message, after which the connection is closed.
+func (h *DeployHandler) Deploy(c *gin.Context) {
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("Failed to upgrade to WebSocket: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	var writeMu sync.Mutex
+	send := func(msg deployMessage) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+		return conn.WriteJSON(msg)
+	}
+
+	var req service.CreateContainerRequest
+	conn.SetReadDeadline(time.Now().Add(deployRequestTimeout))
+	if err := conn.ReadJSON(&req); err != nil {
+		send(deployMessage{Phase: deployPhaseError, Error: "invalid create request: " + err.Error()})
+		return
+	}
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		send(deployMessage{Phase: deployPhaseError, Error: "invalid create request: " + err.Error()})
+		return
+	}
+	conn.SetReadDeadline(time.Time{})
+	req.Start = false // Started below so the client sees the "started" phase
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	defer trackWebSocket(conn, cancel)()
+	op := startOperation(operationDeploy, req.Image, cancel)
+	defer op.done()
+
+	// The client only sends the request; any further read error means it went away
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				cancel()
+				return
+			}
+		}
+	}()
+
+	progress := make(chan service.PullProgress, 10)
+	pullErr := make(chan error, 1)
+	go func() {
+		defer close(progress)
+		_, err := h.containerService.EnsureImage(ctx, req.Image, progress)
+		pullErr <- err
+	}()
+	for p := range progress {
+		op.setProgress(pullProgressSummary(p))
+		send(deployMessage{Phase: deployPhasePulling, Data: p})
+	}
+	if err := <-pullErr; err != nil {
+		send(deployMessage{Phase: deployPhaseError, Error: err.Error()})
+		return
+	}
+
+	info, err := h.containerService.CreateContainer(ctx, req)
+	if err != nil {
+		msg := deployMessage{Phase: deployPhaseError, Error: err.Error()}
+		var conflict *service.NameConflictError
+		if errors.As(err, &conflict) {
+			// Lets the client offer to replace the existing container
+			msg.Data = gin.H{"conflict": conflict}
+		}
+		send(msg)
+		return
+	}
+	op.setProgress("created " + info.Name)
+	if err := send(deployMessage{Phase: deployPhaseCreated, ContainerID: info.ID, ContainerName: info.Name, Data: info}); err != nil {
+		return
+	}
+
+	if err := h.containerService.StartContainer(ctx, info.ID); err != nil {
+		send(deployMessage{Phase: deployPhaseError, ContainerID: info.ID, ContainerName: info.Name, Error: err.Error()})
+		return
+	}
+	op.setProgress("running " + info.Name)
+	if err := send(deployMessage{Phase: deployPhaseStarted, ContainerID: info.ID, ContainerName: info.Name}); err != nil {
+		return
+	}
+
+	writer := &deployLogWriter{send: send, containerID: info.ID, containerName: info.Name}
+	errChan, err := h.logService.StreamLogs(ctx, info.ID, service.LogStreamOptions{
+		Follow:         true,
+		Tail:           "all",
+		Timestamps:     true,
+		TimestampField: true,
+	}, writer)
+	if err != nil {
+		send(deployMessage{Phase: deployPhaseError, ContainerID: info.ID, ContainerName: info.Name, Error: err.Error()})
+		return
+	}
+
+	if err := <-errChan; err != nil {
+		if ctx.Err() == nil {
+			log.Printf("Deploy log streaming error for %s: %v", info.Name, err)
+			send(deployMessage{Phase: deployPhaseError, ContainerID: info.ID, ContainerName: info.Name, Error: err.Error()})
+		} else if op.cancelled() {
+			send(deployMessage{Phase: deployPhaseError, ContainerID: info.ID, ContainerName: info.Name, Error: "deploy cancelled"})
+		}
+		return
+	}
+
+	// The followed log stream ends when the container stops
+	exitCtx, exitCancel := context.WithTimeout(context.Background(), timeouts.Default)
+	defer exitCancel()
+	final, err := h.containerService.GetContainer(exitCtx, info.ID)
+	if err != nil {
+		send(deployMessage{Phase: deployPhaseExited, ContainerID: info.ID, ContainerName: info.Name})
+		return
+	}
+	send(deployMessage{Phase: deployPhaseExited, ContainerID: info.ID, ContainerName: info.Name, Data: final})
+}
+
+// deployLogWriter wraps each log line written by the log stream in a "log" message.
+type deployLogWriter struct {
+	send          func(deployMessage) error
+	containerID   string
+	containerName string
+}
+
+func (w *deployLogWriter) Write(p []byte) (int, error) {
+	// Lines arrive as LogLine JSON, one per write
+	line := json.RawMessage(bytes.TrimSpace(p))
+	if err := w.send(deployMessage{Phase: deployPhaseLog, ContainerID: w.containerID, ContainerName: w.containerName, Data: line}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
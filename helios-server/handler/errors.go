@@ -0,0 +1,18 @@
+// Package handler provides HTTP handlers for the Helios API.
+package handler
+
+import (
+	"net/http"
+
+	"nfcunha/helios/utils/docker"
+)
+
+// errorStatus maps a service error to an HTTP status code.
+// Errors caused by an unreachable Docker daemon are reported as 503 Service Unavailable;
+// everything else uses the provided fallback status.
+func errorStatus(err error, fallback int) int {
+	if docker.IsUnavailable(err) {
+		return http.StatusServiceUnavailable
+	}
+	return fallback
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"testing"
+
+	"nfcunha/helios/utils/docker"
+)
+
+func TestErrorStatus(t *testing.T) {
+	dialErr := &net.OpError{Op: "dial", Net: "unix", Err: syscall.ENOENT}
+
+	tests := []struct {
+		name     string
+		err      error
+		fallback int
+		want     int
+	}{
+		{name: "ping", err: docker.ClassifyError(dialErr), fallback: http.StatusInternalServerError, want: http.StatusServiceUnavailable},
+		{name: "unclassified call wrapped by a service", err: fmt.Errorf("failed to list containers: %w", dialErr), fallback: http.StatusInternalServerError, want: http.StatusServiceUnavailable},
+		{name: "connection refused over a specific fallback", err: fmt.Errorf("failed to stop container: %w", syscall.ECONNREFUSED), fallback: http.StatusConflict, want: http.StatusServiceUnavailable},
+		{name: "other error", err: errors.New("No such container: web"), fallback: http.StatusNotFound, want: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorStatus(tt.err, tt.fallback); got != tt.want {
+				t.Errorf("errorStatus(%v, %d) = %d, want %d", tt.err, tt.fallback, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+// Package handler provides HTTP request handlers.
+package handler
+
+import (
+	"net/http"
+
+	"nfcunha/helios/core/models"
+	"nfcunha/helios/core/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EventLogHandler handles event log HTTP requests.
+type EventLogHandler struct {
+	eventLogRepo *repository.EventLogRepository
+}
+
+// NewEventLogHandler creates a new event log handler.
+func NewEventLogHandler(eventLogRepo *repository.EventLogRepository) *EventLogHandler {
+	return &EventLogHandler{
+		eventLogRepo: eventLogRepo,
+	}
+}
+
+// ListEventLogs handles GET /logs/events
+// Lists system events (Docker connectivity, threshold breaches, startup and shutdown,
+// scheduled jobs), newest first.
+// Query parameters:
+//   - type: string (e.g. "system", "docker", "health_check")
+//   - limit: maximum number of entries (default: 100, max: 1000)
+func (h *EventLogHandler) ListEventLogs(c *gin.Context) {
+	limit, _ := parsePagination(c, 100, 1000)
+	eventType := c.Query("type")
+
+	var logs []*models.EventLog
+	var err error
+	if eventType != "" {
+		logs, err = h.eventLogRepo.GetByType(eventType, limit)
+	} else {
+		logs, err = h.eventLogRepo.GetRecent(limit)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":  "Failed to query event logs",
+			"detail": err.Error(),
+		})
+		return
+	}
+	if logs == nil {
+		logs = []*models.EventLog{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": logs,
+		"count":  len(logs),
+		"limit":  limit,
+	})
+}
@@ -0,0 +1,94 @@
+// Package handler provides HTTP handlers for the Helios API.
+package handler
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"nfcunha/helios/core/service"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// EventsHandler streams Docker daemon events.
+type EventsHandler struct {
+	eventBus *service.EventBus
+	scope    *service.ContainerScope
+	upgrader websocket.Upgrader
+}
+
+// NewEventsHandler creates a new events handler.
+func NewEventsHandler(eventBus *service.EventBus, scope *service.ContainerScope) *EventsHandler {
+	return &EventsHandler{
+		eventBus: eventBus,
+		scope:    scope,
+		upgrader: newUpgrader(),
+	}
+}
+
+// StreamEvents handles GET /helios/events/ws (WebSocket)
+// Sends a DockerEvent JSON message for every container, image, volume and network
+// lifecycle event, including changes made outside Helios. All connections share the
+// event bus's single daemon subscription.
+// Query parameters:
+//   - type: comma-separated event types to forward (default: all four)
+func (h *EventsHandler) StreamEvents(c *gin.Context) {
+	var types map[string]bool
+	if raw := c.Query("type"); raw != "" {
+		types = make(map[string]bool)
+		for _, t := range strings.Split(raw, ",") {
+			types[strings.TrimSpace(t)] = true
+		}
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("Failed to upgrade to WebSocket: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	defer trackWebSocket(conn, cancel)()
+
+	// Handle WebSocket close messages
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				cancel()
+				return
+			}
+		}
+	}()
+
+	msgs, unsubscribe := h.eventBus.Subscribe(64)
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-msgs:
+			if !ok {
+				return
+			}
+			if !service.IsLifecycleEvent(msg) || (types != nil && !types[string(msg.Type)]) {
+				continue
+			}
+			if msg.Type == events.ContainerEventType && !h.scope.Allows(msg.Actor.Attributes["name"]) {
+				continue
+			}
+
+			conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+			if err := conn.WriteJSON(service.NewDockerEvent(msg)); err != nil {
+				log.Printf("Events write failed: %v", err)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// Package handler provides HTTP handlers for the Helios API.
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"nfcunha/helios/core/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// execControlMessage is a control message sent by the client as a text frame.
+type execControlMessage struct {
+	Type string `json:"type"` // "resize"
+	Cols uint   `json:"cols"`
+	Rows uint   `json:"rows"`
+}
+
+// execExitMessage is sent to the client once the command exits.
+type execExitMessage struct {
+	Type     string `json:"type"` // "exit"
+	ExitCode int    `json:"exit_code"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ExecHandler handles interactive commands in containers.
+type ExecHandler struct {
+	containerService *service.ContainerService
+	upgrader         websocket.Upgrader
+}
+
+// NewExecHandler creates a new exec handler.
+func NewExecHandler(containerService *service.ContainerService) *ExecHandler {
+	return &ExecHandler{
+		containerService: containerService,
+		upgrader:         newUpgrader(),
+	}
+}
+
+// StreamExec handles GET /helios/containers/:id/exec/ws (WebSocket)
+// Runs a command with a TTY and bridges it to the WebSocket: binary and text frames
+// are written to its stdin, except text frames holding a resize message such as
+// {"type":"resize","cols":120,"rows":40}, and terminal output is sent as binary frames.
+// When the command exits an {"type":"exit","exit_code":0} text frame is sent and the
+// connection is closed.
+// Query parameters:
+//   - cmd: string (command line split on whitespace, default "/bin/sh")
+//   - user: string (user to run as, default the container's)
+//   - working_dir: string (working directory, default the container's)
+func (h *ExecHandler) StreamExec(c *gin.Context) {
+	containerID := c.Param("id")
+
+	cmd := strings.Fields(c.Query("cmd"))
+	if len(cmd) == 0 {
+		cmd = []string{"/bin/sh"}
+	}
+
+	// Start the command before upgrading so failures get a regular HTTP error
+	session, err := h.containerService.StreamExec(c.Request.Context(), containerID, cmd, service.ExecOptions{
+		User:       c.Query("user"),
+		WorkingDir: c.Query("working_dir"),
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrContainerNotRunning) {
+			status = http.StatusConflict
+		}
+		c.JSON(errorStatus(err, status), gin.H{
+			"error":  "Failed to start command in container",
+			"detail": err.Error(),
+		})
+		return
+	}
+	defer session.Close()
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("Failed to upgrade to WebSocket: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	defer trackWebSocket(conn, cancel)()
+
+	// Closing the session unblocks the output reader when the client goes away
+	stop := context.AfterFunc(ctx, session.Close)
+	defer stop()
+
+	var writeMu sync.Mutex
+	write := func(messageType int, data []byte) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+		return conn.WriteMessage(messageType, data)
+	}
+
+	// Client input: stdin and resize messages
+	go func() {
+		defer cancel()
+		for {
+			messageType, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			if messageType == websocket.TextMessage {
+				var control execControlMessage
+				if json.Unmarshal(data, &control) == nil && control.Type == "resize" {
+					if control.Cols > 0 && control.Rows > 0 {
+						if err := session.Resize(ctx, control.Cols, control.Rows); err != nil {
+							log.Printf("Exec resize failed in container %s: %v", session.ContainerName, err)
+						}
+					}
+					continue
+				}
+			}
+
+			if _, err := session.Write(data); err != nil {
+				return
+			}
+		}
+	}()
+
+	// Terminal output until the command exits or the connection is closed
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := session.Read(buf)
+		if n > 0 {
+			if werr := write(websocket.BinaryMessage, buf[:n]); werr != nil {
+				return
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	if ctx.Err() != nil {
+		return
+	}
+
+	exit := execExitMessage{Type: "exit"}
+	exitCode, err := session.ExitCode(ctx)
+	if err != nil {
+		exit.Error = err.Error()
+	}
+	exit.ExitCode = exitCode
+	if data, err := json.Marshal(exit); err == nil {
+		write(websocket.TextMessage, data)
+	}
+
+	writeMu.Lock()
+	conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "command exited"), time.Now().Add(time.Second))
+	writeMu.Unlock()
+}
@@ -0,0 +1,152 @@
+// Package handler provides HTTP request handlers.
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"nfcunha/helios/core/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthHandler handles health checker HTTP requests.
+type HealthHandler struct {
+	healthChecker *service.HealthChecker
+}
+
+// NewHealthHandler creates a new health handler.
+func NewHealthHandler(healthChecker *service.HealthChecker) *HealthHandler {
+	return &HealthHandler{
+		healthChecker: healthChecker,
+	}
+}
+
+// RunHealthCheck handles POST /health/run
+// Triggers an immediate health check pass using the current configuration.
+func (h *HealthHandler) RunHealthCheck(c *gin.Context) {
+	ctx, cancel := requestContext(c, timeouts.Bulk)
+	defer cancel()
+
+	start := time.Now()
+	checked, err := h.healthChecker.RunOnce(ctx)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
+			"error":  "Failed to run health check",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":            "Health check completed",
+		"containers_checked": checked,
+		"duration_ms":        time.Since(start).Milliseconds(),
+	})
+}
+
+// PortHistory handles GET /helios/containers/:id/ports/history
+// Lists the distinct published port sets recorded by health checks, most recent first.
+// Query parameters:
+//   - limit: integer (max number of port sets, default 20, max 100)
+func (h *HealthHandler) PortHistory(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	records, err := h.healthChecker.PortHistory(c.Param("id"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":  "Failed to load port history",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":      c.Param("id"),
+		"history": records,
+		"count":   len(records),
+	})
+}
+
+// HealthHistory handles GET /helios/containers/:id/health/history
+// Lists the health check results recorded for a container, most recent first. Readings
+// that breached a threshold carry the trigger (cpu, memory or cpu,memory) and the
+// threshold values in effect at the time.
+// Query parameters:
+//   - limit: integer (max number of results, default 50, max 500)
+func (h *HealthHandler) HealthHistory(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 {
+		limit = 50
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	logs, err := h.healthChecker.HealthHistory(c.Param("id"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":  "Failed to load health history",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":      c.Param("id"),
+		"history": logs,
+		"count":   len(logs),
+	})
+}
+
+// StatsHistory handles GET /helios/containers/:id/stats/history
+// Lists the CPU, memory and network readings recorded by health checks, oldest first,
+// for drawing usage charts.
+// Query parameters:
+//   - since: RFC3339 timestamp (default: one hour ago)
+//   - limit: integer (max number of samples, most recent kept, default 500, max 5000)
+func (h *HealthHandler) StatsHistory(c *gin.Context) {
+	since := time.Now().Add(-time.Hour)
+	if raw := c.Query("since"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":  "Invalid since",
+				"detail": "since must be an RFC3339 timestamp such as 2024-01-02T15:04:05Z",
+			})
+			return
+		}
+		since = parsed
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "500"))
+	if err != nil || limit <= 0 {
+		limit = 500
+	}
+	if limit > 5000 {
+		limit = 5000
+	}
+
+	samples, err := h.healthChecker.StatsHistory(c.Param("id"), since, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":  "Failed to load stats history",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":      c.Param("id"),
+		"since":   since,
+		"samples": samples,
+		"count":   len(samples),
+	})
+}
@@ -2,11 +2,13 @@
 package handler
 
 import (
-	"context"
+	"errors"
+	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"strconv"
-	"time"
+	"strings"
 
 	"nfcunha/helios/core/service"
 
@@ -30,12 +32,12 @@ func (h *ImageHandler) ListImages(c *gin.Context) {
 	// Parse query parameters
 	all := c.DefaultQuery("all", "false") == "true"
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	ctx, cancel := requestContext(c, timeouts.Default)
 	defer cancel()
 
 	images, err := h.imageService.ListImages(ctx, all)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
 			"error":  "Failed to list images",
 			"detail": err.Error(),
 		})
@@ -52,12 +54,12 @@ func (h *ImageHandler) ListImages(c *gin.Context) {
 func (h *ImageHandler) InspectImage(c *gin.Context) {
 	imageID := c.Param("id")
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	ctx, cancel := requestContext(c, timeouts.Default)
 	defer cancel()
 
 	detail, err := h.imageService.InspectImage(ctx, imageID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
+		c.JSON(errorStatus(err, http.StatusNotFound), gin.H{
 			"error":  "Failed to inspect image",
 			"detail": err.Error(),
 		})
@@ -67,7 +69,57 @@ func (h *ImageHandler) InspectImage(c *gin.Context) {
 	c.JSON(http.StatusOK, detail)
 }
 
+// DiffImages handles GET /images/diff
+// Compares two images to show what an update from a to b would change.
+// Query parameters:
+//   - a: image reference or ID (e.g. "nginx:1.25")
+//   - b: image reference or ID (e.g. "nginx:1.27")
+func (h *ImageHandler) DiffImages(c *gin.Context) {
+	a, b := c.Query("a"), c.Query("b")
+	if a == "" || b == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Missing image reference",
+			"detail": "Query parameters 'a' and 'b' are required",
+		})
+		return
+	}
+
+	ctx, cancel := requestContext(c, timeouts.Default)
+	defer cancel()
+
+	diff, err := h.imageService.DiffImages(ctx, a, b)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusNotFound), gin.H{
+			"error":  "Failed to diff images",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// AnalyzeImageLayers handles GET /images/layers
+// Reports which layers are shared by which images, with per-layer sizes.
+func (h *ImageHandler) AnalyzeImageLayers(c *gin.Context) {
+	ctx, cancel := requestContext(c, timeouts.Bulk)
+	defer cancel()
+
+	report, err := h.imageService.AnalyzeImageLayers(ctx)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
+			"error":  "Failed to analyze image layers",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
 // PullImage handles POST /images/pull
+// Streams "progress" events, a "status" event before each retry of an interrupted
+// pull, and a final "complete" or "error" event.
 func (h *ImageHandler) PullImage(c *gin.Context) {
 	var req struct {
 		Image string `json:"image" binding:"required"`
@@ -81,12 +133,14 @@ func (h *ImageHandler) PullImage(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Minute)
+	ctx, cancel := requestContext(c, timeouts.Pull)
 	defer cancel()
+	op := startOperation(operationPull, req.Image, cancel)
+	defer op.done()
 
 	progressChan, errChan, err := h.imageService.PullImage(ctx, req.Image)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
 			"error":  "Failed to start image pull",
 			"detail": err.Error(),
 		})
@@ -98,6 +152,8 @@ func (h *ImageHandler) PullImage(c *gin.Context) {
 	c.Writer.Header().Set("Cache-Control", "no-cache")
 	c.Writer.Header().Set("Connection", "keep-alive")
 	c.Writer.Header().Set("Transfer-Encoding", "chunked")
+	shutdown, done := trackSSEStream()
+	defer done()
 
 	c.Stream(func(w io.Writer) bool {
 		select {
@@ -110,6 +166,11 @@ func (h *ImageHandler) PullImage(c *gin.Context) {
 				return false
 			}
 			// Send progress update
+			op.setProgress(pullProgressSummary(progress))
+			if progress.Retry != nil {
+				c.SSEvent("status", progress)
+				return true
+			}
 			c.SSEvent("progress", progress)
 			return true
 
@@ -121,26 +182,251 @@ func (h *ImageHandler) PullImage(c *gin.Context) {
 			}
 			return false
 
+		case <-shutdown:
+			c.SSEvent("shutdown", gin.H{
+				"message": shutdownMessage,
+			})
+			return false
+
+		case <-ctx.Done():
+			message := "Pull operation timed out"
+			if op.cancelled() {
+				message = "Pull operation cancelled"
+			}
+			c.SSEvent("error", gin.H{
+				"error": message,
+			})
+			return false
+		}
+	})
+}
+
+// TagImage handles POST /images/:id/tag
+// Adds a reference to an image and returns its refreshed details.
+// Request body: {"target": "repo:tag"}
+func (h *ImageHandler) TagImage(c *gin.Context) {
+	imageID := c.Param("id")
+
+	var req struct {
+		Target string `json:"target" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Invalid request body",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := requestContext(c, timeouts.Default)
+	defer cancel()
+
+	if err := h.imageService.TagImage(ctx, imageID, req.Target); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidImageReference) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(errorStatus(err, status), gin.H{
+			"error":  "Failed to tag image",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	detail, err := h.imageService.InspectImage(ctx, imageID)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
+			"error":  "Image tagged but failed to inspect it",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+// BuildImage handles POST /images/build
+// Accepts a multipart upload: optional form fields followed by the build context as a
+// tar archive in the "context" part, which is streamed to Docker without buffering.
+// Form fields:
+//   - tag: image reference to tag the result with (repeatable)
+//   - build_arg: KEY=VALUE for an ARG instruction (repeatable)
+//   - target: build stage to stop at
+//   - dockerfile: path of the Dockerfile within the context (default "Dockerfile")
+//
+// Streams "progress" events with the build output (the built image ID arrives in
+// "aux") and a final "complete" or "error" event, like the pull endpoint.
+func (h *ImageHandler) BuildImage(c *gin.Context) {
+	opts, tarContext, err := readBuildRequest(c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Invalid build request",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := requestContext(c, timeouts.Pull)
+	defer cancel()
+	op := startOperation(operationBuild, buildTarget(opts.Tags), cancel)
+	defer op.done()
+
+	progressChan, errChan, err := h.imageService.BuildImage(ctx, tarContext, opts)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrBuildContextTooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		c.JSON(errorStatus(err, status), gin.H{
+			"error":  "Failed to start image build",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	// Stream build output as Server-Sent Events (SSE)
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+	c.Writer.Header().Set("Transfer-Encoding", "chunked")
+	shutdown, done := trackSSEStream()
+	defer done()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case progress, ok := <-progressChan:
+			if !ok {
+				// Channel closed; report the build error if there was one
+				if err := <-errChan; err != nil {
+					c.SSEvent("error", gin.H{
+						"error": err.Error(),
+					})
+					return false
+				}
+				c.SSEvent("complete", gin.H{
+					"status": "Build completed successfully",
+				})
+				return false
+			}
+			if summary := strings.TrimSpace(progress.Stream); summary != "" {
+				op.setProgress(summary)
+			} else {
+				op.setProgress(pullProgressSummary(progress))
+			}
+			c.SSEvent("progress", progress)
+			return true
+
+		case <-shutdown:
+			c.SSEvent("shutdown", gin.H{
+				"message": shutdownMessage,
+			})
+			return false
+
 		case <-ctx.Done():
+			message := "Build operation timed out"
+			if op.cancelled() {
+				message = "Build operation cancelled"
+			}
 			c.SSEvent("error", gin.H{
-				"error": "Pull operation timed out",
+				"error": message,
 			})
 			return false
 		}
 	})
 }
 
+// readBuildRequest reads the build options from a multipart request up to the
+// "context" part, which is returned unread so it can be streamed to Docker.
+func readBuildRequest(r *http.Request) (service.BuildOptions, io.Reader, error) {
+	var opts service.BuildOptions
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return opts, nil, fmt.Errorf("expected a multipart/form-data upload: %w", err)
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return opts, nil, errors.New("missing build context: upload a tar archive in the 'context' part")
+		}
+		if err != nil {
+			return opts, nil, fmt.Errorf("failed to read upload: %w", err)
+		}
+
+		if part.FormName() == "context" {
+			return opts, part, nil
+		}
+
+		value, err := readBuildField(part)
+		if err != nil {
+			return opts, nil, err
+		}
+		switch part.FormName() {
+		case "tag":
+			opts.Tags = append(opts.Tags, value)
+		case "build_arg":
+			key, val, ok := strings.Cut(value, "=")
+			if !ok || key == "" {
+				return opts, nil, fmt.Errorf("invalid build_arg %q: expected KEY=VALUE", value)
+			}
+			if opts.BuildArgs == nil {
+				opts.BuildArgs = make(map[string]string)
+			}
+			opts.BuildArgs[key] = val
+		case "target":
+			opts.Target = value
+		case "dockerfile":
+			opts.Dockerfile = value
+		default:
+			return opts, nil, fmt.Errorf("unknown field %q", part.FormName())
+		}
+	}
+}
+
+// maxBuildFieldSize bounds the form fields preceding the build context.
+const maxBuildFieldSize = 64 * 1024
+
+// readBuildField reads a small form field value.
+func readBuildField(part *multipart.Part) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(part, maxBuildFieldSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read field %q: %w", part.FormName(), err)
+	}
+	if len(data) > maxBuildFieldSize {
+		return "", fmt.Errorf("field %q is too large", part.FormName())
+	}
+	return string(data), nil
+}
+
+// buildTarget describes a build in the operations registry.
+func buildTarget(tags []string) string {
+	if len(tags) == 0 {
+		return "untagged build"
+	}
+	return strings.Join(tags, ", ")
+}
+
 // RemoveImage handles DELETE /images/:id
+// Query parameters:
+//   - force: boolean (remove the image even if it has several tags)
+//   - cascade: boolean (stop and remove every container using the image first)
+//   - confirm: boolean (required with cascade; without it the containers that would be removed are listed)
 func (h *ImageHandler) RemoveImage(c *gin.Context) {
 	imageID := c.Param("id")
 	force := c.DefaultQuery("force", "false") == "true"
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	if c.Query("cascade") == "true" {
+		h.removeImageCascade(c, imageID, force)
+		return
+	}
+
+	ctx, cancel := requestContext(c, timeouts.Default)
 	defer cancel()
 
 	err := h.imageService.RemoveImage(ctx, imageID, force)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
 			"error":  "Failed to remove image",
 			"detail": err.Error(),
 		})
@@ -153,7 +439,74 @@ func (h *ImageHandler) RemoveImage(c *gin.Context) {
 	})
 }
 
+// removeImageCascade removes an image together with the containers using it,
+// once the client has confirmed the removal.
+func (h *ImageHandler) removeImageCascade(c *gin.Context, imageID string, force bool) {
+	if c.Query("confirm") != "true" {
+		ctx, cancel := requestContext(c, timeouts.Default)
+		defer cancel()
+
+		resolvedID, name, dependents, outOfScope, err := h.imageService.ImageDependents(ctx, imageID)
+		if err != nil {
+			c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
+				"error":  "Failed to remove image",
+				"detail": err.Error(),
+			})
+			return
+		}
+
+		if outOfScope > 0 {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":        "Failed to remove image",
+				"detail":       fmt.Sprintf("%v: %d container(s)", service.ErrCascadeOutOfScope, outOfScope),
+				"image_id":     resolvedID,
+				"image_name":   name,
+				"dependents":   dependents,
+				"out_of_scope": outOfScope,
+			})
+			return
+		}
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "Confirmation required",
+			"detail":     "Cascade removal stops and removes every container using the image; repeat the request with confirm=true",
+			"image_id":   resolvedID,
+			"image_name": name,
+			"dependents": dependents,
+		})
+		return
+	}
+
+	ctx, cancel := requestContext(c, timeouts.Bulk)
+	defer cancel()
+
+	report, err := h.imageService.RemoveImageCascade(ctx, imageID, force)
+	if err != nil {
+		if report == nil {
+			c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
+				"error":  "Failed to remove image",
+				"detail": err.Error(),
+			})
+			return
+		}
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrCascadeIncomplete) || errors.Is(err, service.ErrCascadeOutOfScope) {
+			status = http.StatusConflict
+		}
+		c.JSON(errorStatus(err, status), gin.H{
+			"error":  "Failed to remove image",
+			"detail": err.Error(),
+			"report": report,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
 // BulkRemoveImages handles POST /images/bulk/remove
+// Query parameters:
+//   - stream: boolean (emit each result as an NDJSON line as it finishes)
 func (h *ImageHandler) BulkRemoveImages(c *gin.Context) {
 	var req struct {
 		ImageIDs []string `json:"image_ids" binding:"required"`
@@ -175,33 +528,86 @@ func (h *ImageHandler) BulkRemoveImages(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Minute)
+	ctx, cancel := requestContext(c, timeouts.Bulk)
 	defer cancel()
 
-	results := h.imageService.BulkRemoveImages(ctx, req.ImageIDs, req.Force)
+	respondBulk(c, func(progress chan<- service.BulkOperationResult) []service.BulkOperationResult {
+		return h.imageService.BulkRemoveImages(ctx, req.ImageIDs, req.Force, progress)
+	}, resourceBulkSummary)
+}
 
-	// Count successful and failed operations
-	successful := countSuccessful(results)
-	failed := countFailed(results)
+// BulkTagImages handles POST /images/bulk/tag
+// Query parameters:
+//   - stream: boolean (emit each result as an NDJSON line as it finishes)
+func (h *ImageHandler) BulkTagImages(c *gin.Context) {
+	var req struct {
+		Tags []service.TagPair `json:"tags" binding:"required,dive"`
+	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"results":    results,
-		"total":      len(results),
-		"successful": successful,
-		"failed":     failed,
-	})
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Invalid request body",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	if len(req.Tags) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "No tag pairs provided",
+		})
+		return
+	}
+
+	ctx, cancel := requestContext(c, timeouts.Bulk)
+	defer cancel()
+
+	respondBulk(c, func(progress chan<- service.BulkOperationResult) []service.BulkOperationResult {
+		return h.imageService.BulkTagImages(ctx, req.Tags, progress)
+	}, resourceBulkSummary)
+}
+
+// BulkUntagImages handles POST /images/bulk/untag
+// Query parameters:
+//   - stream: boolean (emit each result as an NDJSON line as it finishes)
+func (h *ImageHandler) BulkUntagImages(c *gin.Context) {
+	var req struct {
+		Tags []string `json:"tags" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Invalid request body",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	if len(req.Tags) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "No tags provided",
+		})
+		return
+	}
+
+	ctx, cancel := requestContext(c, timeouts.Bulk)
+	defer cancel()
+
+	respondBulk(c, func(progress chan<- service.BulkOperationResult) []service.BulkOperationResult {
+		return h.imageService.BulkUntagImages(ctx, req.Tags, progress)
+	}, resourceBulkSummary)
 }
 
 // PruneImages handles POST /images/prune
 func (h *ImageHandler) PruneImages(c *gin.Context) {
 	all := c.DefaultQuery("all", "false") == "true"
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Minute)
+	ctx, cancel := requestContext(c, timeouts.Prune)
 	defer cancel()
 
-	spaceReclaimed, err := h.imageService.PruneImages(ctx, all)
+	spaceReclaimed, skipped, err := h.imageService.PruneImages(ctx, all)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
 			"error":  "Failed to prune images",
 			"detail": err.Error(),
 		})
@@ -212,6 +618,7 @@ func (h *ImageHandler) PruneImages(c *gin.Context) {
 		"message":            "Images pruned successfully",
 		"space_reclaimed":    spaceReclaimed,
 		"space_reclaimed_mb": float64(spaceReclaimed) / 1024 / 1024,
+		"skipped":            skipped,
 	})
 }
 
@@ -231,12 +638,12 @@ func (h *ImageHandler) SearchImages(c *gin.Context) {
 		limit = 25
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	ctx, cancel := requestContext(c, timeouts.Default)
 	defer cancel()
 
 	results, err := h.imageService.SearchImages(ctx, term, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
 			"error":  "Failed to search images",
 			"detail": err.Error(),
 		})
@@ -266,12 +673,12 @@ func (h *ImageHandler) GetImageTags(c *gin.Context) {
 		limit = 20
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	ctx, cancel := requestContext(c, timeouts.Default)
 	defer cancel()
 
 	tags, err := h.imageService.GetImageTags(ctx, imageName, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
 			"error":  "Failed to fetch image tags",
 			"detail": err.Error(),
 		})
@@ -0,0 +1,53 @@
+// Package handler provides HTTP request handlers.
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"nfcunha/helios/core/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LifecycleHandler serves container lifecycle timelines.
+type LifecycleHandler struct {
+	timeline *service.LifecycleTimeline
+}
+
+// NewLifecycleHandler creates a new lifecycle handler.
+func NewLifecycleHandler(timeline *service.LifecycleTimeline) *LifecycleHandler {
+	return &LifecycleHandler{
+		timeline: timeline,
+	}
+}
+
+// ContainerLifecycle handles GET /helios/containers/:id/lifecycle
+// Lists the container's recent state transitions (created, started, stopped, died,
+// restarted, removed, ...) oldest first, whether made through Helios or outside it.
+// Query parameters:
+//   - limit: integer (most recent transitions to return, default 100, max 500)
+func (h *LifecycleHandler) ContainerLifecycle(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit <= 0 {
+		limit = 100
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	transitions, err := h.timeline.ForContainer(c.Param("id"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":  "Failed to load container lifecycle",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":          c.Param("id"),
+		"transitions": transitions,
+		"count":       len(transitions),
+	})
+}
@@ -3,9 +3,12 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -23,23 +26,20 @@ type LogHandler struct {
 func NewLogHandler(logService *service.LogService) *LogHandler {
 	return &LogHandler{
 		logService: logService,
-		upgrader: websocket.Upgrader{
-			ReadBufferSize:  1024,
-			WriteBufferSize: 1024,
-			CheckOrigin: func(r *http.Request) bool {
-				return true // Allow all origins in development
-			},
-		},
+		upgrader:   newUpgrader(),
 	}
 }
 
 // StreamLogs handles GET /helios/containers/:id/logs (WebSocket)
 // Query parameters:
 //   - follow: boolean (follow log output)
-//   - tail: string (number of lines from end, default "all")
+//   - tail: string ("all" or number of lines from end, default "100"; at most HELIOS_LOG_MAX_TAIL)
 //   - timestamps: boolean (show timestamps)
 //   - since: string (show logs since timestamp)
 //   - until: string (show logs before timestamp)
+//   - timestamp_format: string (rfc3339, rfc3339nano, datetime, time, or a Go time layout)
+//   - tz: string (IANA time zone or "local" for the server's zone, default UTC)
+//   - timestamp_field: boolean (emit JSON lines with the timestamp in a separate field)
 func (h *LogHandler) StreamLogs(c *gin.Context) {
 	containerID := c.Param("id")
 	if containerID == "" {
@@ -49,15 +49,36 @@ func (h *LogHandler) StreamLogs(c *gin.Context) {
 		return
 	}
 
+	tail, ok := h.parseTail(c, h.logService.NormalizeStreamTail, service.DefaultStreamTail)
+	if !ok {
+		return
+	}
+
 	// Parse query parameters
 	opts := service.LogStreamOptions{
 		Follow:     c.Query("follow") == "true",
-		Tail:       c.DefaultQuery("tail", "100"),
+		Tail:       tail,
 		Timestamps: c.Query("timestamps") == "true",
 		Since:      c.Query("since"),
 		Until:      c.Query("until"),
 	}
 
+	format, location, err := parseTimestampOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Invalid timestamp options",
+			"detail": err.Error(),
+		})
+		return
+	}
+	opts.TimestampFormat = format
+	opts.TimestampLocation = location
+	opts.TimestampField = c.Query("timestamp_field") == "true"
+
+	if !h.checkLogsReadable(c, containerID) {
+		return
+	}
+
 	// Upgrade to WebSocket
 	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
@@ -76,6 +97,7 @@ func (h *LogHandler) StreamLogs(c *gin.Context) {
 	// Create a context that can be cancelled
 	ctx, cancel := context.WithCancel(c.Request.Context())
 	defer cancel()
+	defer trackWebSocket(conn, cancel)()
 
 	// Handle WebSocket close messages
 	go func() {
@@ -111,11 +133,87 @@ func (h *LogHandler) StreamLogs(c *gin.Context) {
 	}
 }
 
+// StreamMatchingLogs handles GET /helios/logs/stream (WebSocket)
+// Follows logs from all running containers matching a label and/or name filter.
+// Lines are prefixed with the container name; containers that start or stop while
+// streaming are attached and detached automatically.
+// Query parameters:
+//   - filter: string (label selector as key or key=value)
+//   - name: string (container name substring)
+//   - tail: string ("all" or number of lines from end per container, default "10"; at most HELIOS_LOG_MAX_TAIL)
+//   - timestamps: boolean (show timestamps)
+func (h *LogHandler) StreamMatchingLogs(c *gin.Context) {
+	sel := service.LogSelector{
+		Label: c.Query("filter"),
+		Name:  c.Query("name"),
+	}
+	if sel.Label == "" && sel.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Missing container selector",
+			"detail": "Query parameter 'filter' or 'name' is required",
+		})
+		return
+	}
+
+	tail, ok := h.parseTail(c, h.logService.NormalizeStreamTail, service.DefaultMatchingTail)
+	if !ok {
+		return
+	}
+
+	opts := service.LogStreamOptions{
+		Tail:       tail,
+		Timestamps: c.Query("timestamps") == "true",
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("Failed to upgrade to WebSocket: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	defer trackWebSocket(conn, cancel)()
+
+	// Handle WebSocket close messages
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				cancel()
+				return
+			}
+		}
+	}()
+
+	writer := &websocketWriter{
+		conn: conn,
+	}
+
+	errChan, err := h.logService.FollowMatchingLogs(ctx, sel, opts, writer)
+	if err != nil {
+		conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf("Error: %v\n", err)))
+		return
+	}
+
+	// The stream ends once the client disconnects; wait for all followers to stop writing
+	if err := <-errChan; err != nil && err != context.Canceled {
+		log.Printf("Multi-container log streaming error: %v", err)
+	}
+}
+
 // DownloadLogs handles GET /helios/containers/:id/logs/download
-// Downloads container logs as a ZIP file.
+// Downloads container logs as a ZIP file, or a gzip file with compression=gzip.
+// The archive is streamed as it is written, so its sizes are sent as the trailers
+// X-Helios-Uncompressed-Size and X-Helios-Compressed-Size.
 // Query parameters:
-//   - tail: string (number of lines from end, default "all")
+//   - tail: string ("all" or number of lines from end, default "all")
 //   - timestamps: boolean (include timestamps)
+//   - split: boolean (write stdout and stderr to separate files, tail counting lines across both; not with gzip)
+//   - timestamp_format: string (rfc3339, rfc3339nano, datetime, time, or a Go time layout)
+//   - tz: string (IANA time zone or "local" for the server's zone, default UTC)
+//   - compression: string (deflate, store or gzip, default deflate)
+//   - level: integer (1 fastest to 9 smallest, for deflate and gzip)
 func (h *LogHandler) DownloadLogs(c *gin.Context) {
 	containerID := c.Param("id")
 	if containerID == "" {
@@ -125,19 +223,172 @@ func (h *LogHandler) DownloadLogs(c *gin.Context) {
 		return
 	}
 
+	format, location, err := parseTimestampOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Invalid timestamp options",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	tail, ok := h.parseTail(c, h.logService.NormalizeTail, service.DefaultArchiveTail)
+	if !ok {
+		return
+	}
+
+	opts := service.LogArchiveOptions{
+		Tail:              tail,
+		SplitStreams:      c.Query("split") == "true",
+		TimestampFormat:   format,
+		TimestampLocation: location,
+		Compression:       c.DefaultQuery("compression", service.LogCompressionDeflate),
+	}
+	if raw := c.Query("level"); raw != "" {
+		level, err := strconv.Atoi(raw)
+		if err != nil || level < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":  "Invalid compression options",
+				"detail": "level must be an integer between 1 and 9",
+			})
+			return
+		}
+		opts.Level = level
+	}
+	if err := service.ValidateLogArchiveOptions(opts); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Invalid compression options",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	if !h.checkLogsReadable(c, containerID) {
+		return
+	}
+
 	// Set headers for download
-	c.Header("Content-Type", "application/zip")
-	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=container-%s-logs.zip", containerID[:12]))
+	if opts.Compression == service.LogCompressionGzip {
+		c.Header("Content-Type", "application/gzip")
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=container-%s-logs.log.gz", service.ShortID(containerID)))
+	} else {
+		c.Header("Content-Type", "application/zip")
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=container-%s-logs.zip", service.ShortID(containerID)))
+	}
+	c.Header("Trailer", "X-Helios-Uncompressed-Size, X-Helios-Compressed-Size")
 
-	// Create archive and stream to response
-	if err := h.logService.CreateLogArchive(c.Request.Context(), containerID, c.Writer); err != nil {
+	// Create archive and stream to response; it may take longer than the server's write timeout
+	extendWriteDeadline(c, 0)
+	result, err := h.logService.CreateLogArchive(c.Request.Context(), containerID, opts, c.Writer)
+	if err != nil {
 		log.Printf("Failed to create log archive: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
+		if c.Writer.Written() {
+			// Part of the archive was already sent; the client sees a truncated download
+			return
+		}
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
 			"error":  "Failed to create log archive",
 			"detail": err.Error(),
 		})
 		return
 	}
+
+	c.Writer.Header().Set("X-Helios-Uncompressed-Size", strconv.FormatInt(result.UncompressedSize, 10))
+	c.Writer.Header().Set("X-Helios-Compressed-Size", strconv.FormatInt(result.CompressedSize, 10))
+}
+
+// parseTail reads the tail query parameter with normalize, responding with 400 and
+// returning false if it is not "all" or a line count within the configured limit.
+func (h *LogHandler) parseTail(c *gin.Context, normalize func(raw, def string) (string, error), def string) (string, bool) {
+	tail, err := normalize(c.Query("tail"), def)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Invalid tail",
+			"detail": err.Error(),
+		})
+		return "", false
+	}
+	return tail, true
+}
+
+// checkLogsReadable responds with an error and returns false if the container's
+// logging driver cannot serve logs.
+func (h *LogHandler) checkLogsReadable(c *gin.Context, containerID string) bool {
+	err := h.logService.CheckLogsReadable(c.Request.Context(), containerID)
+	if err == nil {
+		return true
+	}
+
+	if errors.Is(err, service.ErrLogDriverUnsupported) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Unsupported logging driver",
+			"detail": err.Error(),
+		})
+		return false
+	}
+	c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
+		"error":  "Failed to inspect container",
+		"detail": err.Error(),
+	})
+	return false
+}
+
+// ClearLogs handles POST /helios/containers/:id/logs/clear
+// Truncates a json-file container's log file without recreating the container.
+func (h *LogHandler) ClearLogs(c *gin.Context) {
+	ctx, cancel := requestContext(c, timeouts.Default)
+	defer cancel()
+
+	reclaimed, err := h.logService.ClearLogs(ctx, c.Param("id"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrLogClearUnsupported) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(errorStatus(err, status), gin.H{
+			"error":  "Failed to clear container logs",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":         "Container logs cleared successfully",
+		"id":              c.Param("id"),
+		"space_reclaimed": reclaimed,
+	})
+}
+
+// timestampLayouts maps named timestamp formats to Go time layouts.
+var timestampLayouts = map[string]string{
+	"rfc3339":     time.RFC3339,
+	"rfc3339nano": time.RFC3339Nano,
+	"datetime":    "2006-01-02 15:04:05.000",
+	"time":        "15:04:05.000",
+}
+
+// parseTimestampOptions reads the timestamp_format and tz query parameters.
+// Unknown format names are used as Go time layouts.
+func parseTimestampOptions(c *gin.Context) (string, *time.Location, error) {
+	format := c.Query("timestamp_format")
+	if layout, ok := timestampLayouts[strings.ToLower(format)]; ok {
+		format = layout
+	}
+
+	var location *time.Location
+	switch tz := c.Query("tz"); tz {
+	case "":
+	case "local":
+		location = time.Local
+	default:
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return "", nil, fmt.Errorf("unknown time zone %q", tz)
+		}
+		location = loc
+	}
+
+	return format, location, nil
 }
 
 // websocketWriter implements io.Writer for WebSocket text messages.
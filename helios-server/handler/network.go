@@ -2,9 +2,8 @@
 package handler
 
 import (
-	"context"
+	"errors"
 	"net/http"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"nfcunha/helios/core/service"
@@ -24,12 +23,12 @@ func NewNetworkHandler(networkService *service.NetworkService) *NetworkHandler {
 
 // ListNetworks handles GET /networks
 func (h *NetworkHandler) ListNetworks(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	ctx, cancel := requestContext(c, timeouts.Default)
 	defer cancel()
 
 	networks, err := h.networkService.ListNetworks(ctx)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
 			"error":  "Failed to list networks",
 			"detail": err.Error(),
 		})
@@ -42,16 +41,35 @@ func (h *NetworkHandler) ListNetworks(c *gin.Context) {
 	})
 }
 
+// GetTopology handles GET /networks/topology
+// Returns every network with its attached containers and their addresses, plus each
+// container's network memberships, in a single response.
+func (h *NetworkHandler) GetTopology(c *gin.Context) {
+	ctx, cancel := requestContext(c, timeouts.Default)
+	defer cancel()
+
+	topology, err := h.networkService.GetTopology(ctx)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
+			"error":  "Failed to build network topology",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, topology)
+}
+
 // InspectNetwork handles GET /networks/:id
 func (h *NetworkHandler) InspectNetwork(c *gin.Context) {
 	networkID := c.Param("id")
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	ctx, cancel := requestContext(c, timeouts.Default)
 	defer cancel()
 
 	detail, err := h.networkService.InspectNetwork(ctx, networkID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
+		c.JSON(errorStatus(err, http.StatusNotFound), gin.H{
 			"error":  "Failed to inspect network",
 			"detail": err.Error(),
 		})
@@ -62,6 +80,8 @@ func (h *NetworkHandler) InspectNetwork(c *gin.Context) {
 }
 
 // CreateNetwork handles POST /networks
+// A subnet colliding with a host route is rejected with 409 Conflict naming the route,
+// or reported in "warnings", depending on HELIOS_NETWORK_ROUTE_CHECK.
 func (h *NetworkHandler) CreateNetwork(c *gin.Context) {
 	var req service.CreateNetworkRequest
 
@@ -73,12 +93,22 @@ func (h *NetworkHandler) CreateNetwork(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	ctx, cancel := requestContext(c, timeouts.Default)
 	defer cancel()
 
 	detail, err := h.networkService.CreateNetwork(ctx, &req)
+	var conflict *service.SubnetConflictError
+	if errors.As(err, &conflict) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":  "Subnet collides with a host route",
+			"detail": err.Error(),
+			"subnet": conflict.Subnet,
+			"route":  conflict.Route,
+		})
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
 			"error":  "Failed to create network",
 			"detail": err.Error(),
 		})
@@ -92,12 +122,12 @@ func (h *NetworkHandler) CreateNetwork(c *gin.Context) {
 func (h *NetworkHandler) RemoveNetwork(c *gin.Context) {
 	networkID := c.Param("id")
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	ctx, cancel := requestContext(c, timeouts.Default)
 	defer cancel()
 
 	err := h.networkService.RemoveNetwork(ctx, networkID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
 			"error":  "Failed to remove network",
 			"detail": err.Error(),
 		})
@@ -110,6 +140,37 @@ func (h *NetworkHandler) RemoveNetwork(c *gin.Context) {
 	})
 }
 
+// BulkRemoveNetworks handles POST /networks/bulk/remove
+// Query parameters:
+//   - stream: boolean (emit each result as an NDJSON line as it finishes)
+func (h *NetworkHandler) BulkRemoveNetworks(c *gin.Context) {
+	var req struct {
+		NetworkIDs []string `json:"network_ids" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Invalid request body",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	if len(req.NetworkIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "No network IDs provided",
+		})
+		return
+	}
+
+	ctx, cancel := requestContext(c, timeouts.Bulk)
+	defer cancel()
+
+	respondBulk(c, func(progress chan<- service.BulkOperationResult) []service.BulkOperationResult {
+		return h.networkService.BulkRemoveNetworks(ctx, req.NetworkIDs, progress)
+	}, resourceBulkSummary)
+}
+
 // PruneNetworks handles POST /networks/prune
 func (h *NetworkHandler) PruneNetworks(c *gin.Context) {
 	// Parse optional filters from request body
@@ -122,12 +183,12 @@ func (h *NetworkHandler) PruneNetworks(c *gin.Context) {
 		req.Filters = make(map[string][]string)
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Minute)
+	ctx, cancel := requestContext(c, timeouts.Prune)
 	defer cancel()
 
-	_, networksDeleted, err := h.networkService.PruneNetworks(ctx, req.Filters)
+	_, networksDeleted, skipped, err := h.networkService.PruneNetworks(ctx, req.Filters)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
 			"error":  "Failed to prune networks",
 			"detail": err.Error(),
 		})
@@ -138,5 +199,6 @@ func (h *NetworkHandler) PruneNetworks(c *gin.Context) {
 		"message":          "Networks pruned successfully",
 		"networks_deleted": networksDeleted,
 		"count":            len(networksDeleted),
+		"skipped":          skipped,
 	})
 }
@@ -0,0 +1,156 @@
+// Package handler provides HTTP handlers for the Helios API.
+package handler
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"nfcunha/helios/core/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Operation types tracked in the operations registry.
+const (
+	operationPull   = "pull"   // Image pull
+	operationBuild  = "build"  // Image build from an uploaded context
+	operationUpdate = "update" // Container update: pull and recreate
+	operationDeploy = "deploy" // Deploy over WebSocket: pull if missing, create, start and follow
+)
+
+// activeOperations tracks long-running operations so operators can list and cancel them.
+var activeOperations = &operationRegistry{operations: make(map[string]*operation)}
+
+// Operation describes a long-running operation in progress.
+type Operation struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Target    string    `json:"target"` // Image reference or container the operation works on
+	StartedAt time.Time `json:"started_at"`
+	Progress  string    `json:"progress,omitempty"` // Latest progress reported by the operation
+	Cancelled bool      `json:"cancelled,omitempty"`
+}
+
+// operation is a registered operation and the function that cancels it.
+type operation struct {
+	registry *operationRegistry
+	info     Operation
+	cancel   context.CancelFunc
+}
+
+// operationRegistry holds the operations in progress.
+type operationRegistry struct {
+	mu         sync.Mutex
+	nextID     int
+	operations map[string]*operation
+}
+
+// startOperation registers an operation; cancel is called when it is cancelled through the API.
+// Call done on the returned operation when it ends.
+func startOperation(opType, target string, cancel context.CancelFunc) *operation {
+	r := activeOperations
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	op := &operation{
+		registry: r,
+		info: Operation{
+			ID:        strconv.Itoa(r.nextID),
+			Type:      opType,
+			Target:    target,
+			StartedAt: time.Now(),
+		},
+		cancel: cancel,
+	}
+	r.operations[op.info.ID] = op
+	return op
+}
+
+// setProgress records the latest progress of the operation.
+func (o *operation) setProgress(progress string) {
+	o.registry.mu.Lock()
+	defer o.registry.mu.Unlock()
+	o.info.Progress = progress
+}
+
+// cancelled reports whether the operation was cancelled through the API.
+func (o *operation) cancelled() bool {
+	o.registry.mu.Lock()
+	defer o.registry.mu.Unlock()
+	return o.info.Cancelled
+}
+
+// done removes the operation from the registry.
+func (o *operation) done() {
+	o.registry.mu.Lock()
+	defer o.registry.mu.Unlock()
+	delete(o.registry.operations, o.info.ID)
+}
+
+// pullProgressSummary condenses a pull progress message for the operations list.
+func pullProgressSummary(progress service.PullProgress) string {
+	return strings.Join(strings.Fields(progress.ID+" "+progress.Status+" "+progress.Progress), " ")
+}
+
+// OperationsHandler lists and cancels long-running operations.
+type OperationsHandler struct{}
+
+// NewOperationsHandler creates a new operations handler.
+func NewOperationsHandler() *OperationsHandler {
+	return &OperationsHandler{}
+}
+
+// ListOperations handles GET /helios/operations
+// Lists the operations in progress, oldest first.
+func (h *OperationsHandler) ListOperations(c *gin.Context) {
+	r := activeOperations
+	r.mu.Lock()
+	operations := make([]Operation, 0, len(r.operations))
+	for _, op := range r.operations {
+		operations = append(operations, op.info)
+	}
+	r.mu.Unlock()
+
+	sort.Slice(operations, func(i, j int) bool {
+		return operations[i].StartedAt.Before(operations[j].StartedAt)
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"operations": operations,
+		"count":      len(operations),
+	})
+}
+
+// CancelOperation handles DELETE /helios/operations/:id
+// Cancels an operation's context; it ends once the step it is on notices, and its
+// client is told it was cancelled.
+func (h *OperationsHandler) CancelOperation(c *gin.Context) {
+	r := activeOperations
+	r.mu.Lock()
+	op, ok := r.operations[c.Param("id")]
+	var info Operation
+	if ok {
+		op.info.Cancelled = true
+		info = op.info
+	}
+	r.mu.Unlock()
+
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Operation not found",
+		})
+		return
+	}
+
+	op.cancel()
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":   "Operation cancelled",
+		"operation": info,
+	})
+}
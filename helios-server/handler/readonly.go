@@ -0,0 +1,49 @@
+// Package handler provides HTTP handlers for the Helios API.
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readOnlySafeRoutes are non-GET routes that only refresh or probe state, so they
+// stay available in read-only mode. Keys are gin route templates.
+var readOnlySafeRoutes = map[string]bool{
+	"/helios/health/run":                  true,
+	"/helios/volumes/:name/refresh-usage": true,
+}
+
+// readOnlyUnsafeRoutes are GET routes that change state despite their method,
+// because they are upgraded to WebSockets that drive Docker.
+var readOnlyUnsafeRoutes = map[string]bool{
+	"/helios/containers/deploy":      true,
+	"/helios/containers/:id/exec/ws": true,
+}
+
+// ReadOnly rejects requests that would change Docker or Helios state with 403 when
+// enabled. GET, HEAD and OPTIONS requests pass through, as do the routes in
+// readOnlySafeRoutes; everything else, and the routes in readOnlyUnsafeRoutes, is refused.
+func ReadOnly(enabled bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !enabled || readOnlyAllowed(c.Request.Method, c.FullPath()) {
+			c.Next()
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"error":  "Helios is in read-only mode",
+			"detail": "Unset HELIOS_READ_ONLY to make changes",
+		})
+	}
+}
+
+// readOnlyAllowed reports whether a request for the route template is allowed in read-only mode.
+func readOnlyAllowed(method, route string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return !readOnlyUnsafeRoutes[route]
+	default:
+		return readOnlySafeRoutes[route]
+	}
+}
@@ -0,0 +1,60 @@
+// Package handler provides HTTP request handlers.
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"nfcunha/helios/core/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SettingsHandler handles settings export and import requests.
+type SettingsHandler struct {
+	settingsService *service.SettingsService
+}
+
+// NewSettingsHandler creates a new settings handler.
+func NewSettingsHandler(settingsService *service.SettingsService) *SettingsHandler {
+	return &SettingsHandler{
+		settingsService: settingsService,
+	}
+}
+
+// ExportSettings handles GET /helios/settings/export
+func (h *SettingsHandler) ExportSettings(c *gin.Context) {
+	c.Header("Content-Disposition", `attachment; filename="helios-settings.json"`)
+	c.JSON(http.StatusOK, h.settingsService.ExportSettings())
+}
+
+// ImportSettings handles POST /helios/settings/import
+// Query parameters:
+//   - dry_run: boolean (validate and report conflicts without applying)
+func (h *SettingsHandler) ImportSettings(c *gin.Context) {
+	var settings service.Settings
+	if err := c.ShouldBindJSON(&settings); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Invalid request body",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	dryRun := c.DefaultQuery("dry_run", "false") == "true"
+
+	result, err := h.settingsService.ImportSettings(&settings, dryRun)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidSettings) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"error":  "Failed to import settings",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,39 @@
+// Package handler provides HTTP handlers for the Helios API.
+package handler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"nfcunha/helios/utils/metrics"
+
+	"github.com/gorilla/websocket"
+)
+
+// shutdownMessage is sent to streaming clients when the server shuts down.
+const shutdownMessage = "server shutting down"
+
+// trackWebSocket counts a WebSocket stream and ends it on shutdown by sending a
+// going-away close frame and cancelling the stream's context. Call the returned
+// function when the stream ends.
+func trackWebSocket(conn *websocket.Conn, cancel context.CancelFunc) func() {
+	return metrics.TrackStream(metrics.StreamWebSocket, func() {
+		// WriteControl may be called concurrently with the stream's own writes
+		closeFrame := websocket.FormatCloseMessage(websocket.CloseGoingAway, shutdownMessage)
+		conn.WriteControl(websocket.CloseMessage, closeFrame, time.Now().Add(time.Second))
+		cancel()
+	})
+}
+
+// trackSSEStream counts an SSE stream and returns a channel closed when the server starts
+// shutting down, on which the stream should send a final "shutdown" event and end. Call
+// the returned function when the stream ends.
+func trackSSEStream() (<-chan struct{}, func()) {
+	shutdown := make(chan struct{})
+	var once sync.Once
+	done := metrics.TrackStream(metrics.StreamSSE, func() {
+		once.Do(func() { close(shutdown) })
+	})
+	return shutdown, done
+}
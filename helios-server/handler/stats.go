@@ -0,0 +1,92 @@
+// Package handler provides HTTP handlers for the Helios API.
+package handler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"nfcunha/helios/core/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// StatsHandler streams live container stats.
+type StatsHandler struct {
+	containerService *service.ContainerService
+	upgrader         websocket.Upgrader
+}
+
+// NewStatsHandler creates a new stats handler.
+func NewStatsHandler(containerService *service.ContainerService) *StatsHandler {
+	return &StatsHandler{
+		containerService: containerService,
+		upgrader:         newUpgrader(),
+	}
+}
+
+// StreamStats handles GET /helios/containers/:id/stats/ws (WebSocket)
+// Sends a ContainerStats JSON message every interval, sampled live rather than from
+// the stats cache. The connection is closed with a normal closure once the container
+// stops.
+// Query parameters:
+//   - interval: integer (seconds between samples, default 2, between 1 and 60)
+func (h *StatsHandler) StreamStats(c *gin.Context) {
+	containerID := c.Param("id")
+
+	interval := 2
+	if raw := c.Query("interval"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > 60 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":  "Invalid interval",
+				"detail": "interval must be a number of seconds between 1 and 60",
+			})
+			return
+		}
+		interval = parsed
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("Failed to upgrade to WebSocket: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	defer trackWebSocket(conn, cancel)()
+
+	// Handle WebSocket close messages
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				cancel()
+				return
+			}
+		}
+	}()
+
+	err = h.containerService.StreamContainerStats(ctx, containerID, time.Duration(interval)*time.Second, func(stats *service.ContainerStats) error {
+		conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+		return conn.WriteJSON(stats)
+	})
+
+	closeCode, reason := websocket.CloseNormalClosure, "container stopped"
+	switch {
+	case err == nil:
+		return
+	case !errors.Is(err, service.ErrContainerNotRunning):
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Stats streaming error: %v", err)
+		closeCode, reason = websocket.CloseInternalServerErr, err.Error()
+	}
+	conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(closeCode, reason), time.Now().Add(time.Second))
+}
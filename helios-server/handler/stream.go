@@ -0,0 +1,333 @@
+// Package handler provides HTTP handlers for the Helios API.
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"nfcunha/helios/core/service"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// Stream channels a client can subscribe to.
+const (
+	streamChannelStats  = "stats"
+	streamChannelEvents = "events"
+	streamChannelLogs   = "logs"
+)
+
+// streamStatsInterval matches the stats cache refresh interval.
+const streamStatsInterval = 3 * time.Second
+
+// streamControl is a control message sent by the client.
+type streamControl struct {
+	Action      string `json:"action"`                 // subscribe or unsubscribe
+	Channel     string `json:"channel"`                // stats, events or logs
+	ContainerID string `json:"container_id,omitempty"` // Container name or ID, required for logs
+	Tail        string `json:"tail,omitempty"`         // "all" or lines of history for logs, default "100"; at most HELIOS_LOG_MAX_TAIL
+}
+
+// streamMessage is a message sent to the client, tagged with the channel it belongs to.
+type streamMessage struct {
+	Channel string      `json:"channel"` // "stats", "events" or "logs:<container id>"
+	Type    string      `json:"type"`    // data, subscribed, unsubscribed or error
+	Data    interface{} `json:"data,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// StreamHandler serves a single WebSocket that multiplexes stats, Docker events and
+// container logs, so the dashboard does not need a connection per feed.
+type StreamHandler struct {
+	containerService *service.ContainerService
+	logService       *service.LogService
+	eventBus         *service.EventBus
+	scope            *service.ContainerScope
+	upgrader         websocket.Upgrader
+}
+
+// NewStreamHandler creates a new stream handler.
+func NewStreamHandler(containerService *service.ContainerService, logService *service.LogService, eventBus *service.EventBus, scope *service.ContainerScope) *StreamHandler {
+	return &StreamHandler{
+		containerService: containerService,
+		logService:       logService,
+		eventBus:         eventBus,
+		scope:            scope,
+		upgrader:         newUpgrader(),
+	}
+}
+
+// Stream handles GET /helios/stream (WebSocket)
+// The client sends control messages such as
+//
+//	{"action":"subscribe","channel":"logs","container_id":"web"}
+//
+// and receives messages tagged with their channel. Every subscription is stopped
+// when it is unsubscribed or the connection closes.
+func (h *StreamHandler) Stream(c *gin.Context) {
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("Failed to upgrade to WebSocket: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	defer trackWebSocket(conn, cancel)()
+
+	session := &streamSession{
+		handler:  h,
+		ctx:      ctx,
+		out:      make(chan streamMessage, 256),
+		channels: make(map[string]*streamSubscription),
+	}
+
+	// Read control messages until the client disconnects
+	readerDone := make(chan struct{})
+	go func() {
+		defer close(readerDone)
+		defer cancel()
+		for {
+			var ctl streamControl
+			if err := conn.ReadJSON(&ctl); err != nil {
+				if _, ok := err.(*websocket.CloseError); !ok && ctx.Err() == nil {
+					log.Printf("Stream control read failed: %v", err)
+				}
+				return
+			}
+			session.handle(ctl)
+		}
+	}()
+
+	// Single writer; channel goroutines only ever send to session.out
+	for {
+		select {
+		case <-ctx.Done():
+			// Unblock the reader so no new subscriptions start, then wait for all feeds
+			conn.Close()
+			<-readerDone
+			session.wg.Wait()
+			return
+		case msg := <-session.out:
+			conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+			if err := conn.WriteJSON(msg); err != nil {
+				log.Printf("Stream write failed: %v", err)
+				cancel()
+			}
+		}
+	}
+}
+
+// streamSession tracks the subscriptions of one connection.
+type streamSession struct {
+	handler  *StreamHandler
+	ctx      context.Context
+	out      chan streamMessage
+	mu       sync.Mutex
+	channels map[string]*streamSubscription
+	wg       sync.WaitGroup
+}
+
+// streamSubscription is a running channel feed.
+type streamSubscription struct {
+	cancel context.CancelFunc
+}
+
+// send queues a message for the client; it gives up once the connection is gone.
+func (s *streamSession) send(msg streamMessage) bool {
+	select {
+	case s.out <- msg:
+		return true
+	case <-s.ctx.Done():
+		return false
+	}
+}
+
+// handle applies a control message.
+func (s *streamSession) handle(ctl streamControl) {
+	key, err := s.channelKey(ctl)
+	if err != nil {
+		s.send(streamMessage{Channel: ctl.Channel, Type: "error", Error: err.Error()})
+		return
+	}
+
+	switch ctl.Action {
+	case "subscribe":
+		s.subscribe(key, ctl)
+	case "unsubscribe":
+		s.mu.Lock()
+		sub, ok := s.channels[key]
+		s.mu.Unlock()
+		if !ok {
+			s.send(streamMessage{Channel: key, Type: "error", Error: "not subscribed"})
+			return
+		}
+		sub.cancel()
+	default:
+		s.send(streamMessage{Channel: key, Type: "error", Error: fmt.Sprintf("unknown action %q", ctl.Action)})
+	}
+}
+
+// channelKey returns the key a control message refers to. Log channels are keyed by
+// the resolved container ID, so names and partial IDs address the same feed.
+func (s *streamSession) channelKey(ctl streamControl) (string, error) {
+	switch ctl.Channel {
+	case streamChannelStats, streamChannelEvents:
+		return ctl.Channel, nil
+	case streamChannelLogs:
+		if ctl.ContainerID == "" {
+			return "", fmt.Errorf("container_id is required for the logs channel")
+		}
+		ctx, cancel := context.WithTimeout(s.ctx, timeouts.Default)
+		defer cancel()
+		containerID, err := s.handler.containerService.ResolveContainer(ctx, ctl.ContainerID)
+		if err != nil {
+			return "", err
+		}
+		return streamChannelLogs + ":" + containerID, nil
+	default:
+		return "", fmt.Errorf("unknown channel %q", ctl.Channel)
+	}
+}
+
+// subscribe starts the feed for a channel unless it is already running.
+func (s *streamSession) subscribe(key string, ctl streamControl) {
+	ctx, cancel := context.WithCancel(s.ctx)
+	sub := &streamSubscription{cancel: cancel}
+
+	s.mu.Lock()
+	if _, ok := s.channels[key]; ok {
+		s.mu.Unlock()
+		cancel()
+		s.send(streamMessage{Channel: key, Type: "error", Error: "already subscribed"})
+		return
+	}
+	s.channels[key] = sub
+	s.mu.Unlock()
+
+	s.send(streamMessage{Channel: key, Type: "subscribed"})
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		var err error
+		switch ctl.Channel {
+		case streamChannelStats:
+			s.runStats(ctx, key)
+		case streamChannelEvents:
+			s.runEvents(ctx, key)
+		case streamChannelLogs:
+			err = s.runLogs(ctx, key, key[len(streamChannelLogs)+1:], ctl.Tail)
+		}
+
+		// Release the channel before reporting, so the client can subscribe again right away
+		cancel()
+		s.mu.Lock()
+		if s.channels[key] == sub {
+			delete(s.channels, key)
+		}
+		s.mu.Unlock()
+
+		if err != nil {
+			s.send(streamMessage{Channel: key, Type: "error", Error: err.Error()})
+		}
+		if s.ctx.Err() == nil {
+			s.send(streamMessage{Channel: key, Type: "unsubscribed"})
+		}
+	}()
+}
+
+// runStats sends cached stats and the dashboard summary at the cache refresh interval.
+func (s *streamSession) runStats(ctx context.Context, key string) {
+	ticker := time.NewTicker(streamStatsInterval)
+	defer ticker.Stop()
+
+	for {
+		summary, _ := s.handler.containerService.GetDashboardSummary(ctx)
+		if !s.send(streamMessage{Channel: key, Type: "data", Data: gin.H{
+			"containers": s.handler.containerService.GetCachedStats(),
+			"summary":    summary,
+		}}) {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runEvents forwards Docker events; container events outside the scope are skipped.
+func (s *streamSession) runEvents(ctx context.Context, key string) {
+	msgs, unsubscribe := s.handler.eventBus.Subscribe(64)
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-msgs:
+			if !ok {
+				return
+			}
+			if msg.Type == events.ContainerEventType && !s.handler.scope.Allows(msg.Actor.Attributes["name"]) {
+				continue
+			}
+			if !s.send(streamMessage{Channel: key, Type: "data", Data: msg}) {
+				return
+			}
+		}
+	}
+}
+
+// runLogs follows a container's logs until the channel is cancelled or the stream ends.
+func (s *streamSession) runLogs(ctx context.Context, key, containerID, tail string) error {
+	tail, err := s.handler.logService.NormalizeStreamTail(tail, service.DefaultStreamTail)
+	if err != nil {
+		return err
+	}
+	if err := s.handler.logService.CheckLogsReadable(ctx, containerID); err != nil {
+		return err
+	}
+
+	opts := service.LogStreamOptions{
+		Follow: true,
+		Tail:   tail,
+	}
+	errChan, err := s.handler.logService.StreamLogs(ctx, containerID, opts, &streamChannelWriter{ctx: ctx, session: s, key: key})
+	if err != nil {
+		return err
+	}
+
+	select {
+	case err := <-errChan:
+		if err != nil && err != context.Canceled && ctx.Err() == nil {
+			return err
+		}
+	case <-ctx.Done():
+	}
+	return nil
+}
+
+// streamChannelWriter implements io.Writer by sending data messages on a channel.
+type streamChannelWriter struct {
+	ctx     context.Context // Channel context; writes fail once it is cancelled
+	session *streamSession
+	key     string
+}
+
+func (w *streamChannelWriter) Write(p []byte) (int, error) {
+	if w.ctx.Err() != nil || !w.session.send(streamMessage{Channel: w.key, Type: "data", Data: string(p)}) {
+		return 0, context.Canceled
+	}
+	return len(p), nil
+}
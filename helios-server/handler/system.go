@@ -0,0 +1,178 @@
+// Package handler provides HTTP request handlers.
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"nfcunha/helios/core/service"
+	"nfcunha/helios/utils/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SystemHandler handles host-wide maintenance requests.
+type SystemHandler struct {
+	drainService     *service.DrainService
+	containerService *service.ContainerService
+	diskService      *service.DiskService
+	cfg              *config.Config
+}
+
+// NewSystemHandler creates a new system handler.
+func NewSystemHandler(drainService *service.DrainService, containerService *service.ContainerService, diskService *service.DiskService, cfg *config.Config) *SystemHandler {
+	return &SystemHandler{
+		drainService:     drainService,
+		containerService: containerService,
+		diskService:      diskService,
+		cfg:              cfg,
+	}
+}
+
+// GetConfig handles GET /helios/system/config
+// Returns the configuration loaded at startup, with the source of each value
+// (default or env) and sensitive values redacted. Health check and prune protection
+// settings changed at runtime are reported by GET /helios/settings/export.
+func (h *SystemHandler) GetConfig(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"settings": h.cfg.Effective(),
+	})
+}
+
+// Drain handles POST /helios/system/drain
+// Records and stops every running container, dependents first, ahead of maintenance.
+func (h *SystemHandler) Drain(c *gin.Context) {
+	ctx, cancel := requestContext(c, timeouts.Bulk)
+	defer cancel()
+
+	result, err := h.drainService.Drain(ctx)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrDrainPending) {
+			status = http.StatusConflict
+		}
+		c.JSON(errorStatus(err, status), gin.H{
+			"error":  "Failed to drain containers",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Restore handles POST /helios/system/restore
+// Starts exactly the containers stopped by the pending drain, dependencies first.
+func (h *SystemHandler) Restore(c *gin.Context) {
+	ctx, cancel := requestContext(c, timeouts.Bulk)
+	defer cancel()
+
+	result, err := h.drainService.Restore(ctx)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrNothingToRestore) {
+			status = http.StatusNotFound
+		}
+		c.JSON(errorStatus(err, status), gin.H{
+			"error":  "Failed to restore containers",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// LogRotation handles GET /helios/system/log-rotation
+// Lists containers whose logs are not rotated and can grow until the disk is full.
+func (h *SystemHandler) LogRotation(c *gin.Context) {
+	ctx, cancel := requestContext(c, timeouts.Bulk)
+	defer cancel()
+
+	report, err := h.containerService.LogRotationReport(ctx)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
+			"error":  "Failed to check log rotation",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetDiskUsage handles GET /helios/system/disk-usage
+// Disk space used by images, containers, volumes and the build cache.
+func (h *SystemHandler) GetDiskUsage(c *gin.Context) {
+	ctx, cancel := requestContext(c, timeouts.Bulk)
+	defer cancel()
+
+	report, err := h.diskService.GetDiskUsage(ctx)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
+			"error":  "Failed to get disk usage",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetBuildCache handles GET /helios/system/build-cache
+func (h *SystemHandler) GetBuildCache(c *gin.Context) {
+	ctx, cancel := requestContext(c, timeouts.Default)
+	defer cancel()
+
+	report, err := h.diskService.GetBuildCache(ctx)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
+			"error":  "Failed to get build cache",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// PruneBuildCache handles POST /helios/system/build-cache/prune
+// Query parameters:
+//   - all: boolean (also remove cache still referenced by images)
+//   - until: duration (only remove cache unused for at least this long, e.g. 24h)
+func (h *SystemHandler) PruneBuildCache(c *gin.Context) {
+	all := c.DefaultQuery("all", "false") == "true"
+
+	var until time.Duration
+	if raw := c.Query("until"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil || parsed < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":  "Invalid until",
+				"detail": "until must be a non-negative duration such as 24h",
+			})
+			return
+		}
+		until = parsed
+	}
+
+	ctx, cancel := requestContext(c, timeouts.Prune)
+	defer cancel()
+
+	result, err := h.diskService.PruneBuildCache(ctx, all, until)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
+			"error":  "Failed to prune build cache",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":            "Build cache pruned successfully",
+		"caches_deleted":     result.CachesDeleted,
+		"space_reclaimed":    result.SpaceReclaimed,
+		"space_reclaimed_mb": float64(result.SpaceReclaimed) / 1024 / 1024,
+	})
+}
@@ -0,0 +1,167 @@
+// Package handler provides HTTP handlers for the Helios API.
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"nfcunha/helios/core/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TemplateHandler handles container template HTTP requests.
+type TemplateHandler struct {
+	templateService *service.TemplateService
+}
+
+// NewTemplateHandler creates a new template handler.
+func NewTemplateHandler(templateService *service.TemplateService) *TemplateHandler {
+	return &TemplateHandler{
+		templateService: templateService,
+	}
+}
+
+// templateRequest is the body of template create and update requests.
+type templateRequest struct {
+	Name        string                         `json:"name"` // Ignored on update; the path names the template
+	Description string                         `json:"description"`
+	Spec        service.CreateContainerRequest `json:"spec"`
+}
+
+// ListTemplates handles GET /helios/templates
+func (h *TemplateHandler) ListTemplates(c *gin.Context) {
+	templates, err := h.templateService.ListTemplates()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":  "Failed to list templates",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"templates": templates,
+		"count":     len(templates),
+	})
+}
+
+// GetTemplate handles GET /helios/templates/:name
+func (h *TemplateHandler) GetTemplate(c *gin.Context) {
+	template, err := h.templateService.GetTemplate(c.Param("name"))
+	if err != nil {
+		respondTemplateError(c, "Failed to get template", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// CreateTemplate handles POST /helios/templates
+// Saves a container creation spec under a name; the spec is validated as for
+// POST /helios/containers, except that the image does not need to be pulled yet.
+// Request body: {"name": "web", "description": "...", "spec": {"image": "nginx:1.27", "ports": ["8080:80"]}}
+func (h *TemplateHandler) CreateTemplate(c *gin.Context) {
+	var req templateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Invalid request body",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	template, err := h.templateService.CreateTemplate(req.Name, req.Description, req.Spec)
+	if err != nil {
+		respondTemplateError(c, "Failed to create template", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// UpdateTemplate handles PUT /helios/templates/:name
+// Replaces the description and spec of a template.
+// Request body: {"description": "...", "spec": {...}}
+func (h *TemplateHandler) UpdateTemplate(c *gin.Context) {
+	var req templateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Invalid request body",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	template, err := h.templateService.UpdateTemplate(c.Param("name"), req.Description, req.Spec)
+	if err != nil {
+		respondTemplateError(c, "Failed to update template", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// DeleteTemplate handles DELETE /helios/templates/:name
+func (h *TemplateHandler) DeleteTemplate(c *gin.Context) {
+	name := c.Param("name")
+	if err := h.templateService.DeleteTemplate(name); err != nil {
+		respondTemplateError(c, "Failed to delete template", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Template deleted successfully",
+		"name":    name,
+	})
+}
+
+// CreateFromTemplate handles POST /helios/containers/from-template/:name
+// Creates a container from a template, responding like POST /helios/containers.
+// The body is optional.
+// Request body: {"name": "web-2", "ports": ["8081:80"], "env": ["LOG_LEVEL=debug"], "labels": {}, "start": true, "auto_name": false, "confirm_replace": false}
+func (h *TemplateHandler) CreateFromTemplate(c *gin.Context) {
+	var overrides service.TemplateOverrides
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&overrides); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":  "Invalid request body",
+				"detail": err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx, cancel := requestContext(c, timeouts.Default)
+	defer cancel()
+
+	info, err := h.templateService.CreateFromTemplate(ctx, c.Param("name"), overrides)
+	if err != nil {
+		if errors.Is(err, service.ErrTemplateNotFound) {
+			respondTemplateError(c, "Failed to create container from template", err)
+			return
+		}
+		respondCreateError(c, info, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, info)
+}
+
+// respondTemplateError maps template errors to HTTP status codes.
+func respondTemplateError(c *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, service.ErrTemplateNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, service.ErrTemplateExists):
+		status = http.StatusConflict
+	case errors.Is(err, service.ErrInvalidTemplate):
+		status = http.StatusBadRequest
+	}
+
+	c.JSON(status, gin.H{
+		"error":  message,
+		"detail": err.Error(),
+	})
+}
@@ -0,0 +1,45 @@
+// Package handler provides HTTP request handlers.
+package handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"nfcunha/helios/utils/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// timeouts holds the per-operation request timeouts used by all handlers.
+var timeouts = config.TimeoutConfig{
+	Default: 30 * time.Second,
+	Bulk:    2 * time.Minute,
+	Prune:   2 * time.Minute,
+	Pull:    5 * time.Minute,
+}
+
+// ConfigureTimeouts sets the request timeouts used by all handlers.
+// It must be called before the server starts handling requests.
+func ConfigureTimeouts(cfg config.TimeoutConfig) {
+	timeouts = cfg
+}
+
+// requestContext derives a handler's context from the request with the given timeout.
+func requestContext(c *gin.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(c.Request.Context(), timeout)
+}
+
+// extendWriteDeadline replaces the server's write timeout for this response, so
+// long-polls and streamed downloads are not cut off after it. The response must be
+// written within d from now; zero d removes the deadline.
+func extendWriteDeadline(c *gin.Context, d time.Duration) {
+	var deadline time.Time
+	if d > 0 {
+		deadline = time.Now().Add(d)
+	}
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(deadline); err != nil {
+		log.Printf("Failed to extend write deadline for %s: %v", c.Request.URL.Path, err)
+	}
+}
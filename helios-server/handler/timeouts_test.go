@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestExtendWriteDeadline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		extend func(c *gin.Context)
+		wantOK bool
+	}{
+		{name: "server write timeout", extend: func(c *gin.Context) {}, wantOK: false},
+		{name: "extended", extend: func(c *gin.Context) { extendWriteDeadline(c, time.Second) }, wantOK: true},
+		{name: "removed", extend: func(c *gin.Context) { extendWriteDeadline(c, 0) }, wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/", func(c *gin.Context) {
+				tt.extend(c)
+				time.Sleep(100 * time.Millisecond)
+				c.String(http.StatusOK, "done")
+			})
+
+			server := httptest.NewUnstartedServer(router)
+			server.Config.WriteTimeout = 20 * time.Millisecond
+			server.Start()
+			defer server.Close()
+
+			resp, err := http.Get(server.URL)
+			ok := err == nil
+			if ok {
+				body, readErr := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				ok = readErr == nil && string(body) == "done"
+			}
+			if ok != tt.wantOK {
+				t.Errorf("response completed = %v, want %v (err: %v)", ok, tt.wantOK, err)
+			}
+		})
+	}
+}
@@ -2,7 +2,7 @@
 package handler
 
 import (
-	"context"
+	"errors"
 	"net/http"
 	"time"
 
@@ -24,12 +24,12 @@ func NewVolumeHandler(volumeService *service.VolumeService) *VolumeHandler {
 
 // ListVolumes handles GET /volumes
 func (h *VolumeHandler) ListVolumes(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	ctx, cancel := requestContext(c, timeouts.Default)
 	defer cancel()
 
 	volumes, err := h.volumeService.ListVolumes(ctx)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
 			"error":  "Failed to list volumes",
 			"detail": err.Error(),
 		})
@@ -46,12 +46,12 @@ func (h *VolumeHandler) ListVolumes(c *gin.Context) {
 func (h *VolumeHandler) InspectVolume(c *gin.Context) {
 	volumeName := c.Param("name")
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	ctx, cancel := requestContext(c, timeouts.Default)
 	defer cancel()
 
 	detail, err := h.volumeService.InspectVolume(ctx, volumeName)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
+		c.JSON(errorStatus(err, http.StatusNotFound), gin.H{
 			"error":  "Failed to inspect volume",
 			"detail": err.Error(),
 		})
@@ -73,12 +73,12 @@ func (h *VolumeHandler) CreateVolume(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	ctx, cancel := requestContext(c, timeouts.Default)
 	defer cancel()
 
 	detail, err := h.volumeService.CreateVolume(ctx, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
 			"error":  "Failed to create volume",
 			"detail": err.Error(),
 		})
@@ -93,12 +93,12 @@ func (h *VolumeHandler) RemoveVolume(c *gin.Context) {
 	volumeName := c.Param("name")
 	force := c.DefaultQuery("force", "false") == "true"
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	ctx, cancel := requestContext(c, timeouts.Default)
 	defer cancel()
 
 	err := h.volumeService.RemoveVolume(ctx, volumeName, force)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
 			"error":  "Failed to remove volume",
 			"detail": err.Error(),
 		})
@@ -111,6 +111,89 @@ func (h *VolumeHandler) RemoveVolume(c *gin.Context) {
 	})
 }
 
+// GetVolumeUsage handles GET /volumes/:name/usage
+// Returns the size last computed by POST /volumes/:name/refresh-usage with its age.
+func (h *VolumeHandler) GetVolumeUsage(c *gin.Context) {
+	volumeName := c.Param("name")
+
+	usage, err := h.volumeService.GetVolumeUsage(volumeName)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrVolumeUsageNotComputed) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error":  "Failed to get volume usage",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"usage":       usage,
+		"age_seconds": int64(time.Since(usage.ComputedAt).Seconds()),
+	})
+}
+
+// RefreshVolumeUsage handles POST /volumes/:name/refresh-usage
+// Computes the volume's size now and caches it.
+func (h *VolumeHandler) RefreshVolumeUsage(c *gin.Context) {
+	volumeName := c.Param("name")
+
+	ctx, cancel := requestContext(c, timeouts.Bulk)
+	defer cancel()
+
+	usage, err := h.volumeService.RefreshVolumeUsage(ctx, volumeName)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrVolumeNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(errorStatus(err, status), gin.H{
+			"error":  "Failed to refresh volume usage",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"usage":       usage,
+		"age_seconds": 0,
+	})
+}
+
+// BulkRemoveVolumes handles POST /volumes/bulk/remove
+// Query parameters:
+//   - stream: boolean (emit each result as an NDJSON line as it finishes)
+func (h *VolumeHandler) BulkRemoveVolumes(c *gin.Context) {
+	var req struct {
+		Names []string `json:"names" binding:"required"`
+		Force bool     `json:"force"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Invalid request body",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	if len(req.Names) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "No volume names provided",
+		})
+		return
+	}
+
+	ctx, cancel := requestContext(c, timeouts.Bulk)
+	defer cancel()
+
+	respondBulk(c, func(progress chan<- service.BulkOperationResult) []service.BulkOperationResult {
+		return h.volumeService.BulkRemoveVolumes(ctx, req.Names, req.Force, progress)
+	}, resourceBulkSummary)
+}
+
 // PruneVolumes handles POST /volumes/prune
 func (h *VolumeHandler) PruneVolumes(c *gin.Context) {
 	// Parse optional filters from request body
@@ -123,12 +206,12 @@ func (h *VolumeHandler) PruneVolumes(c *gin.Context) {
 		req.Filters = make(map[string][]string)
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Minute)
+	ctx, cancel := requestContext(c, timeouts.Prune)
 	defer cancel()
 
-	spaceReclaimed, volumesDeleted, err := h.volumeService.PruneVolumes(ctx, req.Filters)
+	spaceReclaimed, volumesDeleted, skipped, err := h.volumeService.PruneVolumes(ctx, req.Filters)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{
 			"error":  "Failed to prune volumes",
 			"detail": err.Error(),
 		})
@@ -141,5 +224,6 @@ func (h *VolumeHandler) PruneVolumes(c *gin.Context) {
 		"space_reclaimed_mb": float64(spaceReclaimed) / 1024 / 1024,
 		"volumes_deleted":  volumesDeleted,
 		"count":            len(volumesDeleted),
+		"skipped":          skipped,
 	})
 }
@@ -0,0 +1,35 @@
+// Package handler provides HTTP request handlers.
+package handler
+
+import (
+	"net/http"
+
+	"nfcunha/helios/utils/config"
+
+	"github.com/gorilla/websocket"
+)
+
+// websocketSettings holds the WebSocket buffer and compression settings used by all handlers.
+var websocketSettings = config.WebSocketConfig{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 4096,
+}
+
+// ConfigureWebSocket sets the WebSocket settings used by all handlers.
+// It must be called before any handler is created.
+func ConfigureWebSocket(cfg config.WebSocketConfig) {
+	websocketSettings = cfg
+}
+
+// newUpgrader creates a WebSocket upgrader with the configured buffers.
+// With compression enabled, permessage-deflate is used for clients that offer it.
+func newUpgrader() websocket.Upgrader {
+	return websocket.Upgrader{
+		ReadBufferSize:    websocketSettings.ReadBufferSize,
+		WriteBufferSize:   websocketSettings.WriteBufferSize,
+		EnableCompression: websocketSettings.Compression,
+		CheckOrigin: func(r *http.Request) bool {
+			return true // Allow all origins in development
+		},
+	}
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"nfcunha/helios/utils/config"
+
+	"github.com/gorilla/websocket"
+)
+
+// logLikeMessage returns size bytes of text shaped like container log output.
+func logLikeMessage(size int) []byte {
+	const line = "2026-10-16T12:00:00.000000000Z INFO request handled method=GET path=/api/items status=200 duration=1.2ms\n"
+	return []byte(strings.Repeat(line, size/len(line)+1)[:size])
+}
+
+// BenchmarkWebSocketWrite measures server-to-client message throughput over loopback
+// for the buffer sizes and compression settings of HELIOS_WS_*.
+func BenchmarkWebSocketWrite(b *testing.B) {
+	defaults := websocketSettings
+	b.Cleanup(func() { websocketSettings = defaults })
+
+	for _, size := range []int{512, 32 * 1024} {
+		for _, cfg := range []config.WebSocketConfig{
+			{ReadBufferSize: 1024, WriteBufferSize: 1024},
+			{ReadBufferSize: 1024, WriteBufferSize: 4096},
+			{ReadBufferSize: 1024, WriteBufferSize: 16 * 1024},
+			{ReadBufferSize: 1024, WriteBufferSize: 32 * 1024},
+			{ReadBufferSize: 1024, WriteBufferSize: 4096, Compression: true},
+		} {
+			name := fmt.Sprintf("msg=%d/write_buffer=%d/compression=%v", size, cfg.WriteBufferSize, cfg.Compression)
+			b.Run(name, func(b *testing.B) {
+				benchmarkWebSocketWrite(b, cfg, logLikeMessage(size))
+			})
+		}
+	}
+}
+
+func benchmarkWebSocketWrite(b *testing.B, cfg config.WebSocketConfig, message []byte) {
+	websocketSettings = cfg
+	upgrader := newUpgrader()
+
+	start := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		<-start
+		for i := 0; i < b.N; i++ {
+			if err := conn.WriteMessage(websocket.TextMessage, message); err != nil {
+				return
+			}
+		}
+		conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	}))
+	defer server.Close()
+
+	dialer := websocket.Dialer{EnableCompression: cfg.Compression}
+	conn, _, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		b.Fatalf("Dial error = %v", err)
+	}
+	defer conn.Close()
+
+	b.SetBytes(int64(len(message)))
+	b.ResetTimer()
+	close(start)
+	for received := 0; received < b.N; received++ {
+		_, reader, err := conn.NextReader()
+		if err != nil {
+			b.Fatalf("read %d of %d messages: %v", received, b.N, err)
+		}
+		io.Copy(io.Discard, reader)
+	}
+}
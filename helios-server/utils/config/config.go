@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -16,13 +17,31 @@ type Config struct {
 	Docker       DockerConfig
 	HealthCheck  HealthCheckConfig
 	LogRetention LogRetentionConfig
+	AutoPrune    AutoPruneConfig
+	Build        BuildConfig
+	PruneProtect PruneProtectConfig
+	Timeouts     TimeoutConfig
+	Webhook      WebhookConfig
+	Registry     RegistryConfig
+	Cost         CostConfig
+	WebSocket    WebSocketConfig
+	ConfigWatch  ConfigWatchConfig
+	Stats        StatsConfig
+	Network      NetworkConfig
+	Exec         ExecConfig
+	Logs         LogsConfig
+	Bulk         BulkConfig
+
+	sources map[string]Source // Where each environment variable's value came from
 }
 
 // ServerConfig contains HTTP server settings.
 type ServerConfig struct {
-	Host string
-	Port string
-	Mode string // "debug" or "release"
+	Host       string
+	Port       string
+	Mode       string // "debug" or "release"
+	AdminToken string // Bearer token for admin endpoints; empty disables them
+	ReadOnly   bool   // Reject every request that would change Docker or Helios state
 }
 
 // DatabaseConfig contains database settings.
@@ -32,7 +51,15 @@ type DatabaseConfig struct {
 
 // DockerConfig contains Docker daemon settings.
 type DockerConfig struct {
-	Host string
+	Host                  string
+	ContainerNamePrefix   string // Only containers whose name starts with this prefix are visible; empty shows all
+	MaxConcurrentInspects int    // Concurrent container inspect/stats calls across the app
+	ValidateBindMounts    bool   // Check bind mount sources exist before creating a container; needs the host paths visible to Helios
+
+	// Containers, networks and volumes created through Helios are labelled
+	// helios.created and helios.created_at, plus these key=value labels
+	StampResources bool
+	ResourceLabels []string
 }
 
 // HealthCheckConfig contains health check monitoring settings.
@@ -41,11 +68,113 @@ type HealthCheckConfig struct {
 	CPUThreshold    float64
 	MemoryThreshold float64
 	Enabled         bool
+	BreachCount     int // Consecutive breaching readings before a container is flagged critical
+	RecoveryCount   int // Consecutive normal readings before a critical flag is cleared
 }
 
 // LogRetentionConfig contains log retention settings.
 type LogRetentionConfig struct {
-	Days int
+	Days     int
+	Interval time.Duration // How often expired logs are deleted
+	Vacuum   bool          // VACUUM the database after deleting logs (locks the database while running)
+}
+
+// AutoPruneConfig contains scheduled prune settings.
+type AutoPruneConfig struct {
+	Enabled  bool
+	Interval time.Duration
+	Targets  []string // "images", "containers"
+
+	// A run is skipped unless one of the enabled thresholds is exceeded; with both
+	// at zero every run prunes
+	MinReclaimable int64   // Reclaimable bytes across the targets; 0 disables
+	MinDiskPercent float64 // Usage of the filesystem holding Docker's data; 0 disables
+}
+
+// StatsConfig contains stats cache settings.
+type StatsConfig struct {
+	SampleEvery int // Default number of refresh cycles between samples; the helios.stats.every label overrides it
+}
+
+// Host route check modes for network creation.
+const (
+	RouteCheckOff    = "off"
+	RouteCheckWarn   = "warn"
+	RouteCheckReject = "reject"
+)
+
+// NetworkConfig contains network creation settings.
+type NetworkConfig struct {
+	RouteCheck string // "off", "warn" or "reject" when a new subnet collides with a host route
+}
+
+// ExecConfig contains settings for one-off commands run in containers.
+type ExecConfig struct {
+	MaxOutput int64 // Bytes of combined output kept per command; the rest is discarded
+}
+
+// LogsConfig contains container log streaming and download settings.
+type LogsConfig struct {
+	MaxTail int // Largest number of history lines a client may request; "all" is capped to it for streams
+}
+
+// BulkConfig contains settings for bulk container operations.
+type BulkConfig struct {
+	Concurrency int // Containers started, stopped or removed at the same time
+}
+
+// ConfigWatchConfig contains settings for restarting containers when a watched
+// config file changes. Containers opt in with the helios.watch label.
+type ConfigWatchConfig struct {
+	Enabled  bool
+	Interval time.Duration // How often labelled containers are re-listed
+	Debounce time.Duration // How long a file must stay unchanged before the restart
+}
+
+// PruneProtectConfig lists resources that prune operations must never remove.
+type PruneProtectConfig struct {
+	Images   []string // Image references or IDs
+	Volumes  []string // Volume names
+	Networks []string // Network names or IDs
+}
+
+// BuildConfig contains image build settings.
+type BuildConfig struct {
+	MaxContextSize int64 // Maximum build context size in bytes
+}
+
+// TimeoutConfig contains per-operation HTTP handler timeouts.
+type TimeoutConfig struct {
+	Default time.Duration // Lists, inspects and single-resource operations
+	Bulk    time.Duration // Bulk operations and on-demand health checks
+	Prune   time.Duration // Prune operations
+	Pull    time.Duration // Image pulls and builds, and container updates
+}
+
+// WebhookConfig contains outbound notification settings.
+type WebhookConfig struct {
+	URL     string        // Endpoint notified of notable events; empty disables webhooks
+	Timeout time.Duration // Per-delivery timeout
+}
+
+// RegistryConfig contains private registry credential sources.
+type RegistryConfig struct {
+	CredentialsFile string // Path to a JSON file mapping registry hosts to credentials
+	Credentials     string // Inline JSON with the same format; overrides file entries
+}
+
+// CostConfig contains the cost model used for chargeback estimates.
+type CostConfig struct {
+	CPUHour  float64 // Price of one CPU core for one hour
+	GBHour   float64 // Price of one GiB of memory for one hour
+	Currency string  // Label reported with estimates
+}
+
+// WebSocketConfig contains WebSocket streaming settings.
+type WebSocketConfig struct {
+	ReadBufferSize  int  // Bytes; clients only send small control messages
+	WriteBufferSize int  // Bytes
+	Compression     bool // Negotiate permessage-deflate with clients that support it
 }
 
 // Load reads configuration from environment variables with sensible defaults.
@@ -54,39 +183,156 @@ type LogRetentionConfig struct {
 // Configuration variables:
 //   - HELIOS_SERVER_HOST (default: "0.0.0.0")
 //   - HELIOS_SERVER_MODE (default: "debug")
+//   - HELIOS_ADMIN_TOKEN (default: "", admin endpoints disabled)
+//   - HELIOS_READ_ONLY (default: false)
 //   - HELIOS_DB_PATH (default: "/app/data/helios.db" or "./helios.db")
 //   - HELIOS_DOCKER_HOST (default: "unix:///var/run/docker.sock")
+//   - HELIOS_CONTAINER_NAME_PREFIX (default: "")
+//   - HELIOS_MAX_CONCURRENT_INSPECTS (default: "16")
+//   - HELIOS_VALIDATE_BIND_MOUNTS (default: true)
+//   - HELIOS_STAMP_RESOURCES (default: "true")
+//   - HELIOS_RESOURCE_LABELS (default: "")
 //   - HELIOS_HEALTH_CHECK_ENABLED (default: "true")
 //   - HELIOS_HEALTH_CHECK_INTERVAL (default: "30s")
 //   - HELIOS_CPU_THRESHOLD (default: "90")
 //   - HELIOS_MEMORY_THRESHOLD (default: "90")
+//   - HELIOS_HEALTH_BREACH_COUNT (default: "3")
+//   - HELIOS_HEALTH_RECOVERY_COUNT (default: "2")
 //   - HELIOS_LOG_RETENTION_DAYS (default: "30")
+//   - HELIOS_LOG_RETENTION_INTERVAL (default: "24h")
+//   - HELIOS_DB_VACUUM (default: "false")
+//   - HELIOS_AUTO_PRUNE_ENABLED (default: "false")
+//   - HELIOS_AUTO_PRUNE_SCHEDULE (default: "24h")
+//   - HELIOS_AUTO_PRUNE_TARGETS (default: "images,containers")
+//   - HELIOS_AUTO_PRUNE_MIN_RECLAIMABLE_MB (default: "0")
+//   - HELIOS_AUTO_PRUNE_MIN_DISK_PERCENT (default: "0")
+//   - HELIOS_BUILD_MAX_CONTEXT_MB (default: "512")
+//   - HELIOS_PRUNE_PROTECT_IMAGES (default: "")
+//   - HELIOS_PRUNE_PROTECT_VOLUMES (default: "")
+//   - HELIOS_PRUNE_PROTECT_NETWORKS (default: "")
+//   - HELIOS_TIMEOUT_DEFAULT (default: "30s")
+//   - HELIOS_TIMEOUT_BULK (default: "2m")
+//   - HELIOS_TIMEOUT_PRUNE (default: "2m")
+//   - HELIOS_TIMEOUT_PULL (default: "5m")
+//   - HELIOS_WEBHOOK_URL (default: "")
+//   - HELIOS_WEBHOOK_TIMEOUT (default: "10s")
+//   - HELIOS_REGISTRY_CREDENTIALS_FILE (default: "")
+//   - HELIOS_REGISTRY_CREDENTIALS (default: "")
+//   - HELIOS_COST_CPU_HOUR (default: "0.04")
+//   - HELIOS_COST_GB_HOUR (default: "0.005")
+//   - HELIOS_COST_CURRENCY (default: "USD")
+//   - HELIOS_WS_READ_BUFFER (default: "1024")
+//   - HELIOS_WS_WRITE_BUFFER (default: "4096")
+//   - HELIOS_WS_COMPRESSION (default: "false")
+//   - HELIOS_STATS_SAMPLE_EVERY (default: "1")
+//   - HELIOS_NETWORK_ROUTE_CHECK (default: "off")
+//   - HELIOS_EXEC_MAX_OUTPUT_KB (default: "1024")
+//   - HELIOS_LOG_MAX_TAIL (default: "10000")
+//   - HELIOS_BULK_CONCURRENCY (default: "5")
+//   - HELIOS_CONFIG_WATCH_ENABLED (default: "false")
+//   - HELIOS_CONFIG_WATCH_INTERVAL (default: "2s")
+//   - HELIOS_CONFIG_WATCH_DEBOUNCE (default: "5s")
 //
 // Returns an error if validation fails.
 func Load() (*Config, error) {
+	loadedSources = make(map[string]Source)
+
 	cfg := &Config{
 		Server: ServerConfig{
-			Host: getEnv("HELIOS_SERVER_HOST", "0.0.0.0"),
-			Port: getEnv("HELIOS_SERVER_PORT", "8080"),
-			Mode: getEnv("HELIOS_SERVER_MODE", "debug"),
+			Host:       getEnv("HELIOS_SERVER_HOST", "0.0.0.0"),
+			Port:       getEnv("HELIOS_SERVER_PORT", "8080"),
+			Mode:       getEnv("HELIOS_SERVER_MODE", "debug"),
+			AdminToken: getEnv("HELIOS_ADMIN_TOKEN", ""),
+			ReadOnly:   getEnvBool("HELIOS_READ_ONLY", false),
 		},
 		Database: DatabaseConfig{
 			Path: getDBPath(),
 		},
 		Docker: DockerConfig{
-			Host: getEnv("HELIOS_DOCKER_HOST", "unix:///var/run/docker.sock"),
+			Host:                  getEnv("HELIOS_DOCKER_HOST", "unix:///var/run/docker.sock"),
+			ContainerNamePrefix:   getEnv("HELIOS_CONTAINER_NAME_PREFIX", ""),
+			MaxConcurrentInspects: getEnvInt("HELIOS_MAX_CONCURRENT_INSPECTS", 16),
+			ValidateBindMounts:    getEnvBool("HELIOS_VALIDATE_BIND_MOUNTS", true),
+			StampResources:        getEnvBool("HELIOS_STAMP_RESOURCES", true),
+			ResourceLabels:        getEnvList("HELIOS_RESOURCE_LABELS", nil),
 		},
 		HealthCheck: HealthCheckConfig{
 			Enabled:         getEnvBool("HELIOS_HEALTH_CHECK_ENABLED", true),
 			Interval:        getEnvDuration("HELIOS_HEALTH_CHECK_INTERVAL", 30*time.Second),
 			CPUThreshold:    getEnvFloat("HELIOS_CPU_THRESHOLD", 90.0),
 			MemoryThreshold: getEnvFloat("HELIOS_MEMORY_THRESHOLD", 90.0),
+			BreachCount:     getEnvInt("HELIOS_HEALTH_BREACH_COUNT", 3),
+			RecoveryCount:   getEnvInt("HELIOS_HEALTH_RECOVERY_COUNT", 2),
 		},
 		LogRetention: LogRetentionConfig{
-			Days: getEnvInt("HELIOS_LOG_RETENTION_DAYS", 30),
+			Days:     getEnvInt("HELIOS_LOG_RETENTION_DAYS", 30),
+			Interval: getEnvDuration("HELIOS_LOG_RETENTION_INTERVAL", 24*time.Hour),
+			Vacuum:   getEnvBool("HELIOS_DB_VACUUM", false),
+		},
+		AutoPrune: AutoPruneConfig{
+			Enabled:  getEnvBool("HELIOS_AUTO_PRUNE_ENABLED", false),
+			Interval: getEnvDuration("HELIOS_AUTO_PRUNE_SCHEDULE", 24*time.Hour),
+			Targets:  getEnvList("HELIOS_AUTO_PRUNE_TARGETS", []string{"images", "containers"}),
+
+			MinReclaimable: int64(getEnvInt("HELIOS_AUTO_PRUNE_MIN_RECLAIMABLE_MB", 0)) * 1024 * 1024,
+			MinDiskPercent: getEnvFloat("HELIOS_AUTO_PRUNE_MIN_DISK_PERCENT", 0),
+		},
+		Build: BuildConfig{
+			MaxContextSize: int64(getEnvInt("HELIOS_BUILD_MAX_CONTEXT_MB", 512)) * 1024 * 1024,
+		},
+		PruneProtect: PruneProtectConfig{
+			Images:   getEnvList("HELIOS_PRUNE_PROTECT_IMAGES", nil),
+			Volumes:  getEnvList("HELIOS_PRUNE_PROTECT_VOLUMES", nil),
+			Networks: getEnvList("HELIOS_PRUNE_PROTECT_NETWORKS", nil),
+		},
+		Timeouts: TimeoutConfig{
+			Default: getEnvDuration("HELIOS_TIMEOUT_DEFAULT", 30*time.Second),
+			Bulk:    getEnvDuration("HELIOS_TIMEOUT_BULK", 2*time.Minute),
+			Prune:   getEnvDuration("HELIOS_TIMEOUT_PRUNE", 2*time.Minute),
+			Pull:    getEnvDuration("HELIOS_TIMEOUT_PULL", 5*time.Minute),
+		},
+		Webhook: WebhookConfig{
+			URL:     getEnv("HELIOS_WEBHOOK_URL", ""),
+			Timeout: getEnvDuration("HELIOS_WEBHOOK_TIMEOUT", 10*time.Second),
+		},
+		Registry: RegistryConfig{
+			CredentialsFile: getEnv("HELIOS_REGISTRY_CREDENTIALS_FILE", ""),
+			Credentials:     getEnv("HELIOS_REGISTRY_CREDENTIALS", ""),
+		},
+		Cost: CostConfig{
+			CPUHour:  getEnvFloat("HELIOS_COST_CPU_HOUR", 0.04),
+			GBHour:   getEnvFloat("HELIOS_COST_GB_HOUR", 0.005),
+			Currency: getEnv("HELIOS_COST_CURRENCY", "USD"),
+		},
+		WebSocket: WebSocketConfig{
+			ReadBufferSize:  getEnvInt("HELIOS_WS_READ_BUFFER", 1024),
+			WriteBufferSize: getEnvInt("HELIOS_WS_WRITE_BUFFER", 4096),
+			Compression:     getEnvBool("HELIOS_WS_COMPRESSION", false),
+		},
+		Stats: StatsConfig{
+			SampleEvery: getEnvInt("HELIOS_STATS_SAMPLE_EVERY", 1),
+		},
+		Network: NetworkConfig{
+			RouteCheck: getEnv("HELIOS_NETWORK_ROUTE_CHECK", RouteCheckOff),
+		},
+		Exec: ExecConfig{
+			MaxOutput: int64(getEnvInt("HELIOS_EXEC_MAX_OUTPUT_KB", 1024)) * 1024,
+		},
+		Logs: LogsConfig{
+			MaxTail: getEnvInt("HELIOS_LOG_MAX_TAIL", 10000),
+		},
+		Bulk: BulkConfig{
+			Concurrency: getEnvInt("HELIOS_BULK_CONCURRENCY", 5),
+		},
+		ConfigWatch: ConfigWatchConfig{
+			Enabled:  getEnvBool("HELIOS_CONFIG_WATCH_ENABLED", false),
+			Interval: getEnvDuration("HELIOS_CONFIG_WATCH_INTERVAL", 2*time.Second),
+			Debounce: getEnvDuration("HELIOS_CONFIG_WATCH_DEBOUNCE", 5*time.Second),
 		},
 	}
 
+	cfg.sources = loadedSources
+
 	// Validate configuration
 	if err := validate(cfg); err != nil {
 		log.Printf("Configuration validation failed: %v", err)
@@ -95,33 +341,135 @@ func Load() (*Config, error) {
 
 	// Log loaded configuration
 	log.Printf("Configuration loaded:")
-	log.Printf("  Server: %s:%s (mode: %s)", cfg.Server.Host, cfg.Server.Port, cfg.Server.Mode)
+	log.Printf("  Server: %s:%s (mode: %s, admin endpoints: %v, read-only: %v)", cfg.Server.Host, cfg.Server.Port, cfg.Server.Mode, cfg.Server.AdminToken != "", cfg.Server.ReadOnly)
 	log.Printf("  Database: %s", cfg.Database.Path)
-	log.Printf("  Docker Host: %s", cfg.Docker.Host)
-	log.Printf("  Health Checks: enabled=%v, interval=%v, cpu_threshold=%.0f%%, memory_threshold=%.0f%%",
+	log.Printf("  Docker Host: %s (max concurrent inspects: %d, validate bind mounts: %v)", cfg.Docker.Host, cfg.Docker.MaxConcurrentInspects, cfg.Docker.ValidateBindMounts)
+	if cfg.Docker.ContainerNamePrefix != "" {
+		log.Printf("  Container Scope: name prefix %q", cfg.Docker.ContainerNamePrefix)
+	}
+	log.Printf("  Resource Labels: stamp=%v, extra=%v", cfg.Docker.StampResources, cfg.Docker.ResourceLabels)
+	log.Printf("  Health Checks: enabled=%v, interval=%v, cpu_threshold=%.0f%%, memory_threshold=%.0f%%, breach_count=%d, recovery_count=%d",
 		cfg.HealthCheck.Enabled, cfg.HealthCheck.Interval,
-		cfg.HealthCheck.CPUThreshold, cfg.HealthCheck.MemoryThreshold)
-	log.Printf("  Log Retention: %d days", cfg.LogRetention.Days)
+		cfg.HealthCheck.CPUThreshold, cfg.HealthCheck.MemoryThreshold,
+		cfg.HealthCheck.BreachCount, cfg.HealthCheck.RecoveryCount)
+	log.Printf("  Log Retention: %d days, interval=%v, vacuum=%v",
+		cfg.LogRetention.Days, cfg.LogRetention.Interval, cfg.LogRetention.Vacuum)
+	log.Printf("  Auto Prune: enabled=%v, interval=%v, targets=%v",
+		cfg.AutoPrune.Enabled, cfg.AutoPrune.Interval, cfg.AutoPrune.Targets)
+	if cfg.AutoPrune.MinReclaimable > 0 || cfg.AutoPrune.MinDiskPercent > 0 {
+		log.Printf("  Auto Prune Thresholds: min_reclaimable=%d bytes, min_disk_percent=%.0f%%",
+			cfg.AutoPrune.MinReclaimable, cfg.AutoPrune.MinDiskPercent)
+	}
+	log.Printf("  Stats: sample_every=%d cycles", cfg.Stats.SampleEvery)
+	log.Printf("  Network: route_check=%s", cfg.Network.RouteCheck)
+	log.Printf("  Exec: max_output=%d bytes", cfg.Exec.MaxOutput)
+	log.Printf("  Logs: max_tail=%d lines", cfg.Logs.MaxTail)
+	log.Printf("  Bulk Operations: concurrency=%d", cfg.Bulk.Concurrency)
+	log.Printf("  Config Watch: enabled=%v, interval=%v, debounce=%v",
+		cfg.ConfigWatch.Enabled, cfg.ConfigWatch.Interval, cfg.ConfigWatch.Debounce)
+	log.Printf("  Build: max_context_size=%d bytes", cfg.Build.MaxContextSize)
+	log.Printf("  Prune Protection: images=%v, volumes=%v, networks=%v",
+		cfg.PruneProtect.Images, cfg.PruneProtect.Volumes, cfg.PruneProtect.Networks)
+	log.Printf("  Timeouts: default=%v, bulk=%v, prune=%v, pull=%v",
+		cfg.Timeouts.Default, cfg.Timeouts.Bulk, cfg.Timeouts.Prune, cfg.Timeouts.Pull)
+	log.Printf("  Webhook: enabled=%v, timeout=%v", cfg.Webhook.URL != "", cfg.Webhook.Timeout)
+	log.Printf("  Registry Credentials: file=%q, inline=%v", cfg.Registry.CredentialsFile, cfg.Registry.Credentials != "")
+	log.Printf("  WebSocket: read_buffer=%d, write_buffer=%d, compression=%v",
+		cfg.WebSocket.ReadBufferSize, cfg.WebSocket.WriteBufferSize, cfg.WebSocket.Compression)
+	log.Printf("  Cost Model: cpu_hour=%g, gb_hour=%g, currency=%s", cfg.Cost.CPUHour, cfg.Cost.GBHour, cfg.Cost.Currency)
 
 	return cfg, nil
 }
 
 // validate checks if the configuration is valid.
 func validate(cfg *Config) error {
+	if err := ValidateHealthCheck(cfg.HealthCheck); err != nil {
+		return err
+	}
+	if cfg.Docker.MaxConcurrentInspects < 1 {
+		return errors.New("max concurrent inspects must be at least 1")
+	}
+	for _, label := range cfg.Docker.ResourceLabels {
+		if key, _, _ := strings.Cut(label, "="); strings.TrimSpace(key) == "" {
+			return errors.New("resource labels must be key=value pairs with a non-empty key")
+		}
+	}
+	if cfg.LogRetention.Days < 1 {
+		return errors.New("log retention days must be at least 1")
+	}
+	if cfg.LogRetention.Interval < time.Minute {
+		return errors.New("log retention interval must be at least 1 minute")
+	}
+	if cfg.AutoPrune.Enabled && cfg.AutoPrune.Interval < time.Minute {
+		return errors.New("auto prune schedule must be at least 1 minute")
+	}
+	if cfg.AutoPrune.MinReclaimable < 0 || cfg.AutoPrune.MinDiskPercent < 0 || cfg.AutoPrune.MinDiskPercent > 100 {
+		return errors.New("auto prune thresholds must not be negative and disk percent must be at most 100")
+	}
+	if cfg.Stats.SampleEvery < 1 {
+		return errors.New("stats sample interval must be at least 1 cycle")
+	}
+	switch cfg.Network.RouteCheck {
+	case RouteCheckOff, RouteCheckWarn, RouteCheckReject:
+	default:
+		return errors.New("network route check must be 'off', 'warn' or 'reject'")
+	}
+	if cfg.Exec.MaxOutput <= 0 {
+		return errors.New("exec max output must be at least 1 KB")
+	}
+	if cfg.Logs.MaxTail < 1 {
+		return errors.New("log max tail must be at least 1 line")
+	}
+	if cfg.Bulk.Concurrency < 1 {
+		return errors.New("bulk concurrency must be at least 1")
+	}
+	if cfg.ConfigWatch.Enabled && (cfg.ConfigWatch.Interval < 500*time.Millisecond || cfg.ConfigWatch.Debounce < 0) {
+		return errors.New("config watch interval must be at least 500ms and debounce must not be negative")
+	}
+	if cfg.Build.MaxContextSize < 1 {
+		return errors.New("build max context size must be at least 1 MB")
+	}
+	if cfg.Timeouts.Default < time.Second || cfg.Timeouts.Bulk < time.Second ||
+		cfg.Timeouts.Prune < time.Second || cfg.Timeouts.Pull < time.Second {
+		return errors.New("handler timeouts must be at least 1 second")
+	}
+	if cfg.Webhook.URL != "" && !strings.HasPrefix(cfg.Webhook.URL, "http://") && !strings.HasPrefix(cfg.Webhook.URL, "https://") {
+		return errors.New("webhook URL must start with http:// or https://")
+	}
+	if cfg.Webhook.Timeout < time.Second {
+		return errors.New("webhook timeout must be at least 1 second")
+	}
+	if cfg.WebSocket.ReadBufferSize < 256 || cfg.WebSocket.WriteBufferSize < 256 {
+		return errors.New("WebSocket buffer sizes must be at least 256 bytes")
+	}
+	if cfg.Cost.CPUHour < 0 || cfg.Cost.GBHour < 0 {
+		return errors.New("cost model prices must not be negative")
+	}
+	for _, target := range cfg.AutoPrune.Targets {
+		if target != "images" && target != "containers" {
+			return errors.New("auto prune targets must be 'images' or 'containers'")
+		}
+	}
+
+	return nil
+}
+
+// ValidateHealthCheck checks if a health check configuration is valid.
+// It is also used to validate settings changed at runtime.
+func ValidateHealthCheck(cfg HealthCheckConfig) error {
 	// Validate thresholds
-	if cfg.HealthCheck.CPUThreshold < 0 || cfg.HealthCheck.CPUThreshold > 100 {
+	if cfg.CPUThreshold < 0 || cfg.CPUThreshold > 100 {
 		return errors.New("CPU threshold must be between 0 and 100")
 	}
-	if cfg.HealthCheck.MemoryThreshold < 0 || cfg.HealthCheck.MemoryThreshold > 100 {
+	if cfg.MemoryThreshold < 0 || cfg.MemoryThreshold > 100 {
 		return errors.New("memory threshold must be between 0 and 100")
 	}
-	if cfg.HealthCheck.Interval < time.Second {
+	if cfg.Interval < time.Second {
 		return errors.New("health check interval must be at least 1 second")
 	}
-	if cfg.LogRetention.Days < 1 {
-		return errors.New("log retention days must be at least 1")
+	if cfg.BreachCount < 1 || cfg.RecoveryCount < 1 {
+		return errors.New("health breach and recovery counts must be at least 1")
 	}
-
 	return nil
 }
 
@@ -133,6 +481,7 @@ func validate(cfg *Config) error {
 func getDBPath() string {
 	// Check environment variable first
 	if path := os.Getenv("HELIOS_DB_PATH"); path != "" {
+		recordSource("HELIOS_DB_PATH", SourceEnv)
 		return path
 	}
 
@@ -148,6 +497,7 @@ func getDBPath() string {
 // getEnv retrieves an environment variable or returns a default value.
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
+		recordSource(key, SourceEnv)
 		return value
 	}
 	return defaultValue
@@ -157,6 +507,7 @@ func getEnv(key, defaultValue string) string {
 func getEnvInt(key string, defaultValue int) int {
 	if value := os.Getenv(key); value != "" {
 		if intVal, err := strconv.Atoi(value); err == nil {
+			recordSource(key, SourceEnv)
 			return intVal
 		}
 		log.Printf("Warning: invalid integer value for %s: %s, using default: %d", key, value, defaultValue)
@@ -168,6 +519,7 @@ func getEnvInt(key string, defaultValue int) int {
 func getEnvFloat(key string, defaultValue float64) float64 {
 	if value := os.Getenv(key); value != "" {
 		if floatVal, err := strconv.ParseFloat(value, 64); err == nil {
+			recordSource(key, SourceEnv)
 			return floatVal
 		}
 		log.Printf("Warning: invalid float value for %s: %s, using default: %.2f", key, value, defaultValue)
@@ -179,6 +531,7 @@ func getEnvFloat(key string, defaultValue float64) float64 {
 func getEnvBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		if boolVal, err := strconv.ParseBool(value); err == nil {
+			recordSource(key, SourceEnv)
 			return boolVal
 		}
 		log.Printf("Warning: invalid boolean value for %s: %s, using default: %v", key, value, defaultValue)
@@ -191,9 +544,28 @@ func getEnvBool(key string, defaultValue bool) bool {
 func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if duration, err := time.ParseDuration(value); err == nil {
+			recordSource(key, SourceEnv)
 			return duration
 		}
 		log.Printf("Warning: invalid duration value for %s: %s, using default: %v", key, value, defaultValue)
 	}
 	return defaultValue
 }
+
+// getEnvList retrieves a comma-separated list environment variable or returns a default value.
+// Entries are trimmed and empty entries are dropped.
+func getEnvList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	recordSource(key, SourceEnv)
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
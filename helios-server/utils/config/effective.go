@@ -0,0 +1,120 @@
+// Package config handles environment-based configuration for Helios.
+package config
+
+import "fmt"
+
+// Source describes where a configuration value came from.
+type Source string
+
+const (
+	SourceDefault Source = "default" // Built-in default; also used when an env value failed to parse
+	SourceEnv     Source = "env"     // HELIOS_* environment variable
+)
+
+// redacted replaces sensitive values in the effective configuration.
+const redacted = "********"
+
+// loadedSources collects value sources while Load runs.
+var loadedSources map[string]Source
+
+// recordSource notes that an environment variable supplied the value for key.
+func recordSource(key string, source Source) {
+	if loadedSources != nil {
+		loadedSources[key] = source
+	}
+}
+
+// Setting is one effective configuration value and where it came from.
+type Setting struct {
+	Key      string      `json:"key"` // Environment variable name
+	Section  string      `json:"section"`
+	Value    interface{} `json:"value"`
+	Source   Source      `json:"source"`
+	Redacted bool        `json:"redacted,omitempty"`
+}
+
+// Effective returns every configuration value as loaded at startup, in the order
+// they are documented on Load. Sensitive values that are set are redacted.
+// Durations are rendered as strings ("30s") and sizes in the unit their key names (MB or KB).
+func (c *Config) Effective() []Setting {
+	settings := []Setting{
+		{Key: "HELIOS_SERVER_HOST", Section: "server", Value: c.Server.Host},
+		{Key: "HELIOS_SERVER_PORT", Section: "server", Value: c.Server.Port},
+		{Key: "HELIOS_SERVER_MODE", Section: "server", Value: c.Server.Mode},
+		{Key: "HELIOS_ADMIN_TOKEN", Section: "server", Value: c.Server.AdminToken, Redacted: true},
+		{Key: "HELIOS_READ_ONLY", Section: "server", Value: c.Server.ReadOnly},
+		{Key: "HELIOS_DB_PATH", Section: "database", Value: c.Database.Path},
+		{Key: "HELIOS_DOCKER_HOST", Section: "docker", Value: c.Docker.Host},
+		{Key: "HELIOS_CONTAINER_NAME_PREFIX", Section: "docker", Value: c.Docker.ContainerNamePrefix},
+		{Key: "HELIOS_MAX_CONCURRENT_INSPECTS", Section: "docker", Value: c.Docker.MaxConcurrentInspects},
+		{Key: "HELIOS_VALIDATE_BIND_MOUNTS", Section: "docker", Value: c.Docker.ValidateBindMounts},
+		{Key: "HELIOS_STAMP_RESOURCES", Section: "docker", Value: c.Docker.StampResources},
+		{Key: "HELIOS_RESOURCE_LABELS", Section: "docker", Value: nonNil(c.Docker.ResourceLabels)},
+		{Key: "HELIOS_HEALTH_CHECK_ENABLED", Section: "health_check", Value: c.HealthCheck.Enabled},
+		{Key: "HELIOS_HEALTH_CHECK_INTERVAL", Section: "health_check", Value: c.HealthCheck.Interval.String()},
+		{Key: "HELIOS_CPU_THRESHOLD", Section: "health_check", Value: c.HealthCheck.CPUThreshold},
+		{Key: "HELIOS_MEMORY_THRESHOLD", Section: "health_check", Value: c.HealthCheck.MemoryThreshold},
+		{Key: "HELIOS_HEALTH_BREACH_COUNT", Section: "health_check", Value: c.HealthCheck.BreachCount},
+		{Key: "HELIOS_HEALTH_RECOVERY_COUNT", Section: "health_check", Value: c.HealthCheck.RecoveryCount},
+		{Key: "HELIOS_LOG_RETENTION_DAYS", Section: "log_retention", Value: c.LogRetention.Days},
+		{Key: "HELIOS_LOG_RETENTION_INTERVAL", Section: "log_retention", Value: c.LogRetention.Interval.String()},
+		{Key: "HELIOS_DB_VACUUM", Section: "log_retention", Value: c.LogRetention.Vacuum},
+		{Key: "HELIOS_AUTO_PRUNE_ENABLED", Section: "auto_prune", Value: c.AutoPrune.Enabled},
+		{Key: "HELIOS_AUTO_PRUNE_SCHEDULE", Section: "auto_prune", Value: c.AutoPrune.Interval.String()},
+		{Key: "HELIOS_AUTO_PRUNE_TARGETS", Section: "auto_prune", Value: nonNil(c.AutoPrune.Targets)},
+		{Key: "HELIOS_AUTO_PRUNE_MIN_RECLAIMABLE_MB", Section: "auto_prune", Value: c.AutoPrune.MinReclaimable / (1024 * 1024)},
+		{Key: "HELIOS_AUTO_PRUNE_MIN_DISK_PERCENT", Section: "auto_prune", Value: c.AutoPrune.MinDiskPercent},
+		{Key: "HELIOS_BUILD_MAX_CONTEXT_MB", Section: "build", Value: c.Build.MaxContextSize / (1024 * 1024)},
+		{Key: "HELIOS_PRUNE_PROTECT_IMAGES", Section: "prune_protect", Value: nonNil(c.PruneProtect.Images)},
+		{Key: "HELIOS_PRUNE_PROTECT_VOLUMES", Section: "prune_protect", Value: nonNil(c.PruneProtect.Volumes)},
+		{Key: "HELIOS_PRUNE_PROTECT_NETWORKS", Section: "prune_protect", Value: nonNil(c.PruneProtect.Networks)},
+		{Key: "HELIOS_TIMEOUT_DEFAULT", Section: "timeouts", Value: c.Timeouts.Default.String()},
+		{Key: "HELIOS_TIMEOUT_BULK", Section: "timeouts", Value: c.Timeouts.Bulk.String()},
+		{Key: "HELIOS_TIMEOUT_PRUNE", Section: "timeouts", Value: c.Timeouts.Prune.String()},
+		{Key: "HELIOS_TIMEOUT_PULL", Section: "timeouts", Value: c.Timeouts.Pull.String()},
+		{Key: "HELIOS_WEBHOOK_URL", Section: "webhook", Value: c.Webhook.URL, Redacted: true}, // URLs often embed tokens
+		{Key: "HELIOS_WEBHOOK_TIMEOUT", Section: "webhook", Value: c.Webhook.Timeout.String()},
+		{Key: "HELIOS_REGISTRY_CREDENTIALS_FILE", Section: "registry", Value: c.Registry.CredentialsFile},
+		{Key: "HELIOS_REGISTRY_CREDENTIALS", Section: "registry", Value: c.Registry.Credentials, Redacted: true},
+		{Key: "HELIOS_COST_CPU_HOUR", Section: "cost", Value: c.Cost.CPUHour},
+		{Key: "HELIOS_COST_GB_HOUR", Section: "cost", Value: c.Cost.GBHour},
+		{Key: "HELIOS_COST_CURRENCY", Section: "cost", Value: c.Cost.Currency},
+		{Key: "HELIOS_WS_READ_BUFFER", Section: "websocket", Value: c.WebSocket.ReadBufferSize},
+		{Key: "HELIOS_WS_WRITE_BUFFER", Section: "websocket", Value: c.WebSocket.WriteBufferSize},
+		{Key: "HELIOS_WS_COMPRESSION", Section: "websocket", Value: c.WebSocket.Compression},
+		{Key: "HELIOS_STATS_SAMPLE_EVERY", Section: "stats", Value: c.Stats.SampleEvery},
+		{Key: "HELIOS_NETWORK_ROUTE_CHECK", Section: "network", Value: c.Network.RouteCheck},
+		{Key: "HELIOS_EXEC_MAX_OUTPUT_KB", Section: "exec", Value: c.Exec.MaxOutput / 1024},
+		{Key: "HELIOS_LOG_MAX_TAIL", Section: "logs", Value: c.Logs.MaxTail},
+		{Key: "HELIOS_BULK_CONCURRENCY", Section: "bulk", Value: c.Bulk.Concurrency},
+		{Key: "HELIOS_CONFIG_WATCH_ENABLED", Section: "config_watch", Value: c.ConfigWatch.Enabled},
+		{Key: "HELIOS_CONFIG_WATCH_INTERVAL", Section: "config_watch", Value: c.ConfigWatch.Interval.String()},
+		{Key: "HELIOS_CONFIG_WATCH_DEBOUNCE", Section: "config_watch", Value: c.ConfigWatch.Debounce.String()},
+	}
+
+	for i := range settings {
+		s := &settings[i]
+		s.Source = SourceDefault
+		if source, ok := c.sources[s.Key]; ok {
+			s.Source = source
+		}
+		// Only redact values that are set, so an empty value still shows the feature is off
+		if s.Redacted {
+			if fmt.Sprint(s.Value) == "" {
+				s.Redacted = false
+			} else {
+				s.Value = redacted
+			}
+		}
+	}
+
+	return settings
+}
+
+// nonNil returns list, or an empty list when it is nil, so it encodes as [] rather than null.
+func nonNil(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return append([]string(nil), list...)
+}
@@ -3,20 +3,31 @@ package docker
 
 import (
 	"context"
+	"io"
 	"log"
+	"sync"
 
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/client"
 )
 
 // Client wraps the Docker SDK client with additional functionality.
+// Container inspects and stats calls share a semaphore so that the health checker,
+// stats cache and batch endpoints together never exceed the configured concurrency.
 type Client struct {
 	*client.Client
+	inspectSem chan struct{}
+
+	hostMemoryMu sync.Mutex
+	hostMemory   uint64 // Cached after the first successful Info call
 }
 
 // NewClient creates a new Docker client using environment variables.
 // It connects to the Docker daemon via the socket specified in DOCKER_HOST
 // or defaults to unix:///var/run/docker.sock
-func NewClient() (*Client, error) {
+// maxConcurrentInspects bounds concurrent ContainerInspect and ContainerStats calls.
+func NewClient(maxConcurrentInspects int) (*Client, error) {
 	cli, err := client.NewClientWithOpts(
 		client.FromEnv,
 		client.WithAPIVersionNegotiation(),
@@ -26,16 +37,101 @@ func NewClient() (*Client, error) {
 		return nil, err
 	}
 
-	log.Println("Docker client created successfully")
-	return &Client{Client: cli}, nil
+	if maxConcurrentInspects < 1 {
+		maxConcurrentInspects = 1
+	}
+
+	log.Printf("Docker client created successfully (max concurrent inspects: %d)", maxConcurrentInspects)
+	return &Client{Client: cli, inspectSem: make(chan struct{}, maxConcurrentInspects)}, nil
+}
+
+// MaxConcurrentInspects returns the size of the shared inspect/stats semaphore.
+func (c *Client) MaxConcurrentInspects() int {
+	return cap(c.inspectSem)
+}
+
+// HostMemory returns the daemon host's total memory as reported by Docker info.
+// It is fetched once and cached; failures are not cached, so the next call retries.
+func (c *Client) HostMemory(ctx context.Context) (uint64, error) {
+	c.hostMemoryMu.Lock()
+	defer c.hostMemoryMu.Unlock()
+
+	if c.hostMemory > 0 {
+		return c.hostMemory, nil
+	}
+	info, err := c.Client.Info(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if info.MemTotal > 0 {
+		c.hostMemory = uint64(info.MemTotal)
+	}
+	return c.hostMemory, nil
+}
+
+// acquireInspect waits for a free inspect slot or for ctx to be done.
+func (c *Client) acquireInspect(ctx context.Context) error {
+	select {
+	case c.inspectSem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// releaseInspect frees an inspect slot.
+func (c *Client) releaseInspect() {
+	<-c.inspectSem
+}
+
+// ContainerInspect inspects a container, waiting for a free inspect slot first.
+func (c *Client) ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error) {
+	if err := c.acquireInspect(ctx); err != nil {
+		return types.ContainerJSON{}, err
+	}
+	defer c.releaseInspect()
+	return c.Client.ContainerInspect(ctx, containerID)
+}
+
+// ContainerStats requests container stats, waiting for a free inspect slot first.
+// The slot is held until the response body is closed.
+func (c *Client) ContainerStats(ctx context.Context, containerID string, stream bool) (container.StatsResponseReader, error) {
+	if err := c.acquireInspect(ctx); err != nil {
+		return container.StatsResponseReader{}, err
+	}
+	resp, err := c.Client.ContainerStats(ctx, containerID, stream)
+	if err != nil {
+		c.releaseInspect()
+		return resp, err
+	}
+	resp.Body = &releasingReadCloser{ReadCloser: resp.Body, release: c.releaseInspect}
+	return resp, nil
+}
+
+// releasingReadCloser calls release exactly once when closed.
+type releasingReadCloser struct {
+	io.ReadCloser
+	release func()
+	closed  bool
+}
+
+// Close closes the underlying reader and releases the held slot.
+func (r *releasingReadCloser) Close() error {
+	err := r.ReadCloser.Close()
+	if !r.closed {
+		r.closed = true
+		r.release()
+	}
+	return err
 }
 
 // Ping verifies connection to the Docker daemon.
+// Connection failures are reported as ErrDockerUnavailable.
 func (c *Client) Ping(ctx context.Context) error {
 	_, err := c.Client.Ping(ctx)
 	if err != nil {
 		log.Printf("Docker daemon ping failed: %v", err)
-		return err
+		return ClassifyError(err)
 	}
 	return nil
 }
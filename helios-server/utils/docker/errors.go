@@ -0,0 +1,47 @@
+// Package docker provides a wrapper around the Docker SDK client.
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+
+	"github.com/docker/docker/client"
+)
+
+// ErrDockerUnavailable indicates that the Docker daemon could not be reached,
+// e.g. because the socket is missing or the connection was refused.
+var ErrDockerUnavailable = errors.New("docker daemon unavailable")
+
+// IsUnavailable reports whether err was caused by the Docker daemon being unreachable.
+func IsUnavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, ErrDockerUnavailable) || client.IsErrConnectionFailed(err) {
+		return true
+	}
+
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+
+	// A dial failure on the daemon socket (missing socket file, permission denied, etc.)
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+
+	return false
+}
+
+// ClassifyError wraps err with ErrDockerUnavailable when it was caused by the daemon
+// being unreachable. Other errors are returned unchanged.
+func ClassifyError(err error) error {
+	if err == nil || errors.Is(err, ErrDockerUnavailable) || !IsUnavailable(err) {
+		return err
+	}
+	return fmt.Errorf("%w: %v", ErrDockerUnavailable, err)
+}
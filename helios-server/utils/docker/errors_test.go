@@ -0,0 +1,70 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+)
+
+// missingSocketError returns the error a real API call fails with when the daemon
+// socket does not exist.
+func missingSocketError(t *testing.T) error {
+	t.Helper()
+	cli, err := client.NewClientWithOpts(
+		client.WithHost("unix://"+filepath.Join(t.TempDir(), "docker.sock")),
+		client.WithVersion("1.47"),
+	)
+	if err != nil {
+		t.Fatalf("NewClientWithOpts error = %v", err)
+	}
+	defer cli.Close()
+
+	_, err = cli.ContainerList(context.Background(), container.ListOptions{})
+	if err == nil {
+		t.Fatal("ContainerList succeeded without a daemon")
+	}
+	return err
+}
+
+func TestClassifyError(t *testing.T) {
+	listErr := missingSocketError(t)
+
+	tests := []struct {
+		name            string
+		err             error
+		wantUnavailable bool
+	}{
+		{name: "nil", err: nil},
+		{name: "missing socket on a list call", err: listErr, wantUnavailable: true},
+		{name: "wrapped by a service", err: fmt.Errorf("failed to list containers: %w", listErr), wantUnavailable: true},
+		{name: "connection refused", err: fmt.Errorf("inspect: %w", syscall.ECONNREFUSED), wantUnavailable: true},
+		{name: "connection reset", err: syscall.ECONNRESET, wantUnavailable: true},
+		{name: "dial failure", err: &net.OpError{Op: "dial", Net: "unix", Err: syscall.ENOENT}, wantUnavailable: true},
+		{name: "read failure", err: &net.OpError{Op: "read", Net: "unix", Err: syscall.EPIPE}},
+		{name: "already classified", err: ErrDockerUnavailable, wantUnavailable: true},
+		{name: "daemon error", err: errors.New("No such container: web")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsUnavailable(tt.err); got != tt.wantUnavailable {
+				t.Errorf("IsUnavailable(%v) = %v, want %v", tt.err, got, tt.wantUnavailable)
+			}
+
+			classified := ClassifyError(tt.err)
+			if got := errors.Is(classified, ErrDockerUnavailable); got != tt.wantUnavailable {
+				t.Errorf("ClassifyError(%v) matches ErrDockerUnavailable = %v, want %v", tt.err, got, tt.wantUnavailable)
+			}
+			if !tt.wantUnavailable && classified != tt.err {
+				t.Errorf("ClassifyError(%v) = %v, want the error unchanged", tt.err, classified)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// Package metrics tracks internal Helios counters for self-monitoring.
+package metrics
+
+import (
+	"sync"
+	"time"
+)
+
+// Health status gauge values.
+const (
+	HealthGaugeHealthy  = 0
+	HealthGaugeCritical = 1
+	HealthGaugeError    = 2
+)
+
+// HealthReading is the latest health check result of a container.
+type HealthReading struct {
+	ContainerID   string
+	ContainerName string
+	Status        string // Health check log status
+	CPUPercent    float64
+	MemoryUsage   uint64
+	MemoryLimit   uint64
+	NetworkRx     uint64
+	NetworkTx     uint64
+	CheckedAt     time.Time
+}
+
+var (
+	healthMu       sync.RWMutex
+	healthReadings = make(map[string]HealthReading) // Container ID -> latest reading
+)
+
+// RecordHealth stores the latest health check result of a container.
+func RecordHealth(reading HealthReading) {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	healthReadings[reading.ContainerID] = reading
+}
+
+// RetainHealth drops the readings of containers not in active, after a full health pass.
+func RetainHealth(active map[string]bool) {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	for id := range healthReadings {
+		if !active[id] {
+			delete(healthReadings, id)
+		}
+	}
+}
+
+// HealthGauge maps a health check status to its gauge value:
+// 0 healthy, 1 critical (resource_critical, unhealthy, oom_killed), 2 error.
+func HealthGauge(status string) int {
+	switch status {
+	case "healthy":
+		return HealthGaugeHealthy
+	case "error":
+		return HealthGaugeError
+	default:
+		return HealthGaugeCritical
+	}
+}
+
+// healthSnapshot returns a copy of the latest readings.
+func healthSnapshot() []HealthReading {
+	healthMu.RLock()
+	defer healthMu.RUnlock()
+	readings := make([]HealthReading, 0, len(healthReadings))
+	for _, reading := range healthReadings {
+		readings = append(readings, reading)
+	}
+	return readings
+}
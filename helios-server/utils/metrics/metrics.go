@@ -0,0 +1,108 @@
+// Package metrics tracks internal Helios counters for self-monitoring.
+package metrics
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	startedAt        = time.Now()
+	skippedRefreshes atomic.Int64
+	statsRefresh     durationStat
+	dbQueries        durationStat
+)
+
+// durationStat accumulates timing observations.
+type durationStat struct {
+	mu    sync.Mutex
+	count int64
+	total time.Duration
+	last  time.Duration
+	max   time.Duration
+}
+
+func (s *durationStat) observe(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.count++
+	s.total += d
+	s.last = d
+	if d > s.max {
+		s.max = d
+	}
+}
+
+func (s *durationStat) snapshot() DurationSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap := DurationSnapshot{
+		Count:  s.count,
+		LastMs: durationMs(s.last),
+		MaxMs:  durationMs(s.max),
+	}
+	if s.count > 0 {
+		snap.AvgMs = durationMs(s.total / time.Duration(s.count))
+	}
+	return snap
+}
+
+// DurationSnapshot summarizes timing observations in milliseconds.
+type DurationSnapshot struct {
+	Count  int64   `json:"count"`
+	LastMs float64 `json:"last_ms"`
+	AvgMs  float64 `json:"avg_ms"`
+	MaxMs  float64 `json:"max_ms"`
+}
+
+// Snapshot is a point-in-time view of Helios internals.
+type Snapshot struct {
+	UptimeSeconds    int64            `json:"uptime_seconds"`
+	Goroutines       int              `json:"goroutines"`
+	HeapAllocBytes   uint64           `json:"heap_alloc_bytes"`
+	ActiveWebSockets int64            `json:"active_websockets"`
+	ActiveSSEStreams int64            `json:"active_sse_streams"`
+	StatsRefresh     DurationSnapshot `json:"stats_refresh"`
+	SkippedRefreshes int64            `json:"stats_refresh_skipped"`
+	DBQueries        DurationSnapshot `json:"db_queries"`
+}
+
+// ObserveStatsRefresh records the duration of a stats cache refresh.
+func ObserveStatsRefresh(d time.Duration) {
+	statsRefresh.observe(d)
+}
+
+// SkipStatsRefresh counts a stats cache tick skipped because the previous refresh was still running.
+func SkipStatsRefresh() {
+	skippedRefreshes.Add(1)
+}
+
+// ObserveDBQuery records the latency of a database query started at start.
+// Intended for use as: defer metrics.ObserveDBQuery(time.Now())
+func ObserveDBQuery(start time.Time) {
+	dbQueries.observe(time.Since(start))
+}
+
+// Collect returns the current internal counters.
+func Collect() Snapshot {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	webSockets, sse := streamCounts()
+
+	return Snapshot{
+		UptimeSeconds:    int64(time.Since(startedAt).Seconds()),
+		Goroutines:       runtime.NumGoroutine(),
+		HeapAllocBytes:   mem.HeapAlloc,
+		ActiveWebSockets: webSockets,
+		ActiveSSEStreams: sse,
+		StatsRefresh:     statsRefresh.snapshot(),
+		SkippedRefreshes: skippedRefreshes.Load(),
+		DBQueries:        dbQueries.snapshot(),
+	}
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
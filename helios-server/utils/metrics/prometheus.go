@@ -0,0 +1,100 @@
+// Package metrics tracks internal Helios counters for self-monitoring.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// PrometheusContentType is the content type of the Prometheus text exposition format.
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// labelEscaper escapes label values for the text exposition format.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// promWriter writes metric families, remembering the first write error.
+type promWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (p *promWriter) family(name, help, kind string) {
+	p.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func (p *promWriter) sample(name, labels string, value float64) {
+	if labels != "" {
+		p.printf("%s{%s} %g\n", name, labels, value)
+	} else {
+		p.printf("%s %g\n", name, value)
+	}
+}
+
+func (p *promWriter) printf(format string, args ...interface{}) {
+	if p.err == nil {
+		_, p.err = fmt.Fprintf(p.w, format, args...)
+	}
+}
+
+// WritePrometheus writes Helios internals and the latest health check result of
+// every container in the Prometheus text exposition format.
+func WritePrometheus(w io.Writer) error {
+	p := &promWriter{w: w}
+	snap := Collect()
+
+	p.family("helios_uptime_seconds", "Seconds since Helios started.", "gauge")
+	p.sample("helios_uptime_seconds", "", float64(snap.UptimeSeconds))
+	p.family("helios_goroutines", "Number of goroutines.", "gauge")
+	p.sample("helios_goroutines", "", float64(snap.Goroutines))
+	p.family("helios_heap_alloc_bytes", "Allocated heap memory.", "gauge")
+	p.sample("helios_heap_alloc_bytes", "", float64(snap.HeapAllocBytes))
+	p.family("helios_active_websockets", "Open WebSocket connections.", "gauge")
+	p.sample("helios_active_websockets", "", float64(snap.ActiveWebSockets))
+	p.family("helios_active_sse_streams", "Open Server-Sent Events streams.", "gauge")
+	p.sample("helios_active_sse_streams", "", float64(snap.ActiveSSEStreams))
+	p.family("helios_stats_refresh_total", "Stats cache refreshes completed.", "counter")
+	p.sample("helios_stats_refresh_total", "", float64(snap.StatsRefresh.Count))
+	p.family("helios_stats_refresh_skipped_total", "Stats cache refreshes skipped because the previous one was still running.", "counter")
+	p.sample("helios_stats_refresh_skipped_total", "", float64(snap.SkippedRefreshes))
+	p.family("helios_db_queries_total", "Database queries executed.", "counter")
+	p.sample("helios_db_queries_total", "", float64(snap.DBQueries.Count))
+
+	readings := healthSnapshot()
+	sort.Slice(readings, func(i, j int) bool { return readings[i].ContainerName < readings[j].ContainerName })
+
+	type gauge struct {
+		name, help string
+		value      func(HealthReading) float64
+	}
+	gauges := []gauge{
+		{"helios_container_health_status", "Latest health assessment: 0 healthy, 1 critical, 2 error.",
+			func(r HealthReading) float64 { return float64(HealthGauge(r.Status)) }},
+		{"helios_container_cpu_percent", "CPU usage at the latest health check.",
+			func(r HealthReading) float64 { return r.CPUPercent }},
+		{"helios_container_memory_usage_bytes", "Memory usage at the latest health check.",
+			func(r HealthReading) float64 { return float64(r.MemoryUsage) }},
+		{"helios_container_memory_limit_bytes", "Memory limit at the latest health check.",
+			func(r HealthReading) float64 { return float64(r.MemoryLimit) }},
+		{"helios_container_network_rx_bytes", "Bytes received at the latest health check.",
+			func(r HealthReading) float64 { return float64(r.NetworkRx) }},
+		{"helios_container_network_tx_bytes", "Bytes transmitted at the latest health check.",
+			func(r HealthReading) float64 { return float64(r.NetworkTx) }},
+		{"helios_container_health_checked_timestamp_seconds", "Unix time of the latest health check.",
+			func(r HealthReading) float64 { return float64(r.CheckedAt.Unix()) }},
+	}
+
+	for _, g := range gauges {
+		if len(readings) == 0 {
+			break
+		}
+		p.family(g.name, g.help, "gauge")
+		for _, r := range readings {
+			labels := fmt.Sprintf(`container="%s",status="%s"`, labelEscaper.Replace(r.ContainerName), labelEscaper.Replace(r.Status))
+			p.sample(g.name, labels, g.value(r))
+		}
+	}
+
+	return p.err
+}
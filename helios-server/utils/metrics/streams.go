@@ -0,0 +1,117 @@
+// Package metrics tracks internal Helios counters for self-monitoring.
+package metrics
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+// StreamKind identifies the protocol of a long-lived client stream.
+type StreamKind int
+
+const (
+	StreamWebSocket StreamKind = iota // WebSocket connection
+	StreamSSE                         // Server-Sent Events stream
+)
+
+// streams tracks open WebSocket and SSE streams, both for the counts reported by
+// Collect and so they can be ended cleanly on shutdown; server.Shutdown neither waits
+// for hijacked WebSocket connections nor tells SSE clients why their stream ends.
+var streams = &streamRegistry{streams: make(map[int]trackedStream)}
+
+// trackedStream is an open stream and the function that tells it to end.
+type trackedStream struct {
+	kind   StreamKind
+	notify func()
+}
+
+// streamRegistry holds the open streams.
+type streamRegistry struct {
+	mu       sync.Mutex
+	nextID   int
+	streams  map[int]trackedStream
+	draining bool
+	drained  chan struct{} // Closed when the last stream ends while draining
+}
+
+// TrackStream registers an open stream; notify is called once when DrainStreams starts.
+// Streams opened while draining are notified immediately and not counted. Call the
+// returned function when the stream ends.
+func TrackStream(kind StreamKind, notify func()) func() {
+	r := streams
+	r.mu.Lock()
+	if r.draining {
+		r.mu.Unlock()
+		notify()
+		return func() {}
+	}
+	id := r.nextID
+	r.nextID++
+	r.streams[id] = trackedStream{kind: kind, notify: notify}
+	r.mu.Unlock()
+
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.streams, id)
+		if r.drained != nil && len(r.streams) == 0 {
+			close(r.drained)
+			r.drained = nil
+		}
+	}
+}
+
+// ActiveStreams returns the number of open WebSocket and SSE streams.
+func ActiveStreams() int {
+	streams.mu.Lock()
+	defer streams.mu.Unlock()
+	return len(streams.streams)
+}
+
+// streamCounts returns the number of open streams of each kind.
+func streamCounts() (webSockets, sse int64) {
+	streams.mu.Lock()
+	defer streams.mu.Unlock()
+	for _, s := range streams.streams {
+		switch s.kind {
+		case StreamWebSocket:
+			webSockets++
+		case StreamSSE:
+			sse++
+		}
+	}
+	return webSockets, sse
+}
+
+// DrainStreams tells every active stream that the server is shutting down and waits
+// until they have ended or ctx is done. It returns the number of streams still open.
+// Call it before server.Shutdown.
+func DrainStreams(ctx context.Context) int {
+	r := streams
+	r.mu.Lock()
+	r.draining = true
+	if len(r.streams) == 0 {
+		r.mu.Unlock()
+		return 0
+	}
+	drained := make(chan struct{})
+	r.drained = drained
+	notifiers := make([]func(), 0, len(r.streams))
+	for _, s := range r.streams {
+		notifiers = append(notifiers, s.notify)
+	}
+	r.mu.Unlock()
+
+	log.Printf("Closing %d active streams", len(notifiers))
+	for _, notify := range notifiers {
+		go notify()
+	}
+
+	select {
+	case <-drained:
+		return 0
+	case <-ctx.Done():
+		return ActiveStreams()
+	}
+}
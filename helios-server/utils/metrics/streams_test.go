@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestStreamRegistry(t *testing.T) {
+	tests := []struct {
+		name          string
+		kinds         []StreamKind
+		endOnNotify   bool
+		wantWS        int64
+		wantSSE       int64
+		wantRemaining int
+	}{
+		{name: "no streams"},
+		{name: "streams end when notified", kinds: []StreamKind{StreamWebSocket, StreamWebSocket, StreamSSE}, endOnNotify: true, wantWS: 2, wantSSE: 1},
+		{name: "streams ignore the notification", kinds: []StreamKind{StreamWebSocket, StreamSSE}, wantWS: 1, wantSSE: 1, wantRemaining: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			streams = &streamRegistry{streams: make(map[int]trackedStream)}
+
+			for _, kind := range tt.kinds {
+				var done func()
+				done = TrackStream(kind, func() {
+					if tt.endOnNotify {
+						done()
+					}
+				})
+			}
+
+			snap := Collect()
+			if snap.ActiveWebSockets != tt.wantWS || snap.ActiveSSEStreams != tt.wantSSE {
+				t.Errorf("Collect() streams = %d WebSocket, %d SSE, want %d, %d",
+					snap.ActiveWebSockets, snap.ActiveSSEStreams, tt.wantWS, tt.wantSSE)
+			}
+			if got := ActiveStreams(); got != len(tt.kinds) {
+				t.Errorf("ActiveStreams() = %d, want %d", got, len(tt.kinds))
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			if got := DrainStreams(ctx); got != tt.wantRemaining {
+				t.Errorf("DrainStreams() = %d, want %d", got, tt.wantRemaining)
+			}
+
+			notified := false
+			TrackStream(StreamSSE, func() { notified = true })()
+			if !notified {
+				t.Error("stream opened while draining was not notified")
+			}
+		})
+	}
+}
@@ -1,20 +1,37 @@
 package statsutil
 
 import (
+	"runtime"
+	"strings"
+
 	"github.com/docker/docker/api/types/container"
 )
 
 // CalculateCPUPercent calculates the CPU usage percentage from Docker stats.
+// 100% is one fully used CPU, so a busy multi-core container can exceed 100%.
 func CalculateCPUPercent(stats *container.StatsResponse) float64 {
 	cpuDelta := float64(stats.CPUStats.CPUUsage.TotalUsage - stats.PreCPUStats.CPUUsage.TotalUsage)
 	systemDelta := float64(stats.CPUStats.SystemUsage - stats.PreCPUStats.SystemUsage)
 
 	if systemDelta > 0.0 && cpuDelta > 0.0 {
-		return (cpuDelta / systemDelta) * float64(len(stats.CPUStats.CPUUsage.PercpuUsage)) * 100.0
+		return (cpuDelta / systemDelta) * float64(onlineCPUs(stats)) * 100.0
 	}
 	return 0.0
 }
 
+// onlineCPUs returns the number of CPUs the stats were measured across. cgroup v2
+// hosts report no per-CPU usage, so OnlineCPUs is preferred, then the per-CPU
+// usage count (older daemons), then the CPU count of the host Helios runs on.
+func onlineCPUs(stats *container.StatsResponse) int {
+	if stats.CPUStats.OnlineCPUs > 0 {
+		return int(stats.CPUStats.OnlineCPUs)
+	}
+	if n := len(stats.CPUStats.CPUUsage.PercpuUsage); n > 0 {
+		return n
+	}
+	return runtime.NumCPU()
+}
+
 // GetNetworkRx returns total received bytes across all network interfaces.
 func GetNetworkRx(stats *container.StatsResponse) uint64 {
 	var total uint64
@@ -33,24 +50,148 @@ func GetNetworkTx(stats *container.StatsResponse) uint64 {
 	return total
 }
 
+// IsMemoryUnlimited reports whether a container's memory limit means it has none of its
+// own. Containers started without a memory limit report the host's total memory (or,
+// on some cgroup v1 hosts, an even larger sentinel) as their limit. A hostMemory of 0
+// means it is unknown, in which case only a zero limit counts as unlimited.
+func IsMemoryUnlimited(limit, hostMemory uint64) bool {
+	return limit == 0 || (hostMemory > 0 && limit >= hostMemory)
+}
+
+// MemoryPercent returns usage as a percentage of the container's memory limit.
+// It returns false for containers without a limit of their own, whose usage relative
+// to the host would be misleading.
+func MemoryPercent(usage, limit, hostMemory uint64) (float64, bool) {
+	if IsMemoryUnlimited(limit, hostMemory) {
+		return 0, false
+	}
+	return float64(usage) / float64(limit) * 100.0, true
+}
+
+// CgroupVersion identifies the cgroup hierarchy a stats response was collected from.
+type CgroupVersion int
+
+const (
+	// CgroupUnknown means the version could not be determined from the response.
+	CgroupUnknown CgroupVersion = iota
+	// CgroupV1 is the legacy per-controller hierarchy.
+	CgroupV1
+	// CgroupV2 is the unified hierarchy used by modern distributions.
+	CgroupV2
+)
+
+// DetectCgroupVersion inspects the memory.stat keys in the response to determine
+// which cgroup hierarchy the daemon is reporting from. cgroup v1 exposes keys such as
+// "total_cache" and "rss", while cgroup v2 exposes "anon" and "file".
+func DetectCgroupVersion(stats *container.StatsResponse) CgroupVersion {
+	memStats := stats.MemoryStats.Stats
+	if memStats == nil {
+		return CgroupUnknown
+	}
+
+	if _, ok := memStats["anon"]; ok {
+		return CgroupV2
+	}
+	if _, ok := memStats["file"]; ok {
+		return CgroupV2
+	}
+	if _, ok := memStats["total_cache"]; ok {
+		return CgroupV1
+	}
+	if _, ok := memStats["rss"]; ok {
+		return CgroupV1
+	}
+
+	return CgroupUnknown
+}
+
+// MemoryBreakdown details where a container's memory is going.
+// Fields that the cgroup version does not report are left at zero.
+type MemoryBreakdown struct {
+	Swap  uint64 // Swap usage (cgroup v1 only)
+	RSS   uint64 // Anonymous resident memory ("rss" on v1, "anon" on v2)
+	Cache uint64 // Page cache ("cache" on v1, "file" on v2)
+	Anon  uint64 // Anonymous memory (cgroup v2 only)
+	File  uint64 // File-backed memory (cgroup v2 only)
+}
+
+// GetMemoryBreakdown extracts swap, RSS and cache usage from memory.stat.
+// cgroup v1 hierarchical "total_*" keys are preferred over the per-cgroup ones.
+func GetMemoryBreakdown(stats *container.StatsResponse) MemoryBreakdown {
+	memStats := stats.MemoryStats.Stats
+	var breakdown MemoryBreakdown
+
+	switch DetectCgroupVersion(stats) {
+	case CgroupV1:
+		breakdown.Swap = memStat(memStats, "total_swap", "swap")
+		breakdown.RSS = memStat(memStats, "total_rss", "rss")
+		breakdown.Cache = memStat(memStats, "total_cache", "cache")
+	case CgroupV2:
+		breakdown.Anon = memStat(memStats, "anon")
+		breakdown.File = memStat(memStats, "file")
+		breakdown.RSS = breakdown.Anon
+		breakdown.Cache = breakdown.File
+	}
+
+	return breakdown
+}
+
+// memStat returns the first of the given memory.stat keys that is present, or 0.
+func memStat(memStats map[string]uint64, keys ...string) uint64 {
+	for _, key := range keys {
+		if value, ok := memStats[key]; ok {
+			return value
+		}
+	}
+	return 0
+}
+
 // GetBlockRead returns total bytes read from block devices.
+// Both cgroup versions use IoServiceBytesRecursive: the daemon reports "Read" entries
+// on cgroup v1 and fills the same list with lowercase "read" entries from io.stat on
+// cgroup v2, which the baseline already matched, so there is no separate v2 source to
+// read. Falls back to Windows storage stats when no blkio entries are present. Use
+// BlockIOAvailable to tell a real zero from a cgroup v2 host that reported nothing.
 func GetBlockRead(stats *container.StatsResponse) uint64 {
-	var total uint64
-	for _, bioEntry := range stats.BlkioStats.IoServiceBytesRecursive {
-		if bioEntry.Op == "Read" || bioEntry.Op == "read" {
-			total += bioEntry.Value
-		}
+	if total, ok := sumBlkioOp(stats.BlkioStats.IoServiceBytesRecursive, "read"); ok {
+		return total
 	}
-	return total
+	return stats.StorageStats.ReadSizeBytes
 }
 
 // GetBlockWrite returns total bytes written to block devices.
+// See GetBlockRead for the sources consulted.
 func GetBlockWrite(stats *container.StatsResponse) uint64 {
+	if total, ok := sumBlkioOp(stats.BlkioStats.IoServiceBytesRecursive, "write"); ok {
+		return total
+	}
+	return stats.StorageStats.WriteSizeBytes
+}
+
+// BlockIOAvailable reports whether the response carries block IO figures. On cgroup v2
+// the io.stat list is empty when the io controller is not enabled for the container's
+// cgroup (common with rootless Docker), so zero totals there mean unknown, not idle.
+func BlockIOAvailable(stats *container.StatsResponse) bool {
+	if len(stats.BlkioStats.IoServiceBytesRecursive) > 0 {
+		return true
+	}
+	if stats.StorageStats.ReadSizeBytes > 0 || stats.StorageStats.WriteSizeBytes > 0 {
+		return true
+	}
+	return DetectCgroupVersion(stats) != CgroupV2
+}
+
+// sumBlkioOp totals the entries matching op case-insensitively, which covers both
+// the capitalized cgroup v1 operation names and the lowercase cgroup v2 ones.
+// The boolean result reports whether any matching entry was found.
+func sumBlkioOp(entries []container.BlkioStatEntry, op string) (uint64, bool) {
 	var total uint64
-	for _, bioEntry := range stats.BlkioStats.IoServiceBytesRecursive {
-		if bioEntry.Op == "Write" || bioEntry.Op == "write" {
+	found := false
+	for _, bioEntry := range entries {
+		if strings.EqualFold(bioEntry.Op, op) {
 			total += bioEntry.Value
+			found = true
 		}
 	}
-	return total
+	return total, found
 }
@@ -0,0 +1,169 @@
+package statsutil
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// cpuStats builds a stats response with the given CPU and system usage deltas.
+func cpuStats(cpuDelta, systemDelta uint64, onlineCPUs uint32, percpu []uint64) *container.StatsResponse {
+	stats := &container.StatsResponse{}
+	stats.PreCPUStats.CPUUsage.TotalUsage = 1_000_000
+	stats.PreCPUStats.SystemUsage = 10_000_000
+	stats.CPUStats.CPUUsage.TotalUsage = stats.PreCPUStats.CPUUsage.TotalUsage + cpuDelta
+	stats.CPUStats.SystemUsage = stats.PreCPUStats.SystemUsage + systemDelta
+	stats.CPUStats.OnlineCPUs = onlineCPUs
+	stats.CPUStats.CPUUsage.PercpuUsage = percpu
+	return stats
+}
+
+func TestCalculateCPUPercent(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats *container.StatsResponse
+		want  float64
+	}{
+		// cgroup v2 daemons report no per-CPU usage, only OnlineCPUs
+		{name: "cgroup v2", stats: cpuStats(250, 1000, 4, nil), want: 100},
+		{name: "cgroup v1 per-CPU usage", stats: cpuStats(250, 1000, 0, []uint64{1, 2}), want: 50},
+		{name: "online CPUs preferred", stats: cpuStats(250, 1000, 8, []uint64{1, 2}), want: 200},
+		{name: "host CPU fallback", stats: cpuStats(250, 1000, 0, nil), want: 25 * float64(runtime.NumCPU())},
+		{name: "no system delta", stats: cpuStats(250, 0, 4, nil), want: 0},
+		{name: "no CPU delta", stats: cpuStats(0, 1000, 4, nil), want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CalculateCPUPercent(tt.stats); got != tt.want {
+				t.Errorf("CalculateCPUPercent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryPercent(t *testing.T) {
+	const host = 16 << 30
+
+	tests := []struct {
+		name          string
+		usage         uint64
+		limit         uint64
+		hostMemory    uint64
+		want          float64
+		wantUnlimited bool
+	}{
+		{name: "normal limit", usage: 256 << 20, limit: 1 << 30, hostMemory: host, want: 25},
+		{name: "limit equals host", usage: 256 << 20, limit: host, hostMemory: host, wantUnlimited: true},
+		{name: "limit above host", usage: 256 << 20, limit: 1 << 62, hostMemory: host, wantUnlimited: true},
+		{name: "no limit", usage: 256 << 20, limit: 0, hostMemory: host, wantUnlimited: true},
+		{name: "host memory unknown", usage: 256 << 20, limit: host, hostMemory: 0, want: 1.5625},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsMemoryUnlimited(tt.limit, tt.hostMemory); got != tt.wantUnlimited {
+				t.Errorf("IsMemoryUnlimited(%d, %d) = %v, want %v", tt.limit, tt.hostMemory, got, tt.wantUnlimited)
+			}
+			got, limited := MemoryPercent(tt.usage, tt.limit, tt.hostMemory)
+			if got != tt.want || limited == tt.wantUnlimited {
+				t.Errorf("MemoryPercent(%d, %d, %d) = %v, %v, want %v, %v", tt.usage, tt.limit, tt.hostMemory, got, limited, tt.want, !tt.wantUnlimited)
+			}
+		})
+	}
+}
+
+func TestGetMemoryBreakdown(t *testing.T) {
+	tests := []struct {
+		name        string
+		memStats    map[string]uint64
+		wantVersion CgroupVersion
+		want        MemoryBreakdown
+	}{
+		{name: "cgroup v1", memStats: map[string]uint64{"cache": 10, "rss": 20, "total_cache": 100, "total_rss": 200, "total_swap": 50}, wantVersion: CgroupV1, want: MemoryBreakdown{Swap: 50, RSS: 200, Cache: 100}},
+		{name: "cgroup v1 without hierarchy", memStats: map[string]uint64{"cache": 10, "rss": 20}, wantVersion: CgroupV1, want: MemoryBreakdown{RSS: 20, Cache: 10}},
+		{name: "cgroup v2", memStats: map[string]uint64{"anon": 300, "file": 400}, wantVersion: CgroupV2, want: MemoryBreakdown{RSS: 300, Cache: 400, Anon: 300, File: 400}},
+		{name: "no memory.stat", memStats: nil, wantVersion: CgroupUnknown},
+		{name: "unrecognized keys", memStats: map[string]uint64{"pgfault": 1}, wantVersion: CgroupUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := &container.StatsResponse{}
+			stats.MemoryStats.Stats = tt.memStats
+
+			if got := DetectCgroupVersion(stats); got != tt.wantVersion {
+				t.Errorf("DetectCgroupVersion() = %v, want %v", got, tt.wantVersion)
+			}
+			if got := GetMemoryBreakdown(stats); got != tt.want {
+				t.Errorf("GetMemoryBreakdown() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetBlockIO(t *testing.T) {
+	tests := []struct {
+		name        string
+		entries     []container.BlkioStatEntry
+		storage     container.StorageStats
+		memStats    map[string]uint64
+		wantRead    uint64
+		wantWrite   uint64
+		wantUnknown bool
+	}{
+		{
+			name: "cgroup v1",
+			entries: []container.BlkioStatEntry{
+				{Major: 8, Op: "Read", Value: 100}, {Major: 8, Op: "Write", Value: 10},
+				{Major: 8, Op: "Sync", Value: 110}, {Major: 8, Op: "Total", Value: 110},
+				{Major: 9, Op: "Read", Value: 5},
+			},
+			wantRead:  105,
+			wantWrite: 10,
+		},
+		{
+			name:      "cgroup v2",
+			entries:   []container.BlkioStatEntry{{Major: 8, Op: "read", Value: 200}, {Major: 8, Op: "write", Value: 20}},
+			memStats:  map[string]uint64{"anon": 1, "file": 1},
+			wantRead:  200,
+			wantWrite: 20,
+		},
+		{
+			name:        "cgroup v2 without io.stat entries",
+			memStats:    map[string]uint64{"anon": 1, "file": 1},
+			wantUnknown: true,
+		},
+		{
+			name:     "cgroup v1 idle",
+			memStats: map[string]uint64{"total_cache": 0, "rss": 0},
+		},
+		{
+			name:      "windows storage stats",
+			storage:   container.StorageStats{ReadSizeBytes: 300, WriteSizeBytes: 30},
+			wantRead:  300,
+			wantWrite: 30,
+		},
+		{name: "no block IO"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := &container.StatsResponse{}
+			stats.BlkioStats.IoServiceBytesRecursive = tt.entries
+			stats.StorageStats = tt.storage
+			stats.MemoryStats.Stats = tt.memStats
+
+			if got := GetBlockRead(stats); got != tt.wantRead {
+				t.Errorf("GetBlockRead() = %d, want %d", got, tt.wantRead)
+			}
+			if got := GetBlockWrite(stats); got != tt.wantWrite {
+				t.Errorf("GetBlockWrite() = %d, want %d", got, tt.wantWrite)
+			}
+			if got := !BlockIOAvailable(stats); got != tt.wantUnknown {
+				t.Errorf("BlockIOAvailable() = %v, want %v", !got, !tt.wantUnknown)
+			}
+		})
+	}
+}
@@ -237,7 +237,7 @@ export default function ContainerDetail() {
             <span className="text-sm text-gray-400">Memory</span>
           </div>
           <p className="text-xl font-bold text-white">
-            {container.stats?.memory_percent?.toFixed(1) || 0}%
+            {container.stats?.memory_unlimited ? 'N/A' : `${container.stats?.memory_percent?.toFixed(1) || 0}%`}
           </p>
           <p className="text-xs text-gray-400 mt-1">
             {formatBytes(container.stats?.memory_usage || 0)} / {formatBytes(container.stats?.memory_limit || 0)}
@@ -491,11 +491,11 @@ export default function Containers() {
                     <td className="px-6 py-4 whitespace-nowrap text-sm">
                       {container.state === 'running' ? (
                         <div className="flex flex-col">
-                          <span className={`font-mono text-xs ${container.stats && container.stats.memory_percent > 80 ? 'text-red-400' : 'text-gray-400'}`}>
+                          <span className={`font-mono text-xs ${container.stats && (container.stats.memory_percent ?? 0) > 80 ? 'text-red-400' : 'text-gray-400'}`}>
                             {container.stats ? `${formatBytes(container.stats.memory_usage)} / ${formatBytes(container.stats.memory_limit)}` : '-'}
                           </span>
-                          <span className={`font-mono text-xs ${container.stats && container.stats.memory_percent > 80 ? 'text-red-400' : 'text-gray-500'}`}>
-                            {container.stats ? `${container.stats.memory_percent.toFixed(1)}%` : ''}
+                          <span className={`font-mono text-xs ${container.stats && (container.stats.memory_percent ?? 0) > 80 ? 'text-red-400' : 'text-gray-500'}`}>
+                            {container.stats ? (container.stats.memory_percent === null ? 'N/A (no limit)' : `${container.stats.memory_percent.toFixed(1)}%`) : ''}
                           </span>
                         </div>
                       ) : (
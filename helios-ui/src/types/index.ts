@@ -15,11 +15,13 @@ export interface ContainerStats {
   cpu_percent: number;
   memory_usage: number;
   memory_limit: number;
-  memory_percent: number;
+  memory_percent: number | null; // null when the container has no memory limit
+  memory_unlimited?: boolean;
   network_rx: number;
   network_tx: number;
   block_read: number;
   block_write: number;
+  block_unknown?: boolean; // cgroup v2 reported no io.stat entries
 }
 
 export interface Port {